@@ -13,8 +13,10 @@ import (
 
 	"bff-go-mvp/internal/config"
 	_ "bff-go-mvp/internal/docs" // swagger docs
+	"bff-go-mvp/internal/health"
 	"bff-go-mvp/internal/logger"
 	"bff-go-mvp/internal/router"
+	"bff-go-mvp/internal/tracing"
 )
 
 // @title EV Charging BFF API
@@ -22,6 +24,10 @@ import (
 // @description Backend-for-frontend for EV charging flows.
 // @BasePath /
 // @schemes http
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Bearer token: "Bearer <jwt>"
 func main() {
 	// Initialize logger
 	zapLogger, err := logger.NewLogger(os.Getenv("ENV"))
@@ -32,9 +38,25 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		zapLogger.Fatal("Invalid configuration", zap.Error(err))
+	}
+
+	// Tracing is installed before the router so that its clients pick up the
+	// global tracer provider and propagator.
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		zapLogger.Fatal("Failed to initialise tracing", zap.Error(err))
+	}
+	zapLogger.Info("Tracing configured", zap.String("exporter", cfg.Tracing.Exporter))
 
 	// Setup router with all endpoints
-	r := router.New(cfg, zapLogger)
+	// background stops the backends' background work, such as the refund
+	// sweep, on shutdown.
+	background, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	probes := health.New()
+	r := router.New(background, cfg, zapLogger, probes)
 
 	// Optionally log where Swagger UI is exposed.
 	zapLogger.Info("Swagger UI available", zap.String("url", "/swagger/index.html"))
@@ -64,7 +86,14 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	zapLogger.Info("Shutting down server...")
+	// Fail readiness first and give load balancers time to stop routing
+	// new requests here before connections are closed.
+	// Background work stops with them; refunds it leaves due are sent by
+	// the next instance's sweep.
+	probes.Drain()
+	stopBackground()
+	zapLogger.Info("Shutting down server...", zap.Duration("drain_delay", cfg.API.ShutdownDrainDelay))
+	time.Sleep(cfg.API.ShutdownDrainDelay)
 
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -74,5 +103,9 @@ func main() {
 		zapLogger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
+	if err := shutdownTracing(ctx); err != nil {
+		zapLogger.Error("Failed to flush traces", zap.Error(err))
+	}
+
 	zapLogger.Info("Server exited")
 }
@@ -0,0 +1,53 @@
+// Command fakepsp runs the fake payment service provider as its own server,
+// for pointing the BFF at with PAYMENT_PSP_URL.
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/fakepsp"
+	"bff-go-mvp/internal/logger"
+)
+
+func main() {
+	zapLogger, err := logger.NewLogger(os.Getenv("ENV"))
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	}
+	defer zapLogger.Sync()
+
+	port := getEnv("FAKE_PSP_PORT", "8090")
+	autoCapture, _ := strconv.ParseBool(getEnv("FAKE_PSP_AUTO_CAPTURE", "false"))
+	psp := fakepsp.New(fakepsp.Options{
+		BaseURL:     getEnv("FAKE_PSP_PUBLIC_URL", "http://localhost:"+port),
+		AutoCapture: autoCapture,
+		CallbackURL: os.Getenv("FAKE_PSP_CALLBACK_URL"),
+		// The secret the BFF has for this provider in PAYMENT_WEBHOOK_SECRETS.
+		WebhookSecret: os.Getenv("FAKE_PSP_WEBHOOK_SECRET"),
+		Logger:        zapLogger,
+	})
+
+	srv := &http.Server{
+		Addr:         ":" + port,
+		Handler:      psp.Handler(),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+	}
+	zapLogger.Info("Starting fake PSP", zap.String("address", srv.Addr), zap.Bool("auto_capture", autoCapture))
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		zapLogger.Fatal("Fake PSP failed", zap.Error(err))
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,115 @@
+// Package apperror defines the typed errors returned by domain services.
+// internal/httpx maps them to HTTP status codes and the standard Error body.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind classifies an error independently of the transport.
+type Kind string
+
+const (
+	KindBadRequest       Kind = "bad_request"
+	KindUnauthorized     Kind = "unauthorized"
+	KindForbidden        Kind = "forbidden"
+	KindNotFound         Kind = "not_found"
+	KindMethodNotAllowed Kind = "method_not_allowed"
+	KindConflict         Kind = "conflict"
+	KindValidation       Kind = "validation"
+	KindUnavailable      Kind = "unavailable"
+	KindInternal         Kind = "internal"
+)
+
+// defaultCodes are the documented error codes for each kind.
+var defaultCodes = map[Kind]string{
+	KindBadRequest:       "BAD_REQUEST",
+	KindUnauthorized:     "UNAUTHORIZED",
+	KindForbidden:        "FORBIDDEN",
+	KindNotFound:         "NOT_FOUND",
+	KindMethodNotAllowed: "METHOD_NOT_ALLOWED",
+	KindConflict:         "CONFLICT",
+	KindValidation:       "VALIDATION_ERROR",
+	KindUnavailable:      "SERVICE_UNAVAILABLE",
+	KindInternal:         "INTERNAL_ERROR",
+}
+
+// InternalMessage is the client-facing message for internal errors.
+const InternalMessage = "Server error occurred while processing the request."
+
+// Error is a domain error with a client-facing code, message and details.
+// Err, when set, is the underlying cause and is never shown to clients.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Details map[string]interface{}
+	Err     error
+}
+
+// New returns an error of the given kind with its default code.
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Code: defaultCodes[kind], Message: message}
+}
+
+// Wrap returns an error of the given kind caused by err.
+func Wrap(kind Kind, message string, err error) *Error {
+	e := New(kind, message)
+	e.Err = err
+	return e
+}
+
+func BadRequest(message string) *Error   { return New(KindBadRequest, message) }
+func Unauthorized(message string) *Error { return New(KindUnauthorized, message) }
+func Forbidden(message string) *Error    { return New(KindForbidden, message) }
+func NotFound(message string) *Error     { return New(KindNotFound, message) }
+func Conflict(message string) *Error     { return New(KindConflict, message) }
+func Validation(message string) *Error   { return New(KindValidation, message) }
+
+// Internal wraps an unexpected failure behind the generic internal message.
+func Internal(err error) *Error {
+	return Wrap(KindInternal, InternalMessage, err)
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithCode overrides the default code.
+func (e *Error) WithCode(code string) *Error {
+	e.Code = code
+	return e
+}
+
+// WithDetail adds a key to the error details.
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	if e.Details == nil {
+		e.Details = make(map[string]interface{})
+	}
+	e.Details[key] = value
+	return e
+}
+
+// From returns the *Error in err's chain, or an internal error wrapping err
+// when there is none.
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return Internal(err)
+}
+
+// IsKind reports whether err carries an *Error of the given kind.
+func IsKind(err error, kind Kind) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Kind == kind
+}
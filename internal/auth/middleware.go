@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/httpx"
+	"bff-go-mvp/internal/transaction"
+)
+
+// Middleware rejects requests without a valid bearer token with 401
+// UNAUTHORIZED and stores the caller's principal in the request context.
+// Requests for which public returns true are passed through unchecked.
+func Middleware(v *Verifier, logger *zap.Logger, public func(*http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if public != nil && public(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, err := bearerToken(r)
+			if err == nil {
+				var p Principal
+				if p, err = v.Verify(r.Context(), token); err == nil {
+					next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+					return
+				}
+			}
+
+			transaction.Logger(r.Context(), logger).Info("Request rejected: invalid bearer token",
+				zap.String("path", r.URL.Path),
+				zap.Error(err),
+			)
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			httpx.WriteAppError(w, apperror.Unauthorized("Invalid or missing authentication token."))
+		})
+	}
+}
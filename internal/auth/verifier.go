@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"bff-go-mvp/internal/config"
+)
+
+// Verifier validates bearer tokens and extracts the caller's principal.
+type Verifier struct {
+	keys       *KeySet
+	secret     []byte
+	methods    []string
+	options    []jwt.ParserOption
+	rolesClaim string
+}
+
+// NewVerifier builds a verifier from the auth configuration, loading the
+// JWKS from file or URL when configured.
+func NewVerifier(ctx context.Context, cfg config.AuthConfig) (*Verifier, error) {
+	v := &Verifier{rolesClaim: cfg.RolesClaim}
+	if v.rolesClaim == "" {
+		v.rolesClaim = "roles"
+	}
+
+	switch {
+	case cfg.JWKSFile != "":
+		keys, err := LoadKeySetFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+	case cfg.JWKSURL != "":
+		keys, err := NewRemoteKeySet(ctx, cfg.JWKSURL, nil, cfg.JWKSRefresh)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+	}
+	if v.keys != nil {
+		v.methods = append(v.methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+	if cfg.HS256Secret != "" {
+		v.secret = []byte(cfg.HS256Secret)
+		v.methods = append(v.methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(v.methods) == 0 {
+		return nil, errors.New("no token verification keys configured")
+	}
+
+	v.options = []jwt.ParserOption{
+		jwt.WithValidMethods(v.methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.ClockSkew),
+	}
+	if cfg.Issuer != "" {
+		v.options = append(v.options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		v.options = append(v.options, jwt.WithAudience(cfg.Audience))
+	}
+	return v, nil
+}
+
+// Verify parses and validates a compact JWT and returns its principal.
+func (v *Verifier) Verify(ctx context.Context, token string) (Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if v.secret == nil {
+				return nil, errors.New("HMAC tokens are not accepted")
+			}
+			return v.secret, nil
+		default:
+			if v.keys == nil {
+				return nil, errors.New("no JWKS configured")
+			}
+			kid, _ := t.Header["kid"].(string)
+			return v.keys.Key(ctx, kid)
+		}
+	}, v.options...)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Principal{}, errors.New("token has no subject")
+	}
+	bapID, _ := claims["bap_id"].(string)
+	return Principal{Subject: subject, BAPID: bapID, Roles: roles(claims, v.rolesClaim)}, nil
+}
+
+// roles reads the roles claim: a list of strings or a space-delimited
+// string.
+func roles(claims jwt.MapClaims, claim string) []string {
+	var out []string
+	switch v := claims[claim].(type) {
+	case []interface{}:
+		for _, r := range v {
+			if s, ok := r.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+	case string:
+		out = strings.Fields(v)
+	}
+	return out
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", errors.New("missing Authorization header")
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", errors.New("unsupported Authorization scheme")
+	}
+	return strings.TrimSpace(token), nil
+}
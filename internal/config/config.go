@@ -1,13 +1,30 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"bff-go-mvp/internal/money"
 )
 
 // Config holds application configuration
 type Config struct {
+	// Env is the deployment environment from ENV: "development" or "dev"
+	// for local development, anything else is treated as production.
+	Env     string
 	GRPC    GRPCConfig
 	API     APIConfig
+	Backend BackendConfig
+	Auth    AuthConfig
+	Tracing TracingConfig
+	Payment PaymentConfig
+
+	// loadProblems are the settings Load could not parse.
+	loadProblems []string
 }
 
 // GRPCConfig holds gRPC client configuration
@@ -18,18 +35,386 @@ type GRPCConfig struct {
 // APIConfig holds API server configuration
 type APIConfig struct {
 	Port string
+	// HealthCheckTimeout bounds each readiness check.
+	HealthCheckTimeout time.Duration
+	// ShutdownDrainDelay is how long readiness fails before the server stops
+	// accepting connections, giving load balancers time to drain it.
+	ShutdownDrainDelay time.Duration
+	// MoneyFormat is the money format of responses when a request has no
+	// X-Money-Format header: money.FormatLegacy or money.FormatDecimal.
+	MoneyFormat string
+}
+
+// AuthConfig holds bearer token verification settings. RS256 and ES256 tokens
+// are checked against a JWKS loaded from JWKSFile or JWKSURL; HS256 tokens
+// against HS256Secret, which is meant for development only.
+type AuthConfig struct {
+	Enabled     bool
+	JWKSFile    string
+	JWKSURL     string
+	HS256Secret string
+	Issuer      string
+	Audience    string
+	// OperatorRole grants access to every order.
+	OperatorRole string
+	// RolesClaim is the claim roles are read from. OAuth scopes are not
+	// roles and are never read as such.
+	RolesClaim string
+	// ClockSkew is the leeway allowed when checking exp, nbf and iat.
+	ClockSkew time.Duration
+	// JWKSRefresh is the minimum interval between JWKS URL fetches.
+	JWKSRefresh time.Duration
+}
+
+// PaymentConfig holds the payment gateway settings of the payment domain in
+// mock mode. Payments go to the PSP at PSPURL. In development, FakePSP
+// mounts an in-process fake PSP at /psp/ on the BFF itself instead.
+type PaymentConfig struct {
+	PSPURL string
+	// PSPProvider names the PSP at PSPURL in its webhook path and in
+	// WebhookSecrets. Payments only accept webhooks from the provider they
+	// were created at.
+	PSPProvider string
+	// FakePSP serves payments from the in-process fake PSP. It moves no
+	// money and its pages need no authentication, so Validate only allows
+	// it in development.
+	FakePSP bool
+	// PublicURL is the BFF's public base URL, used for the checkout pages of
+	// the in-process fake PSP.
+	PublicURL string
+	// AutoCapture makes the in-process fake PSP capture payments on
+	// creation, so orders are paid as soon as payment is initiated.
+	AutoCapture bool
+	// CallbackURL is where the in-process fake PSP reports payments
+	// approved or declined on its checkout page; empty reports them to the
+	// BFF's own webhook for provider "fakepsp".
+	CallbackURL string
+	// WebhookSecrets are the HMAC secrets that sign each provider's
+	// webhooks, keyed by the provider name in the webhook path. The
+	// in-process fake PSP gets a random one when "fakepsp" has none.
+	WebhookSecrets map[string]string
+	// WebhookTolerance is how far a webhook's signature timestamp may be
+	// from now; older events are rejected as stale.
+	WebhookTolerance time.Duration
+	// RefundMaxAttempts bounds the attempts made to send a refund to the
+	// PSP; a failed attempt is retried after RefundRetryBackoff, doubling
+	// after each further failure.
+	RefundMaxAttempts  int
+	RefundRetryBackoff time.Duration
+	// RefundSweepInterval is how often refunds that are due are looked up
+	// in the order store and sent again.
+	RefundSweepInterval time.Duration
+	// MerchantVPA and MerchantName are the payee of UPI intent links.
+	MerchantVPA  string
+	MerchantName string
+}
+
+// TracingConfig holds OpenTelemetry trace export settings. The variable
+// names follow the OpenTelemetry SDK conventions.
+type TracingConfig struct {
+	// Exporter is "none", "otlp" (gRPC) or "stdout".
+	Exporter     string
+	OTLPEndpoint string
+	OTLPInsecure bool
+	ServiceName  string
+	// SampleRatio is the fraction of new traces sampled; incoming sampled
+	// parents are always followed.
+	SampleRatio float64
+}
+
+// Trace exporters.
+const (
+	TraceExporterNone   = "none"
+	TraceExporterOTLP   = "otlp"
+	TraceExporterStdout = "stdout"
+)
+
+// BackendMode selects which implementation serves a domain.
+type BackendMode string
+
+const (
+	BackendModeMock BackendMode = "mock"
+	BackendModeGRPC BackendMode = "grpc"
+	BackendModeHTTP BackendMode = "http"
+	// BackendModeIndex serves search from an in-memory station index loaded
+	// from a fixture file.
+	BackendModeIndex BackendMode = "index"
+)
+
+// Domain names used for per-domain backend selection.
+const (
+	DomainSearch    = "search"
+	DomainEstimate  = "estimate"
+	DomainPayment   = "payment"
+	DomainOrders    = "orders"
+	DomainLifecycle = "lifecycle"
+	DomainFeedback  = "feedback"
+	DomainSupport   = "support"
+)
+
+// supportedModes lists the backend modes that have an implementation for
+// each domain. Only discovery (search) has a gRPC contract and a local
+// station index today.
+var supportedModes = map[string][]BackendMode{
+	DomainSearch:    {BackendModeMock, BackendModeGRPC, BackendModeHTTP, BackendModeIndex},
+	DomainEstimate:  {BackendModeMock, BackendModeHTTP},
+	DomainPayment:   {BackendModeMock, BackendModeHTTP},
+	DomainOrders:    {BackendModeMock, BackendModeHTTP},
+	DomainLifecycle: {BackendModeMock, BackendModeHTTP},
+	DomainFeedback:  {BackendModeMock, BackendModeHTTP},
+	DomainSupport:   {BackendModeMock, BackendModeHTTP},
+}
+
+// BackendConfig holds per-domain backend selection. Each domain falls back
+// to the global BACKEND_MODE when its own variable is not set.
+type BackendConfig struct {
+	Search    BackendMode
+	Estimate  BackendMode
+	Payment   BackendMode
+	Orders    BackendMode
+	Lifecycle BackendMode
+	Feedback  BackendMode
+	Support   BackendMode
+
+	// HTTPBaseURL is the downstream REST base URL used by domains in http mode.
+	HTTPBaseURL string
+	// HTTPTimeout bounds each downstream HTTP call.
+	HTTPTimeout time.Duration
+	// StationsFile is the station fixture used by search in index mode.
+	StationsFile string
+	// VehiclesFile is the vehicle registry; empty uses the built-in one.
+	VehiclesFile string
+}
+
+// DomainMode pairs a domain name with its selected backend mode.
+type DomainMode struct {
+	Domain string
+	Mode   BackendMode
+}
+
+// Domains returns the backend mode of every domain in a stable order.
+func (b BackendConfig) Domains() []DomainMode {
+	return []DomainMode{
+		{DomainSearch, b.Search},
+		{DomainEstimate, b.Estimate},
+		{DomainPayment, b.Payment},
+		{DomainOrders, b.Orders},
+		{DomainLifecycle, b.Lifecycle},
+		{DomainFeedback, b.Feedback},
+		{DomainSupport, b.Support},
+	}
+}
+
+// Uses reports whether any domain is configured with the given mode.
+func (b BackendConfig) Uses(mode BackendMode) bool {
+	for _, d := range b.Domains() {
+		if d.Mode == mode {
+			return true
+		}
+	}
+	return false
 }
 
 // Load loads configuration from environment variables with defaults
 func Load() *Config {
-	return &Config{
+	defaultMode := getEnv("BACKEND_MODE", string(BackendModeMock))
+
+	l := &loader{}
+	cfg := &Config{
+		Env: getEnv("ENV", "production"),
 		GRPC: GRPCConfig{
 			ServiceAddress: getEnv("GRPC_SERVICE_ADDRESS", "localhost:50051"),
 		},
 		API: APIConfig{
-			Port: getEnv("API_PORT", "8080"),
+			Port:               getEnv("API_PORT", "8080"),
+			HealthCheckTimeout: l.getDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			ShutdownDrainDelay: l.getDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+			MoneyFormat:        strings.ToLower(getEnv("MONEY_FORMAT", money.FormatLegacy)),
+		},
+		Backend: BackendConfig{
+			Search:       getMode("SEARCH_BACKEND_MODE", defaultMode),
+			Estimate:     getMode("ESTIMATE_BACKEND_MODE", defaultMode),
+			Payment:      getMode("PAYMENT_BACKEND_MODE", defaultMode),
+			Orders:       getMode("ORDERS_BACKEND_MODE", defaultMode),
+			Lifecycle:    getMode("LIFECYCLE_BACKEND_MODE", defaultMode),
+			Feedback:     getMode("FEEDBACK_BACKEND_MODE", defaultMode),
+			Support:      getMode("SUPPORT_BACKEND_MODE", defaultMode),
+			HTTPBaseURL:  getEnv("BACKEND_HTTP_BASE_URL", ""),
+			HTTPTimeout:  l.getDuration("BACKEND_HTTP_TIMEOUT", 10*time.Second),
+			StationsFile: getEnv("SEARCH_STATIONS_FILE", ""),
+			VehiclesFile: getEnv("VEHICLES_FILE", ""),
+		},
+		Auth: AuthConfig{
+			Enabled:      l.getBool("AUTH_ENABLED", false),
+			JWKSFile:     getEnv("AUTH_JWKS_FILE", ""),
+			JWKSURL:      getEnv("AUTH_JWKS_URL", ""),
+			HS256Secret:  getEnv("AUTH_HS256_SECRET", ""),
+			Issuer:       getEnv("AUTH_ISSUER", ""),
+			Audience:     getEnv("AUTH_AUDIENCE", ""),
+			OperatorRole: getEnv("AUTH_OPERATOR_ROLE", "operator"),
+			RolesClaim:   getEnv("AUTH_ROLES_CLAIM", "roles"),
+			ClockSkew:    l.getDuration("AUTH_CLOCK_SKEW", 30*time.Second),
+			JWKSRefresh:  l.getDuration("AUTH_JWKS_REFRESH", 5*time.Minute),
+		},
+		Payment: PaymentConfig{
+			PSPURL:       getEnv("PAYMENT_PSP_URL", ""),
+			PSPProvider:  getEnv("PAYMENT_PSP_PROVIDER", "fakepsp"),
+			FakePSP:      l.getBool("PAYMENT_FAKE_PSP", false),
+			PublicURL:    getEnv("PAYMENT_PUBLIC_URL", "http://localhost:"+getEnv("API_PORT", "8080")),
+			AutoCapture:  l.getBool("PAYMENT_AUTO_CAPTURE", false),
+			CallbackURL:  getEnv("PAYMENT_CALLBACK_URL", ""),
+			MerchantVPA:  getEnv("PAYMENT_MERCHANT_VPA", "bluechargenet@upi"),
+			MerchantName: getEnv("PAYMENT_MERCHANT_NAME", "BlueChargeNet"),
+
+			WebhookSecrets:   getPairs("PAYMENT_WEBHOOK_SECRETS"),
+			WebhookTolerance: l.getDuration("PAYMENT_WEBHOOK_TOLERANCE", 5*time.Minute),
+
+			RefundMaxAttempts:   l.getInt("PAYMENT_REFUND_MAX_ATTEMPTS", 5),
+			RefundRetryBackoff:  l.getDuration("PAYMENT_REFUND_RETRY_BACKOFF", 30*time.Second),
+			RefundSweepInterval: l.getDuration("PAYMENT_REFUND_SWEEP_INTERVAL", 10*time.Second),
+		},
+		Tracing: TracingConfig{
+			Exporter:     strings.ToLower(strings.TrimSpace(getEnv("OTEL_TRACES_EXPORTER", TraceExporterNone))),
+			OTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4317"),
+			OTLPInsecure: l.getBool("OTEL_EXPORTER_OTLP_INSECURE", true),
+			ServiceName:  getEnv("OTEL_SERVICE_NAME", "bff-go-mvp"),
+			SampleRatio:  l.getFloat("OTEL_TRACES_SAMPLER_ARG", 1),
 		},
 	}
+	cfg.loadProblems = l.problems
+	return cfg
+}
+
+// Development reports whether ENV selects local development.
+func (c *Config) Development() bool {
+	return c.Env == "development" || c.Env == "dev"
+}
+
+// Validate checks that every domain has a known, implemented backend mode
+// and that the settings required by the selected modes are present.
+func (c *Config) Validate() error {
+	problems := append([]string(nil), c.loadProblems...)
+
+	for _, d := range c.Backend.Domains() {
+		if !isSupported(d.Domain, d.Mode) {
+			problems = append(problems, fmt.Sprintf("%s: unsupported backend mode %q (supported: %s)",
+				d.Domain, d.Mode, joinModes(supportedModes[d.Domain])))
+		}
+	}
+
+	if c.Backend.Uses(BackendModeGRPC) && c.GRPC.ServiceAddress == "" {
+		problems = append(problems, "GRPC_SERVICE_ADDRESS is required when a domain uses grpc mode")
+	}
+	if c.Backend.Uses(BackendModeHTTP) && c.Backend.HTTPBaseURL == "" {
+		problems = append(problems, "BACKEND_HTTP_BASE_URL is required when a domain uses http mode")
+	}
+	if c.Backend.Search == BackendModeIndex && c.Backend.StationsFile == "" {
+		problems = append(problems, "SEARCH_STATIONS_FILE is required when search uses index mode")
+	}
+
+	if c.API.MoneyFormat != money.FormatLegacy && c.API.MoneyFormat != money.FormatDecimal {
+		problems = append(problems, fmt.Sprintf("MONEY_FORMAT: unsupported format %q (supported: %s, %s)",
+			c.API.MoneyFormat, money.FormatLegacy, money.FormatDecimal))
+	}
+
+	if c.Backend.Payment == BackendModeMock {
+		for _, u := range []struct{ key, value string }{
+			{"PAYMENT_PSP_URL", c.Payment.PSPURL},
+			{"PAYMENT_PUBLIC_URL", c.Payment.PublicURL},
+			{"PAYMENT_CALLBACK_URL", c.Payment.CallbackURL},
+		} {
+			if u.value != "" && !isHTTPURL(u.value) {
+				problems = append(problems, fmt.Sprintf("%s must be an absolute http(s) URL", u.key))
+			}
+		}
+		if handle, provider, ok := strings.Cut(c.Payment.MerchantVPA, "@"); !ok || handle == "" || provider == "" {
+			problems = append(problems, "PAYMENT_MERCHANT_VPA must be a UPI address such as name@bank")
+		}
+		switch {
+		case c.Payment.FakePSP && c.Payment.PSPURL != "":
+			problems = append(problems, "PAYMENT_FAKE_PSP and PAYMENT_PSP_URL cannot both be set")
+		case c.Payment.FakePSP && !c.Development():
+			problems = append(problems, "PAYMENT_FAKE_PSP is only allowed with ENV=development")
+		case !c.Payment.FakePSP && c.Payment.PSPURL == "":
+			problems = append(problems, "PAYMENT_PSP_URL is required when payment uses mock mode (or PAYMENT_FAKE_PSP in development)")
+		}
+	}
+	for provider, secret := range c.Payment.WebhookSecrets {
+		if provider == "" || secret == "" {
+			problems = append(problems, "PAYMENT_WEBHOOK_SECRETS must be a comma-separated list of provider=secret pairs")
+			break
+		}
+	}
+	if c.Payment.WebhookTolerance <= 0 {
+		problems = append(problems, "PAYMENT_WEBHOOK_TOLERANCE must be positive")
+	}
+	if c.Payment.RefundMaxAttempts <= 0 {
+		problems = append(problems, "PAYMENT_REFUND_MAX_ATTEMPTS must be positive")
+	}
+	if c.Payment.RefundRetryBackoff <= 0 {
+		problems = append(problems, "PAYMENT_REFUND_RETRY_BACKOFF must be positive")
+	}
+	if c.Payment.RefundSweepInterval <= 0 {
+		problems = append(problems, "PAYMENT_REFUND_SWEEP_INTERVAL must be positive")
+	}
+
+	if !c.Auth.Enabled && !c.Development() {
+		problems = append(problems, "AUTH_ENABLED is required unless ENV=development")
+	}
+	if c.Auth.Enabled {
+		if c.Auth.JWKSFile == "" && c.Auth.JWKSURL == "" && c.Auth.HS256Secret == "" {
+			problems = append(problems, "AUTH_JWKS_FILE, AUTH_JWKS_URL or AUTH_HS256_SECRET is required when AUTH_ENABLED is set")
+		}
+		if c.Auth.JWKSFile != "" && c.Auth.JWKSURL != "" {
+			problems = append(problems, "set only one of AUTH_JWKS_FILE and AUTH_JWKS_URL")
+		}
+		if c.Auth.HS256Secret != "" && len(c.Auth.HS256Secret) < 32 {
+			problems = append(problems, "AUTH_HS256_SECRET must be at least 32 bytes")
+		}
+		if c.Auth.HS256Secret != "" && !c.Development() {
+			problems = append(problems, "AUTH_HS256_SECRET is only allowed with ENV=development")
+		}
+	}
+
+	switch c.Tracing.Exporter {
+	case TraceExporterNone, TraceExporterStdout:
+	case TraceExporterOTLP:
+		if c.Tracing.OTLPEndpoint == "" {
+			problems = append(problems, "OTEL_EXPORTER_OTLP_ENDPOINT is required when OTEL_TRACES_EXPORTER is otlp")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("OTEL_TRACES_EXPORTER: unsupported exporter %q (supported: none, otlp, stdout)", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problems = append(problems, "OTEL_TRACES_SAMPLER_ARG must be between 0 and 1")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func isSupported(domain string, mode BackendMode) bool {
+	for _, m := range supportedModes[domain] {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+func joinModes(modes []BackendMode) string {
+	names := make([]string, 0, len(modes))
+	for _, m := range modes {
+		names = append(names, string(m))
+	}
+	return strings.Join(names, ", ")
 }
 
 // getEnv gets environment variable or returns default value
@@ -39,3 +424,82 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getMode reads a backend mode, normalising case and whitespace.
+func getMode(key, defaultValue string) BackendMode {
+	return BackendMode(strings.ToLower(strings.TrimSpace(getEnv(key, defaultValue))))
+}
+
+// loader reads typed settings and records those that cannot be parsed, so
+// that Validate reports them instead of silently using the defaults.
+type loader struct {
+	problems []string
+}
+
+func (l *loader) invalid(key, value, want string) {
+	l.problems = append(l.problems, fmt.Sprintf("%s=%q is not %s", key, value, want))
+}
+
+// getBool parses a boolean such as "true", "1" or "false", or returns the
+// default value when unset.
+func (l *loader) getBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err == nil {
+			return b
+		}
+		l.invalid(key, value, "a boolean (true or false)")
+	}
+	return defaultValue
+}
+
+// getInt parses an integer or returns the default value when unset.
+func (l *loader) getInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		n, err := strconv.Atoi(value)
+		if err == nil {
+			return n
+		}
+		l.invalid(key, value, "an integer")
+	}
+	return defaultValue
+}
+
+// getFloat parses a float or returns the default value when unset.
+func (l *loader) getFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		f, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			return f
+		}
+		l.invalid(key, value, "a number")
+	}
+	return defaultValue
+}
+
+// getPairs parses a comma-separated list of key=value pairs. An entry
+// without "=" is kept with an empty value so that Validate reports it.
+func getPairs(key string) map[string]string {
+	pairs := map[string]string{}
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(entry, "=")
+		pairs[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return pairs
+}
+
+// getDuration parses a Go duration string or returns the default value
+// when unset.
+func (l *loader) getDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		d, err := time.ParseDuration(value)
+		if err == nil {
+			return d
+		}
+		l.invalid(key, value, "a duration such as 30s")
+	}
+	return defaultValue
+}
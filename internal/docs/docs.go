@@ -17,7 +17,12 @@ const docTemplate = `{
     "paths": {
         "/v1/estimate": {
             "post": {
-                "description": "Returns an estimated cost, duration, and other pricing details for a charging session.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns an estimated cost, duration, and other pricing details for a charging session. The selected offer is priced for the requested energy, or for as much energy as the requested amount covers; price components always sum to the amount. Duration and battery gain follow the vehicle's charging curve from state_of_charge.",
                 "consumes": [
                     "application/json"
                 ],
@@ -31,10 +36,25 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unique transaction identifier",
+                        "description": "Unique transaction identifier; generated when absent",
                         "name": "X-Transaction-Id",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Backend provider identifier",
+                        "name": "X-Bpp-Id",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "decimal",
+                            "legacy"
+                        ],
+                        "type": "string",
+                        "description": "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent",
+                        "name": "X-Money-Format",
+                        "in": "header"
                     },
                     {
                         "description": "Estimate request payload",
@@ -59,6 +79,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -70,7 +108,12 @@ const docTemplate = `{
         },
         "/v1/orders/{order_id}": {
             "get": {
-                "description": "Returns current status and details of an order.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns current status and details of an order. Once charging has stopped it includes the session's bill, with the idle fee accrued while the vehicle stays plugged in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -84,10 +127,9 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unique transaction identifier",
+                        "description": "Unique transaction identifier; generated when absent",
                         "name": "X-Transaction-Id",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
                         "type": "string",
@@ -102,6 +144,16 @@ const docTemplate = `{
                         "name": "order_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "decimal",
+                            "legacy"
+                        ],
+                        "type": "string",
+                        "description": "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent",
+                        "name": "X-Money-Format",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -117,6 +169,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -128,7 +192,12 @@ const docTemplate = `{
         },
         "/v1/orders/{order_id}/cancel": {
             "get": {
-                "description": "Returns an estimate of fees applicable if the order is cancelled.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns an estimate of fees applicable if the order is cancelled, under the cancellation terms booked with the order. A cancel_code for an operator fault (see cancellation.feeWaivedFor in the estimate) waives the fee when the caller holds the operator role; buyers' codes do not.",
                 "consumes": [
                     "application/json"
                 ],
@@ -142,10 +211,9 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unique transaction identifier",
+                        "description": "Unique transaction identifier; generated when absent",
                         "name": "X-Transaction-Id",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
                         "type": "string",
@@ -193,6 +261,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -202,7 +288,12 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Cancels an order and returns the final cancellation outcome.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels an order and returns the final cancellation outcome. The fee is computed exactly as by the cancellation estimate; cancel_code and cancel_reason may be sent as query parameters or body fields.",
                 "consumes": [
                     "application/json"
                 ],
@@ -216,10 +307,9 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unique transaction identifier",
+                        "description": "Unique transaction identifier; generated when absent",
                         "name": "X-Transaction-Id",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
                         "type": "string",
@@ -235,6 +325,18 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Reason description for cancellation",
+                        "name": "cancel_reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason code for cancellation; operator-fault codes waive the fee for callers with the operator role",
+                        "name": "cancel_code",
+                        "in": "query"
+                    },
                     {
                         "description": "Optional cancellation payload",
                         "name": "request",
@@ -257,6 +359,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -268,7 +388,12 @@ const docTemplate = `{
         },
         "/v1/orders/{order_id}/payment": {
             "post": {
-                "description": "Initiates a payment flow for the specified order.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Initiates payment of the order's quoted amount through the payment gateway adapter of the chosen method: a upi:// intent link for UPI_INTENT, a collect request to the buyer's VPA for UPI_COLLECT, or the PSP checkout page for CARD and WALLET. paymentUrl is where the buyer pays, and attempt tracks the payment attempt recorded on the order. The order becomes ACTIVE once the PSP captures the payment; initiating again with the same method returns the pending attempt.",
                 "consumes": [
                     "application/json"
                 ],
@@ -282,10 +407,9 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unique transaction identifier",
+                        "description": "Unique transaction identifier; generated when absent",
                         "name": "X-Transaction-Id",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
                         "type": "string",
@@ -294,6 +418,16 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "enum": [
+                            "decimal",
+                            "legacy"
+                        ],
+                        "type": "string",
+                        "description": "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent",
+                        "name": "X-Money-Format",
+                        "in": "header"
+                    },
                     {
                         "type": "string",
                         "description": "Order ID",
@@ -306,7 +440,7 @@ const docTemplate = `{
                         "name": "request",
                         "in": "body",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/model.PaymentRequest"
                         }
                     }
                 ],
@@ -323,6 +457,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -334,6 +486,11 @@ const docTemplate = `{
         },
         "/v1/orders/{order_id}/rating": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Submits a rating and optional feedback comments for a completed order.",
                 "consumes": [
                     "application/json"
@@ -348,10 +505,9 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unique transaction identifier",
+                        "description": "Unique transaction identifier; generated when absent",
                         "name": "X-Transaction-Id",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
                         "type": "string",
@@ -390,6 +546,108 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/{order_id}/refunds": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the refunds of an order, oldest first. Cancelling a paid order refunds what the cancellation fee leaves, and stopping a session refunds the unused prepaid amount; each refund is sent through the payment gateway of the captured payment and retried with backoff when it fails. refunded sums the refunds that succeeded; the payment becomes REFUNDED once they add up to the captured amount and stays PAID after a partial refund.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "List order refunds",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unique transaction identifier; generated when absent",
+                        "name": "X-Transaction-Id",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Backend provider identifier",
+                        "name": "X-Bpp-Id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "decimal",
+                            "legacy"
+                        ],
+                        "type": "string",
+                        "description": "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent",
+                        "name": "X-Money-Format",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RefundsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -401,6 +659,11 @@ const docTemplate = `{
         },
         "/v1/orders/{order_id}/start": {
             "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Starts a charging session for an existing order.",
                 "consumes": [
                     "application/json"
@@ -415,10 +678,9 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unique transaction identifier",
+                        "description": "Unique transaction identifier; generated when absent",
                         "name": "X-Transaction-Id",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
                         "type": "string",
@@ -456,6 +718,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -467,7 +747,12 @@ const docTemplate = `{
         },
         "/v1/orders/{order_id}/stop": {
             "get": {
-                "description": "Returns an estimate of charges and status if the session is stopped now.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns an estimate of charges and status if the session is stopped now: the session's ENERGY and SESSION_DURATION telemetry billed with the booked offer, less the prepaid amount. Stopping bills the same way.",
                 "consumes": [
                     "application/json"
                 ],
@@ -481,10 +766,9 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unique transaction identifier",
+                        "description": "Unique transaction identifier; generated when absent",
                         "name": "X-Transaction-Id",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
                         "type": "string",
@@ -520,6 +804,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -529,7 +831,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Stops an ongoing charging session and returns final pricing information.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stops an ongoing charging session and returns final pricing information: the session's ENERGY and SESSION_DURATION telemetry billed with the booked offer, then a refund of any unused prepaid amount; breakdown.total is the balance due.",
                 "consumes": [
                     "application/json"
                 ],
@@ -543,10 +850,9 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unique transaction identifier",
+                        "description": "Unique transaction identifier; generated when absent",
                         "name": "X-Transaction-Id",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
                         "type": "string",
@@ -562,6 +868,16 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "enum": [
+                            "decimal",
+                            "legacy"
+                        ],
+                        "type": "string",
+                        "description": "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent",
+                        "name": "X-Money-Format",
+                        "in": "header"
+                    },
                     {
                         "description": "Optional stop reason payload",
                         "name": "request",
@@ -584,6 +900,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -595,6 +929,11 @@ const docTemplate = `{
         },
         "/v1/orders/{order_id}/support": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Returns support contact channels and metadata for a specific order.",
                 "consumes": [
                     "application/json"
@@ -609,10 +948,9 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unique transaction identifier",
+                        "description": "Unique transaction identifier; generated when absent",
                         "name": "X-Transaction-Id",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
                         "type": "string",
@@ -642,6 +980,105 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/{order_id}/unplug": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records that the vehicle was unplugged after charging completed. The time between charging completing and the unplug, beyond the offer's grace period, is billed as an IDLE_FEE component under the offer's idle fee policy, and the session is settled again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Report connector unplugged",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unique transaction identifier; generated when absent",
+                        "name": "X-Transaction-Id",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Backend provider identifier",
+                        "name": "X-Bpp-Id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "decimal",
+                            "legacy"
+                        ],
+                        "type": "string",
+                        "description": "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent",
+                        "name": "X-Money-Format",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.UnplugResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -653,7 +1090,12 @@ const docTemplate = `{
         },
         "/v1/search": {
             "post": {
-                "description": "Search charging locations/connectors by EVSE ID or geo-coordinates with optional filters.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search charging locations/connectors by EVSE ID or geo-coordinates with optional filters, sort and availability (time_window or open_now). Unsupported connector types, vehicle types, sort keys or orders and invalid time windows return 422.",
                 "consumes": [
                     "application/json"
                 ],
@@ -700,6 +1142,159 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/vehicles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the vehicles in the registry with their usable battery capacity, supported connectors, AC and DC charge power limits and charging curve. Estimates for vehicles that are not listed use per-type defaults.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicles"
+                ],
+                "summary": "List vehicles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only vehicles of this make (case-insensitive)",
+                        "name": "make",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "2-wheeler",
+                            "3-wheeler",
+                            "4-wheeler"
+                        ],
+                        "type": "string",
+                        "description": "Only vehicles of this type",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.VehiclesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/webhooks/payments/{provider}": {
+            "post": {
+                "description": "Applies a payment event from the provider to the order's payment: payment.captured pays the order and payment.failed marks its payment FAILED so that it can be retried; refund.succeeded and refund.failed report the outcome of a refund. The request must carry an X-Webhook-Signature header of the form t=\u003cunix seconds\u003e,v1=\u003chex HMAC-SHA256 of \"\u003ct\u003e.\u003cbody\u003e\"\u003e made with the provider's secret from PAYMENT_WEBHOOK_SECRETS; signatures older than PAYMENT_WEBHOOK_TOLERANCE are rejected as stale. An event delivered again is acknowledged as a duplicate without changing the order. The endpoint does not take a bearer token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Receive a payment provider webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "t=\u003cunix seconds\u003e,v1=\u003chex HMAC-SHA256 signature\u003e",
+                        "name": "X-Webhook-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "fakepsp",
+                        "description": "Payment provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment event",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment.PSPEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PaymentWebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -732,7 +1327,8 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "value": {
-                    "type": "number"
+                    "type": "string",
+                    "example": "128.64"
                 }
             }
         },
@@ -775,6 +1371,9 @@ const docTemplate = `{
         "model.CancelEstimateResponse": {
             "type": "object",
             "properties": {
+                "breakdown": {
+                    "$ref": "#/definitions/model.PriceBreakdown"
+                },
                 "charging": {
                     "$ref": "#/definitions/model.ChargingInfo"
                 },
@@ -787,7 +1386,7 @@ const docTemplate = `{
                 "priceComponents": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.PriceComponentString"
+                        "$ref": "#/definitions/model.PriceComponent"
                     }
                 },
                 "validity": {
@@ -798,6 +1397,9 @@ const docTemplate = `{
         "model.CancelResponse": {
             "type": "object",
             "properties": {
+                "breakdown": {
+                    "$ref": "#/definitions/model.PriceBreakdown"
+                },
                 "charging": {
                     "$ref": "#/definitions/model.ChargingInfo"
                 },
@@ -810,7 +1412,7 @@ const docTemplate = `{
                 "priceComponents": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.PriceComponentString"
+                        "$ref": "#/definitions/model.PriceComponent"
                     }
                 }
             }
@@ -831,21 +1433,73 @@ const docTemplate = `{
                 },
                 "fee": {
                     "$ref": "#/definitions/model.CancellationFee"
+                },
+                "feeWaivedFor": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "freeBeforeMinutes": {
+                    "type": "integer"
+                },
+                "minimumFee": {
+                    "$ref": "#/definitions/model.Amount"
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CancellationTier"
+                    }
+                }
+            }
+        },
+        "model.CancellationTier": {
+            "type": "object",
+            "properties": {
+                "percentage": {
+                    "type": "string"
+                },
+                "withinMinutes": {
+                    "type": "integer"
                 }
             }
         },
         "model.Catalog": {
             "type": "object",
             "properties": {
+                "address": {
+                    "$ref": "#/definitions/model.Address"
+                },
+                "availabilityWindow": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AvailabilityWindow"
+                    }
+                },
+                "availablePowerType": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "connectors": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/model.Connector"
                     }
                 },
+                "distanceMeters": {
+                    "description": "DistanceMeters is the distance from the searched coordinates, when the\nsearch was by location.",
+                    "type": "number"
+                },
                 "id": {
                     "type": "string"
                 },
+                "nextOpening": {
+                    "description": "NextOpening is when a station that is closed at search time opens next,\nin RFC 3339 with the station's UTC offset.",
+                    "type": "string"
+                },
                 "offers": {
                     "type": "array",
                     "items": {
@@ -857,6 +1511,21 @@ const docTemplate = `{
                 },
                 "rating": {
                     "$ref": "#/definitions/model.Rating"
+                },
+                "timezone": {
+                    "description": "Timezone is the IANA time zone of AvailabilityWindow; Asia/Kolkata when\nempty.",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChargingCurvePoint": {
+            "type": "object",
+            "properties": {
+                "powerKW": {
+                    "type": "number"
+                },
+                "soc": {
+                    "type": "number"
                 }
             }
         },
@@ -899,12 +1568,6 @@ const docTemplate = `{
         "model.Connector": {
             "type": "object",
             "properties": {
-                "availabilityWindow": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.AvailabilityWindow"
-                    }
-                },
                 "connectorAttributes": {
                     "$ref": "#/definitions/model.ConnectorAttributes"
                 },
@@ -1021,6 +1684,10 @@ const docTemplate = `{
                 "offer_id": {
                     "type": "string"
                 },
+                "state_of_charge": {
+                    "description": "StateOfCharge is the battery level in percent when charging starts;\n20 when absent.",
+                    "type": "number"
+                },
                 "time_window": {
                     "$ref": "#/definitions/model.TimeWindow"
                 },
@@ -1035,6 +1702,9 @@ const docTemplate = `{
                 "amount": {
                     "$ref": "#/definitions/model.Amount"
                 },
+                "breakdown": {
+                    "$ref": "#/definitions/model.PriceBreakdown"
+                },
                 "cancellation": {
                     "$ref": "#/definitions/model.CancellationPolicy"
                 },
@@ -1100,6 +1770,35 @@ const docTemplate = `{
                 }
             }
         },
+        "model.IdleFee": {
+            "type": "object",
+            "properties": {
+                "cap": {
+                    "type": "number",
+                    "example": 200
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "INR"
+                },
+                "gracePeriodMinutes": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 2
+                },
+                "unit": {
+                    "type": "string",
+                    "enum": [
+                        "MIN",
+                        "HUR"
+                    ],
+                    "example": "MIN"
+                }
+            }
+        },
         "model.Offer": {
             "type": "object",
             "properties": {
@@ -1141,6 +1840,9 @@ const docTemplate = `{
                 "buyerFinderFee": {
                     "$ref": "#/definitions/model.BuyerFinderFee"
                 },
+                "idleFee": {
+                    "$ref": "#/definitions/model.IdleFee"
+                },
                 "idleFeePolicy": {
                     "type": "string"
                 }
@@ -1171,6 +1873,9 @@ const docTemplate = `{
         "model.OrderResponse": {
             "type": "object",
             "properties": {
+                "breakdown": {
+                    "$ref": "#/definitions/model.PriceBreakdown"
+                },
                 "charging": {
                     "$ref": "#/definitions/model.ChargingInfo"
                 },
@@ -1189,6 +1894,13 @@ const docTemplate = `{
                 "payment": {
                     "$ref": "#/definitions/model.PaymentInfo"
                 },
+                "priceComponents": {
+                    "description": "PriceComponents and Breakdown are the session's bill once charging\nhas stopped, including the idle fee accrued so far.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PriceComponent"
+                    }
+                },
                 "trackingUrl": {
                     "type": "string"
                 },
@@ -1197,6 +1909,35 @@ const docTemplate = `{
                 }
             }
         },
+        "model.PaymentAttempt": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "UPI_INTENT",
+                        "UPI_COLLECT",
+                        "CARD",
+                        "WALLET"
+                    ]
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "PENDING",
+                        "PAID",
+                        "FAILED",
+                        "VOIDED"
+                    ]
+                }
+            }
+        },
         "model.PaymentInfo": {
             "type": "object",
             "properties": {
@@ -1205,6 +1946,33 @@ const docTemplate = `{
                 }
             }
         },
+        "model.PaymentRequest": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "UPI_INTENT",
+                        "UPI_COLLECT",
+                        "CARD",
+                        "WALLET"
+                    ],
+                    "example": "UPI_INTENT"
+                },
+                "returnUrl": {
+                    "type": "string",
+                    "example": "https://app.bluechargenet.in/orders/ord-1"
+                },
+                "vpa": {
+                    "type": "string",
+                    "example": "driver@okaxis"
+                },
+                "wallet": {
+                    "type": "string",
+                    "example": "PAYTM"
+                }
+            }
+        },
         "model.PaymentResponse": {
             "type": "object",
             "properties": {
@@ -1217,12 +1985,18 @@ const docTemplate = `{
                 "amount": {
                     "$ref": "#/definitions/model.Amount"
                 },
+                "attempt": {
+                    "$ref": "#/definitions/model.PaymentAttempt"
+                },
                 "beneficiaryId": {
                     "type": "string"
                 },
                 "order": {
                     "$ref": "#/definitions/model.OrderInfo"
                 },
+                "payment": {
+                    "$ref": "#/definitions/model.PaymentInfo"
+                },
                 "paymentUrl": {
                     "type": "string"
                 },
@@ -1231,30 +2005,40 @@ const docTemplate = `{
                 }
             }
         },
-        "model.Price": {
+        "model.PaymentWebhookResponse": {
             "type": "object",
             "properties": {
-                "applicableQuantity": {
-                    "$ref": "#/definitions/model.ApplicableQuantity"
+                "attempt": {
+                    "$ref": "#/definitions/model.PaymentAttempt"
                 },
-                "currency": {
+                "eventId": {
                     "type": "string"
                 },
-                "value": {
-                    "type": "number"
+                "order": {
+                    "$ref": "#/definitions/model.OrderInfo"
+                },
+                "payment": {
+                    "$ref": "#/definitions/model.PaymentInfo"
+                },
+                "refund": {
+                    "$ref": "#/definitions/model.Refund"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "processed",
+                        "duplicate"
+                    ]
                 }
             }
         },
-        "model.PriceComponent": {
+        "model.Price": {
             "type": "object",
             "properties": {
-                "currency": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
+                "applicableQuantity": {
+                    "$ref": "#/definitions/model.ApplicableQuantity"
                 },
-                "type": {
+                "currency": {
                     "type": "string"
                 },
                 "value": {
@@ -1262,22 +2046,18 @@ const docTemplate = `{
                 }
             }
         },
-        "model.PriceComponentFlexible": {
+        "model.PriceBreakdown": {
             "type": "object",
             "properties": {
-                "currency": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "type": {
-                    "type": "string"
+                "subtotal": {
+                    "$ref": "#/definitions/model.Amount"
                 },
-                "value": {}
+                "total": {
+                    "$ref": "#/definitions/model.Amount"
+                }
             }
         },
-        "model.PriceComponentString": {
+        "model.PriceComponent": {
             "type": "object",
             "properties": {
                 "currency": {
@@ -1287,19 +2067,28 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "type": {
-                    "type": "string"
+                    "type": "string",
+                    "enum": [
+                        "BASE",
+                        "UNIT",
+                        "SURCHARGE",
+                        "DISCOUNT",
+                        "FEE",
+                        "TAX",
+                        "IDLE_FEE",
+                        "PAID",
+                        "REFUND"
+                    ]
                 },
                 "value": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "128.64"
                 }
             }
         },
         "model.Provider": {
             "type": "object",
             "properties": {
-                "address": {
-                    "$ref": "#/definitions/model.Address"
-                },
                 "descriptor": {
                     "$ref": "#/definitions/model.ProviderDescriptor"
                 },
@@ -1349,6 +2138,75 @@ const docTemplate = `{
                 }
             }
         },
+        "model.Refund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/model.Amount"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "nextAttemptAt": {
+                    "type": "string"
+                },
+                "paymentAttemptId": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "CANCELLATION",
+                        "UNUSED_PREPAID",
+                        "UNAPPLIED_PAYMENT"
+                    ]
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "PENDING",
+                        "PROCESSING",
+                        "SUCCEEDED",
+                        "FAILED"
+                    ]
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RefundsResponse": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/model.OrderInfo"
+                },
+                "payment": {
+                    "$ref": "#/definitions/model.PaymentInfo"
+                },
+                "refunded": {
+                    "$ref": "#/definitions/model.Amount"
+                },
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Refund"
+                    }
+                }
+            }
+        },
         "model.SearchFilters": {
             "type": "object",
             "properties": {
@@ -1359,7 +2217,17 @@ const docTemplate = `{
                     }
                 },
                 "connector_type": {
-                    "type": "string"
+                    "type": "string",
+                    "enum": [
+                        "TYPE_1",
+                        "TYPE_2",
+                        "CCS1",
+                        "CCS2",
+                        "CHADEMO",
+                        "GBT",
+                        "BHARAT_AC_001",
+                        "BHARAT_DC_001"
+                    ]
                 },
                 "cpo": {
                     "type": "string"
@@ -1390,6 +2258,10 @@ const docTemplate = `{
                         "type": "number"
                     }
                 },
+                "open_now": {
+                    "description": "OpenNow keeps only stations open at search time. It cannot be combined\nwith TimeWindow.",
+                    "type": "boolean"
+                },
                 "sort": {
                     "$ref": "#/definitions/model.SearchSort"
                 },
@@ -1422,10 +2294,20 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "order": {
-                    "type": "string"
+                    "type": "string",
+                    "enum": [
+                        "asc",
+                        "desc"
+                    ]
                 },
                 "sort_by": {
-                    "type": "string"
+                    "type": "string",
+                    "enum": [
+                        "distance",
+                        "price",
+                        "rating",
+                        "power"
+                    ]
                 }
             }
         },
@@ -1460,6 +2342,9 @@ const docTemplate = `{
         "model.StopChargingResponse": {
             "type": "object",
             "properties": {
+                "breakdown": {
+                    "$ref": "#/definitions/model.PriceBreakdown"
+                },
                 "charging": {
                     "$ref": "#/definitions/model.ChargingInfo"
                 },
@@ -1472,7 +2357,7 @@ const docTemplate = `{
                 "priceComponents": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.PriceComponentFlexible"
+                        "$ref": "#/definitions/model.PriceComponent"
                     }
                 },
                 "validity": {
@@ -1483,6 +2368,9 @@ const docTemplate = `{
         "model.StopEstimateResponse": {
             "type": "object",
             "properties": {
+                "breakdown": {
+                    "$ref": "#/definitions/model.PriceBreakdown"
+                },
                 "charging": {
                     "$ref": "#/definitions/model.ChargingInfo"
                 },
@@ -1495,7 +2383,7 @@ const docTemplate = `{
                 "priceComponents": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.PriceComponentString"
+                        "$ref": "#/definitions/model.PriceComponent"
                     }
                 },
                 "validity": {
@@ -1543,6 +2431,29 @@ const docTemplate = `{
                 }
             }
         },
+        "model.UnplugResponse": {
+            "type": "object",
+            "properties": {
+                "breakdown": {
+                    "$ref": "#/definitions/model.PriceBreakdown"
+                },
+                "charging": {
+                    "$ref": "#/definitions/model.ChargingInfo"
+                },
+                "order": {
+                    "$ref": "#/definitions/model.OrderInfo"
+                },
+                "payment": {
+                    "$ref": "#/definitions/model.PaymentInfo"
+                },
+                "priceComponents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PriceComponent"
+                    }
+                }
+            }
+        },
         "model.Validity": {
             "type": "object",
             "properties": {
@@ -1564,9 +2475,172 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "type": {
+                    "type": "string",
+                    "enum": [
+                        "2-wheeler",
+                        "3-wheeler",
+                        "4-wheeler"
+                    ]
+                }
+            }
+        },
+        "model.VehicleSpec": {
+            "type": "object",
+            "properties": {
+                "chargingCurve": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ChargingCurvePoint"
+                    }
+                },
+                "connectors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "make": {
+                    "type": "string"
+                },
+                "maxACPowerKW": {
+                    "type": "number"
+                },
+                "maxDCPowerKW": {
+                    "type": "number"
+                },
+                "model": {
                     "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "2-wheeler",
+                        "3-wheeler",
+                        "4-wheeler"
+                    ]
+                },
+                "usableBatteryKWh": {
+                    "type": "number"
                 }
             }
+        },
+        "model.VehiclesResponse": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "integer"
+                },
+                "vehicles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VehicleSpec"
+                    }
+                }
+            }
+        },
+        "payment.Method": {
+            "type": "string",
+            "enum": [
+                "UPI_INTENT",
+                "UPI_COLLECT",
+                "CARD",
+                "WALLET"
+            ],
+            "x-enum-varnames": [
+                "MethodUPIIntent",
+                "MethodUPICollect",
+                "MethodCard",
+                "MethodWallet"
+            ]
+        },
+        "payment.PSPEvent": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payment": {
+                    "$ref": "#/definitions/payment.PSPPayment"
+                },
+                "refund": {
+                    "$ref": "#/definitions/payment.PSPRefund"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "payment.PSPPayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/model.Amount"
+                },
+                "checkoutUrl": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "method": {
+                    "$ref": "#/definitions/payment.Method"
+                },
+                "orderId": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "payment.PSPRefund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/model.Amount"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "failureReason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "orderId": {
+                    "type": "string"
+                },
+                "paymentId": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Bearer token: \"Bearer \u003cjwt\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
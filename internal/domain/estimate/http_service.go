@@ -0,0 +1,25 @@
+package estimate
+
+import (
+	"context"
+	"net/http"
+
+	"bff-go-mvp/internal/httpclient"
+	"bff-go-mvp/internal/model"
+)
+
+// HTTPService implements Service by forwarding to a downstream REST backend
+// that serves POST /v1/estimate.
+type HTTPService struct {
+	client *httpclient.Client
+}
+
+func NewHTTPService(client *httpclient.Client) *HTTPService {
+	return &HTTPService{client: client}
+}
+
+func (s *HTTPService) Estimate(ctx context.Context, req model.EstimateRequest) (model.EstimateResponse, error) {
+	var resp model.EstimateResponse
+	err := s.client.Do(ctx, http.MethodPost, "/v1/estimate", nil, req, &resp)
+	return resp, err
+}
@@ -2,91 +2,163 @@ package estimate
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/orders"
+	"bff-go-mvp/internal/domain/search"
 	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/pricing"
+	"bff-go-mvp/internal/vehicles"
 )
 
-// MockService implements Service and returns static data that matches
-// the example in swagger.yaml for POST /v1/estimate.
-type MockService struct{}
+// MockService implements Service by pricing the selected offer of the offer
+// book with the pricing engine. The duration and battery gain follow the
+// charging curve of the requested vehicle on the selected connector. Every
+// estimate creates a new order in the shared repository so the later order
+// endpoints can act on it.
+type MockService struct {
+	repo     orders.Repository
+	offers   *OfferBook
+	vehicles *vehicles.Registry
+	policy   pricing.Policy
+}
+
+// MockPolicy holds the surcharges, discounts and fees of the pricing example
+// in swagger.yaml. Cancelling is free up to an hour before the start, then
+// costs 10%, 20% in the last 15 minutes and 30% after the start, at least
+// 10 INR; charger faults and closures waive the fee.
+var MockPolicy = pricing.Policy{
+	Surcharges:  []pricing.Adjustment{{Description: "Surge price", Percent: 20}},
+	Discounts:   []pricing.Adjustment{{Description: "Offer discount", Percent: 15}},
+	ServiceFees: []pricing.Adjustment{{Description: "Service fee", Fixed: 10}},
+	Cancellation: pricing.CancellationPolicy{
+		FreeBefore: time.Hour,
+		Tiers: []pricing.CancellationTier{
+			{Within: time.Hour, Percent: 10},
+			{Within: 15 * time.Minute, Percent: 20},
+			{Within: 0, Percent: 30},
+		},
+		MinimumFee:  10,
+		WaiverCodes: []string{"CHARGER_FAULT", "CHARGER_UNAVAILABLE", "STATION_CLOSED", "OPERATOR_CANCELLED"},
+	},
+}
 
-func NewMockService() *MockService {
-	return &MockService{}
+func NewMockService(repo orders.Repository, offers *OfferBook, registry *vehicles.Registry, policy pricing.Policy) *MockService {
+	return &MockService{repo: repo, offers: offers, vehicles: registry, policy: policy}
 }
 
 func (s *MockService) Estimate(ctx context.Context, req model.EstimateRequest) (model.EstimateResponse, error) {
-	_ = ctx
+	sel, err := s.offers.Select(req)
+	if err != nil {
+		return model.EstimateResponse{}, err
+	}
+	charge, err := s.charge(req, sel.Connector)
+	if err != nil {
+		return model.EstimateResponse{}, err
+	}
+	// Without energy or amount, estimate a 30 kWh session.
+	energy := req.Energy
+	if energy == nil && req.Amount == nil {
+		energy = &model.Energy{Value: 30, Unit: "kWh"}
+	}
+	quote, err := pricing.Price(pricing.Request{
+		Offer:   sel.Offer,
+		Profile: charge,
+		Energy:  energy,
+		Amount:  req.Amount,
+		Policy:  s.policy,
+	})
+	if err != nil {
+		return model.EstimateResponse{}, err
+	}
+	breakdown, err := pricing.Breakdown(quote.Amount.Currency, quote.Components)
+	if err != nil {
+		return model.EstimateResponse{}, apperror.Internal(err)
+	}
 
-	resp := model.EstimateResponse{
-		Order: model.OrderInfo{
-			ID:     "1231208-id",
-			Mode:   "reservation",
-			Status: "quoted_price",
-		},
-		Amount: model.Amount{
-			Value:    128.64,
-			Currency: "INR",
-		},
-		DurationInMinutes:          "15",
-		PercentageOfBatteryCharged: "80",
-		Energy: &model.Energy{
-			Value: 30,
-			Unit:  "kWh",
-		},
+	order := &orders.Order{
+		ID:                         "order-" + uuid.NewString(),
+		Mode:                       orders.ModeReservation,
+		Status:                     orders.StatusQuoted,
+		PaymentStatus:              orders.PaymentPending,
+		ChargingStatus:             orders.ChargingIdle,
+		EvseID:                     req.EvseID,
+		ConnectorID:                req.ConnectorID,
+		OfferID:                    sel.Offer.ID,
+		Offer:                      sel.Offer,
+		Policy:                     s.policy,
+		Vehicle:                    req.Vehicle,
+		TimeWindow:                 req.TimeWindow,
+		Amount:                     quote.Amount,
+		Energy:                     &quote.Energy,
+		DurationInMinutes:          strconv.Itoa(quote.DurationInMinutes),
+		PercentageOfBatteryCharged: strconv.Itoa(int(math.Round(charge.SoCGain(quote.Energy.Value)))),
 		Validity: &model.Validity{
 			StartDate: "2025-01-27T00:00:00Z",
 			EndDate:   "2025-04-27T23:59:59Z",
 		},
-		PriceComponents: []model.PriceComponent{
-			{
-				Type:        "UNIT",
-				Value:       100,
-				Currency:    "INR",
-				Description: "Base charging session cost (100 INR)",
-			},
-			{
-				Type:        "SURCHARGE",
-				Value:       20,
-				Currency:    "INR",
-				Description: "Surge price (20%)",
-			},
-			{
-				Type:        "DISCOUNT",
-				Value:       -15,
-				Currency:    "INR",
-				Description: "Offer discount (15%)",
-			},
-			{
-				Type:        "FEE",
-				Value:       10,
-				Currency:    "INR",
-				Description: "Service fee",
-			},
-			{
-				Type:        "FEE",
-				Value:       13.64,
-				Currency:    "INR",
-				Description: "Overcharge estimation",
-			},
-			{
-				Type:        "Pending Payment",
-				Value:       0.64,
-				Currency:    "INR",
-				Description: "Pending payment",
-			},
-		},
-		Cancellation: &model.CancellationPolicy{
-			Fee: &model.CancellationFee{
-				Percentage: "30",
-			},
-			ExternalRef: &model.ExternalRef{
-				MIMEType: "text/html",
-				URL:      "https://example-company.com/charge/tnc.html",
-			},
+		PriceComponents: quote.Components,
+		Cancellation:    s.policy.Cancellation.Describe(quote.Amount.Currency),
+		AcceptedPaymentMethod: []string{
+			"BankTransfer",
+			"UPI",
+			"Wallet",
 		},
 	}
 
-	return resp, nil
+	order.Cancellation.ExternalRef = &model.ExternalRef{
+		MIMEType: "text/html",
+		URL:      "https://example-company.com/charge/tnc.html",
+	}
+
+	if err := s.repo.Create(ctx, order); err != nil {
+		return model.EstimateResponse{}, err
+	}
+
+	return model.EstimateResponse{
+		Order:                      order.Info(),
+		Amount:                     order.Amount,
+		DurationInMinutes:          order.DurationInMinutes,
+		PercentageOfBatteryCharged: order.PercentageOfBatteryCharged,
+		Energy:                     order.Energy,
+		Validity:                   order.Validity,
+		PriceComponents:            order.PriceComponents,
+		Breakdown:                  breakdown,
+		Cancellation:               order.Cancellation,
+	}, nil
 }
 
+// charge models charging the request's vehicle on conn.
+func (s *MockService) charge(req model.EstimateRequest, conn model.Connector) (*vehicles.Charge, error) {
+	spec, err := s.vehicles.Lookup(req.Vehicle)
+	if err != nil {
+		return nil, err
+	}
+	attrs := conn.ConnectorAttributes
+	if attrs.ConnectorType != "" && !supports(spec, attrs.ConnectorType) {
+		return nil, apperror.Validation(fmt.Sprintf("vehicle %q has no inlet for %s connectors", spec.ID, attrs.ConnectorType)).
+			WithDetail("field", "connector_id")
+	}
+	soc := float64(vehicles.DefaultStateOfCharge)
+	if req.StateOfCharge != nil {
+		soc = *req.StateOfCharge
+	}
+	return vehicles.NewCharge(spec, attrs.MaxPowerKW, strings.EqualFold(attrs.PowerType, "DC"), soc)
+}
 
+func supports(spec model.VehicleSpec, connectorType string) bool {
+	want := search.NormalizeConnectorType(connectorType)
+	for _, t := range spec.Connectors {
+		if search.NormalizeConnectorType(t) == want {
+			return true
+		}
+	}
+	return false
+}
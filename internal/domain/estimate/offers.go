@@ -0,0 +1,98 @@
+package estimate
+
+import (
+	"fmt"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+)
+
+// Selection is the offer an estimate is priced with and the connector it is
+// used on.
+type Selection struct {
+	Offer     model.Offer
+	Connector model.Connector
+}
+
+// OfferBook resolves the offer of an estimate request from a set of
+// catalogs.
+type OfferBook struct {
+	offers      map[string]model.Offer
+	connectors  map[string]model.Connector
+	byConnector map[string]string // connector ID -> first offer covering it
+	fallback    string
+}
+
+// NewOfferBook indexes the offers of catalogs. The first offer is the
+// fallback for requests that name neither an offer nor a known connector.
+// When catalogs repeat an offer or connector ID, the first one wins.
+func NewOfferBook(catalogs ...[]model.Catalog) *OfferBook {
+	b := &OfferBook{
+		offers:      make(map[string]model.Offer),
+		connectors:  make(map[string]model.Connector),
+		byConnector: make(map[string]string),
+	}
+	for _, set := range catalogs {
+		for _, c := range set {
+			for _, conn := range c.Connectors {
+				if _, ok := b.connectors[conn.ID]; !ok {
+					b.connectors[conn.ID] = conn
+				}
+			}
+			for _, o := range c.Offers {
+				if _, ok := b.offers[o.ID]; ok {
+					continue
+				}
+				b.offers[o.ID] = o
+				if b.fallback == "" {
+					b.fallback = o.ID
+				}
+				for _, item := range o.Items {
+					if _, ok := b.byConnector[item]; !ok {
+						b.byConnector[item] = o.ID
+					}
+				}
+			}
+		}
+	}
+	return b
+}
+
+// Select returns the offer of req: offer_id when set, otherwise the offer
+// covering connector_id, otherwise the fallback offer. The connector is
+// connector_id when the offer covers it, else the most powerful of the
+// offer's connectors. An unknown offer_id is a not-found error.
+func (b *OfferBook) Select(req model.EstimateRequest) (Selection, error) {
+	id := req.OfferID
+	if id == "" {
+		id = b.byConnector[req.ConnectorID]
+	}
+	if id == "" {
+		id = b.fallback
+	}
+	if id == "" {
+		return Selection{}, apperror.NotFound("no offer available for the connector").
+			WithDetail("field", "connector_id")
+	}
+	offer, ok := b.offers[id]
+	if !ok {
+		return Selection{}, apperror.NotFound(fmt.Sprintf("offer %q not found", id)).
+			WithDetail("field", "offer_id")
+	}
+
+	sel := Selection{Offer: offer}
+	for _, item := range offer.Items {
+		conn, ok := b.connectors[item]
+		if !ok {
+			continue
+		}
+		if item == req.ConnectorID {
+			sel.Connector = conn
+			break
+		}
+		if conn.ConnectorAttributes.MaxPowerKW > sel.Connector.ConnectorAttributes.MaxPowerKW {
+			sel.Connector = conn
+		}
+	}
+	return sel, nil
+}
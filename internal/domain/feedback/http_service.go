@@ -0,0 +1,26 @@
+package feedback
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"bff-go-mvp/internal/httpclient"
+	"bff-go-mvp/internal/model"
+)
+
+// HTTPService implements Service by forwarding to a downstream REST backend
+// that serves POST /v1/orders/{order_id}/rating.
+type HTTPService struct {
+	client *httpclient.Client
+}
+
+func NewHTTPService(client *httpclient.Client) *HTTPService {
+	return &HTTPService{client: client}
+}
+
+func (s *HTTPService) SetRating(ctx context.Context, orderID string, req model.RatingRequest) (model.RatingResponse, error) {
+	var resp model.RatingResponse
+	err := s.client.Do(ctx, http.MethodPost, "/v1/orders/"+url.PathEscape(orderID)+"/rating", nil, req, &resp)
+	return resp, err
+}
@@ -2,34 +2,43 @@ package feedback
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
+
+	"bff-go-mvp/internal/domain/orders"
 	"bff-go-mvp/internal/model"
 )
 
-// MockService implements Service and returns static data based on swagger example.
-type MockService struct{}
+// MockService implements Service by recording the rating on the order in the
+// shared repository.
+type MockService struct {
+	repo orders.Repository
+}
 
-func NewMockService() *MockService {
-	return &MockService{}
+func NewMockService(repo orders.Repository) *MockService {
+	return &MockService{repo: repo}
 }
 
 func (s *MockService) SetRating(ctx context.Context, orderID string, req model.RatingRequest) (model.RatingResponse, error) {
-	_ = ctx
-	_ = orderID
-	_ = req
+	order, err := s.repo.Update(ctx, orderID, func(o *orders.Order) error {
+		if err := o.Apply(orders.ActionRate, time.Now().UTC()); err != nil {
+			return err
+		}
+		rating := req
+		o.Rating = &rating
+		return nil
+	})
+	if err != nil {
+		return model.RatingResponse{}, err
+	}
 
 	return model.RatingResponse{
-		Order: model.OrderInfo{
-			ID:     "order-bpp-789012",
-			Status: "COMPLETED",
-			Mode:   "RESERVATION",
-		},
+		Order: order.Info(),
 		FeedbackForm: &model.FeedbackForm{
 			URL:          "https://example-bpp.com/feedback/portal",
 			MIMEType:     "application/xml",
-			SubmissionID: "feedback-123e4567-e89b-12d3-a456-426614174000",
+			SubmissionID: "feedback-" + uuid.NewString(),
 		},
 	}, nil
 }
-
-
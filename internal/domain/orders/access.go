@@ -0,0 +1,96 @@
+package orders
+
+import (
+	"context"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/auth"
+)
+
+// Owner identifies the caller an order was created for.
+type Owner struct {
+	UserID string
+	BAPID  string
+}
+
+// Ownership records and looks up order owners.
+type Ownership interface {
+	SetOwner(ctx context.Context, orderID string, owner Owner) error
+	// OwnerOf returns the owner or a NOT_FOUND error for unknown orders.
+	OwnerOf(ctx context.Context, orderID string) (Owner, error)
+}
+
+// SetOwner records the owner on the stored order.
+func (r *MemoryRepository) SetOwner(ctx context.Context, orderID string, owner Owner) error {
+	_, err := r.Update(ctx, orderID, func(o *Order) error {
+		o.Owner = owner
+		return nil
+	})
+	return err
+}
+
+// OwnerOf returns the owner of the stored order.
+func (r *MemoryRepository) OwnerOf(ctx context.Context, orderID string) (Owner, error) {
+	o, err := r.Get(ctx, orderID)
+	if err != nil {
+		return Owner{}, err
+	}
+	return o.Owner, nil
+}
+
+// AccessPolicy decides whether the caller may act on an order. Callers may
+// only reach their own orders unless they hold the operator role. Orders of
+// other users are reported as not found so their existence is not leaked.
+// With authenticated set, requests without a principal are denied.
+type AccessPolicy struct {
+	ownership     Ownership
+	operatorRole  string
+	authenticated bool
+}
+
+func NewAccessPolicy(ownership Ownership, operatorRole string, authenticated bool) *AccessPolicy {
+	return &AccessPolicy{ownership: ownership, operatorRole: operatorRole, authenticated: authenticated}
+}
+
+// Claim records the caller as the owner of a newly created order.
+func (p *AccessPolicy) Claim(ctx context.Context, orderID string) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok && p.authenticated {
+		return apperror.Unauthorized("Authentication required.")
+	}
+	return p.ownership.SetOwner(ctx, orderID, Owner{
+		UserID: principal.Subject,
+		BAPID:  principal.BAPID,
+	})
+}
+
+// Authorize returns a NOT_FOUND error unless the caller owns the order or is
+// an operator. Requests without a principal are allowed only when
+// authentication is disabled, and are UNAUTHORIZED otherwise.
+func (p *AccessPolicy) Authorize(ctx context.Context, orderID string) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	switch {
+	case !ok && p.authenticated:
+		return apperror.Unauthorized("Authentication required.")
+	case !ok, isOperator(ctx, p.operatorRole):
+		return nil
+	}
+
+	owner, err := p.ownership.OwnerOf(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if owner.UserID != principal.Subject {
+		return NotFoundError(orderID)
+	}
+	if owner.BAPID != "" && principal.BAPID != "" && owner.BAPID != principal.BAPID {
+		return NotFoundError(orderID)
+	}
+	return nil
+}
+
+// isOperator reports whether the caller holds operatorRole.
+func isOperator(ctx context.Context, operatorRole string) bool {
+	principal, ok := auth.PrincipalFrom(ctx)
+	return ok && operatorRole != "" && principal.HasRole(operatorRole)
+}
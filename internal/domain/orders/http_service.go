@@ -0,0 +1,121 @@
+package orders
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"bff-go-mvp/internal/httpclient"
+	"bff-go-mvp/internal/model"
+)
+
+// HTTPService implements Service by forwarding to a downstream REST backend
+// that serves GET /v1/orders/{order_id}.
+type HTTPService struct {
+	client *httpclient.Client
+}
+
+func NewHTTPService(client *httpclient.Client) *HTTPService {
+	return &HTTPService{client: client}
+}
+
+func (s *HTTPService) GetOrder(ctx context.Context, orderID string) (model.OrderResponse, error) {
+	var resp model.OrderResponse
+	err := s.client.Do(ctx, http.MethodGet, orderPath(orderID), nil, nil, &resp)
+	return resp, err
+}
+
+// HTTPOwnership implements Ownership for orders created by a downstream REST
+// backend: the owner is stored with the order by the backend, through
+// PUT and GET /v1/orders/{order_id}/owner, so that it survives restarts and
+// is seen by every replica.
+type HTTPOwnership struct {
+	client *httpclient.Client
+}
+
+func NewHTTPOwnership(client *httpclient.Client) *HTTPOwnership {
+	return &HTTPOwnership{client: client}
+}
+
+// ownerBody is the owner as exchanged with the backend.
+type ownerBody struct {
+	UserID string `json:"userId"`
+	BAPID  string `json:"bapId,omitempty"`
+}
+
+func (s *HTTPOwnership) SetOwner(ctx context.Context, orderID string, owner Owner) error {
+	body := ownerBody{UserID: owner.UserID, BAPID: owner.BAPID}
+	return s.client.Do(ctx, http.MethodPut, orderPath(orderID)+"/owner", nil, body, nil)
+}
+
+func (s *HTTPOwnership) OwnerOf(ctx context.Context, orderID string) (Owner, error) {
+	var body ownerBody
+	if err := s.client.Do(ctx, http.MethodGet, orderPath(orderID)+"/owner", nil, nil, &body); err != nil {
+		return Owner{}, err
+	}
+	return Owner{UserID: body.UserID, BAPID: body.BAPID}, nil
+}
+
+// HTTPLifecycleService implements LifecycleService by forwarding the
+// start/stop/unplug/cancel endpoints to a downstream REST backend.
+type HTTPLifecycleService struct {
+	client *httpclient.Client
+}
+
+func NewHTTPLifecycleService(client *httpclient.Client) *HTTPLifecycleService {
+	return &HTTPLifecycleService{client: client}
+}
+
+func (s *HTTPLifecycleService) EstimateCancel(ctx context.Context, orderID, activity, cancelReason, cancelCode string) (model.CancelEstimateResponse, error) {
+	query := url.Values{}
+	setIfNotEmpty(query, "activity", activity)
+	setIfNotEmpty(query, "cancel_reason", cancelReason)
+	setIfNotEmpty(query, "cancel_code", cancelCode)
+
+	var resp model.CancelEstimateResponse
+	err := s.client.Do(ctx, http.MethodGet, orderPath(orderID)+"/cancel", query, nil, &resp)
+	return resp, err
+}
+
+func (s *HTTPLifecycleService) Cancel(ctx context.Context, orderID string, body map[string]interface{}) (model.CancelResponse, error) {
+	var resp model.CancelResponse
+	err := s.client.Do(ctx, http.MethodPost, orderPath(orderID)+"/cancel", nil, body, &resp)
+	return resp, err
+}
+
+func (s *HTTPLifecycleService) EstimateStop(ctx context.Context, orderID, activity string) (model.StopEstimateResponse, error) {
+	query := url.Values{}
+	setIfNotEmpty(query, "activity", activity)
+
+	var resp model.StopEstimateResponse
+	err := s.client.Do(ctx, http.MethodGet, orderPath(orderID)+"/stop", query, nil, &resp)
+	return resp, err
+}
+
+func (s *HTTPLifecycleService) Stop(ctx context.Context, orderID string, req model.StopChargingRequest) (model.StopChargingResponse, error) {
+	var resp model.StopChargingResponse
+	err := s.client.Do(ctx, http.MethodPut, orderPath(orderID)+"/stop", nil, req, &resp)
+	return resp, err
+}
+
+func (s *HTTPLifecycleService) Start(ctx context.Context, orderID string, req model.StartChargingRequest) (model.StartChargingResponse, error) {
+	var resp model.StartChargingResponse
+	err := s.client.Do(ctx, http.MethodPut, orderPath(orderID)+"/start", nil, req, &resp)
+	return resp, err
+}
+
+func (s *HTTPLifecycleService) Unplug(ctx context.Context, orderID string) (model.UnplugResponse, error) {
+	var resp model.UnplugResponse
+	err := s.client.Do(ctx, http.MethodPut, orderPath(orderID)+"/unplug", nil, nil, &resp)
+	return resp, err
+}
+
+func orderPath(orderID string) string {
+	return "/v1/orders/" + url.PathEscape(orderID)
+}
+
+func setIfNotEmpty(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
+}
@@ -2,224 +2,328 @@ package orders
 
 import (
 	"context"
+	"time"
 
+	"bff-go-mvp/internal/apperror"
 	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+	"bff-go-mvp/internal/pricing"
 )
 
-// LifecycleService defines operations for order lifecycle: start, stop, cancel.
+// LifecycleService defines operations for order lifecycle: start, stop,
+// unplug, cancel.
 type LifecycleService interface {
 	EstimateCancel(ctx context.Context, orderID, activity, cancelReason, cancelCode string) (model.CancelEstimateResponse, error)
 	Cancel(ctx context.Context, orderID string, body map[string]interface{}) (model.CancelResponse, error)
 	EstimateStop(ctx context.Context, orderID, activity string) (model.StopEstimateResponse, error)
 	Stop(ctx context.Context, orderID string, req model.StopChargingRequest) (model.StopChargingResponse, error)
 	Start(ctx context.Context, orderID string, req model.StartChargingRequest) (model.StartChargingResponse, error)
+	Unplug(ctx context.Context, orderID string) (model.UnplugResponse, error)
 }
 
-// MockLifecycleService implements LifecycleService using static example data.
-type MockLifecycleService struct{}
+// MockLifecycleService implements LifecycleService against the shared order
+// repository. Every call is checked against the order state machine, and
+// charging telemetry is the static swagger example. Refunds recorded by
+// Cancel and Stop are handed to refunds; with a nil Refunder they stay
+// pending. Cancel codes waive the fee only for callers holding
+// operatorRole.
+type MockLifecycleService struct {
+	repo         Repository
+	refunds      Refunder
+	operatorRole string
+	now          func() time.Time
+}
 
-func NewMockLifecycleService() *MockLifecycleService {
-	return &MockLifecycleService{}
+func NewMockLifecycleService(repo Repository, refunds Refunder, operatorRole string) *MockLifecycleService {
+	return &MockLifecycleService{repo: repo, refunds: refunds, operatorRole: operatorRole, now: time.Now}
 }
 
 func (s *MockLifecycleService) EstimateCancel(ctx context.Context, orderID, activity, cancelReason, cancelCode string) (model.CancelEstimateResponse, error) {
-	_ = ctx
-	_ = orderID
 	_ = activity
-	_ = cancelReason
-	_ = cancelCode
 
+	order, err := s.repo.Get(ctx, orderID)
+	if err != nil {
+		return model.CancelEstimateResponse{}, err
+	}
+	if err := order.Check(ActionCancel); err != nil {
+		return model.CancelEstimateResponse{}, err
+	}
+
+	quote := evaluateCancellation(order, cancelCode, cancelReason, isOperator(ctx, s.operatorRole), s.now().UTC())
+	components := cancellationComponents(order, quote, false)
+	breakdown, err := pricing.Breakdown(order.Amount.Currency, components)
+	if err != nil {
+		return model.CancelEstimateResponse{}, apperror.Internal(err)
+	}
 	return model.CancelEstimateResponse{
-		Order: model.OrderInfo{
-			ID:     "order-bpp-789012",
-			Status: "ACTIVE",
-			Mode:   "RESERVATION",
-		},
-		Payment: &model.PaymentInfo{
-			Status: "PAID",
-		},
-		Charging: &model.ChargingInfo{
-			Status: "ACTIVE",
-		},
-		Validity: &model.Validity{
-			StartDate: "2025-01-27T00:00:00Z",
-			EndDate:   "2025-04-27T23:59:59Z",
-		},
-		PriceComponents: []model.PriceComponentString{
-			{
-				Type:        "PAID",
-				Value:       "400.00",
-				Currency:    "INR",
-				Description: "Base price",
-			},
-			{
-				Type:        "FEE",
-				Value:       "30.00",
-				Currency:    "INR",
-				Description: "Cancellation charges",
-			},
-		},
+		Order:           order.Info(),
+		Payment:         order.PaymentInfo(),
+		Charging:        order.ChargingInfo(),
+		Validity:        order.Validity,
+		PriceComponents: components,
+		Breakdown:       breakdown,
 	}, nil
 }
 
 func (s *MockLifecycleService) Cancel(ctx context.Context, orderID string, body map[string]interface{}) (model.CancelResponse, error) {
-	_ = ctx
-	_ = orderID
-	_ = body
+	code, _ := body["cancel_code"].(string)
+	reason, _ := body["cancel_reason"].(string)
+	operator := isOperator(ctx, s.operatorRole)
+
+	// The fee is priced on the order as it was before cancelling, exactly as
+	// EstimateCancel prices it. What the fee leaves of a payment is refunded.
+	var quote pricing.CancellationQuote
+	var refunded bool
+	order, err := s.repo.Update(ctx, orderID, func(o *Order) error {
+		now := s.now().UTC()
+		quote = evaluateCancellation(o, code, reason, operator, now)
+		if err := o.Apply(ActionCancel, now); err != nil {
+			return err
+		}
+		if o.isPaid() {
+			refunded = o.AddRefund(quote.Refund, RefundReasonCancellation, now) != nil
+		}
+		return nil
+	})
+	if err != nil {
+		return model.CancelResponse{}, err
+	}
+	if refunded {
+		if order, err = s.processRefunds(ctx, orderID); err != nil {
+			return model.CancelResponse{}, err
+		}
+	}
 
+	components := cancellationComponents(order, quote, refunded)
+	breakdown, err := pricing.Breakdown(order.Amount.Currency, components)
+	if err != nil {
+		return model.CancelResponse{}, apperror.Internal(err)
+	}
 	return model.CancelResponse{
-		Order: model.OrderInfo{
-			ID:     "order-bpp-789012",
-			Status: "CANCELLED",
-			Mode:   "RESERVATION",
-		},
-		Payment: &model.PaymentInfo{
-			Status: "PAID",
-		},
-		Charging: &model.ChargingInfo{
-			Status: "ACTIVE",
-		},
-		PriceComponents: []model.PriceComponentString{
-			{
-				Type:        "FEE",
-				Value:       "30.00",
-				Currency:    "INR",
-				Description: "Cancellation charges",
-			},
-			{
-				Type:        "REFUND",
-				Value:       "-300.00",
-				Currency:    "INR",
-				Description: "Cancellation refund",
-			},
-		},
+		Order:           order.Info(),
+		Payment:         order.PaymentInfo(),
+		Charging:        order.ChargingInfo(),
+		PriceComponents: components,
+		Breakdown:       breakdown,
 	}, nil
 }
 
 func (s *MockLifecycleService) EstimateStop(ctx context.Context, orderID, activity string) (model.StopEstimateResponse, error) {
-	_ = ctx
-	_ = orderID
 	_ = activity
 
+	order, err := s.repo.Get(ctx, orderID)
+	if err != nil {
+		return model.StopEstimateResponse{}, err
+	}
+	if err := order.Check(ActionStop); err != nil {
+		return model.StopEstimateResponse{}, err
+	}
+
+	settlement, err := Settle(order, s.now().UTC())
+	if err != nil {
+		return model.StopEstimateResponse{}, err
+	}
 	return model.StopEstimateResponse{
-		Order: model.OrderInfo{
-			ID:     "order-bpp-789012",
-			Status: "ACTIVE",
-			Mode:   "RESERVATION",
-		},
-		Payment: &model.PaymentInfo{
-			Status: "PAID",
-		},
-		Charging: &model.ChargingInfo{
-			Status: "ACTIVE",
-		},
-		Validity: &model.Validity{
-			StartDate: "2025-01-27T00:00:00Z",
-			EndDate:   "2025-04-27T23:59:59Z",
-		},
-		PriceComponents: []model.PriceComponentString{
-			{
-				Type:        "PAID",
-				Value:       "400.00",
-				Currency:    "INR",
-				Description: "Base price",
-			},
-			{
-				Type:        "FEE",
-				Value:       "30.00",
-				Currency:    "INR",
-				Description: "Cancellation charges",
-			},
-			{
-				Type:        "REFUND",
-				Value:       "-300.00",
-				Currency:    "INR",
-				Description: "Cancellation refund",
-			},
-		},
+		Order:           order.Info(),
+		Payment:         order.PaymentInfo(),
+		Charging:        order.ChargingInfo(),
+		Validity:        order.Validity,
+		PriceComponents: settlement.Components,
+		Breakdown:       settlement.Breakdown,
 	}, nil
 }
 
 func (s *MockLifecycleService) Stop(ctx context.Context, orderID string, req model.StopChargingRequest) (model.StopChargingResponse, error) {
-	_ = ctx
-	_ = orderID
 	_ = req
 
+	// The session is billed with the same Settle as the stop estimate; a
+	// failure leaves the session running. The unused prepaid amount is
+	// refunded.
+	var settlement Settlement
+	var refunded bool
+	order, err := s.repo.Update(ctx, orderID, func(o *Order) error {
+		now := s.now().UTC()
+		if err := o.Apply(ActionStop, now); err != nil {
+			return err
+		}
+		if o.ChargingTelemetry != nil {
+			o.ChargingTelemetry.EventTime = now.Format(time.RFC3339)
+		}
+		var err error
+		if settlement, err = Settle(o, now); err != nil {
+			return err
+		}
+		o.Settlement = settlement.Components
+		if o.isPaid() {
+			refunded = o.AddRefund(refundOf(settlement.Components), RefundReasonUnusedPrepaid, now) != nil
+		}
+		return nil
+	})
+	if err != nil {
+		return model.StopChargingResponse{}, err
+	}
+	if refunded {
+		if order, err = s.processRefunds(ctx, orderID); err != nil {
+			return model.StopChargingResponse{}, err
+		}
+	}
+
 	return model.StopChargingResponse{
-		Order: model.OrderInfo{
-			ID:     "order-bpp-789012",
-			Status: "COMPLETED",
-			Mode:   "RESERVATION",
-		},
-		Payment: &model.PaymentInfo{
-			Status: "PAID",
-		},
-		Charging: &model.ChargingInfo{
-			Status: "COMPLETED",
-		},
-		Validity: &model.Validity{
-			StartDate: "2025-01-27T00:00:00Z",
-			EndDate:   "2025-04-27T23:59:59Z",
-		},
-		PriceComponents: []model.PriceComponentFlexible{
+		Order:           order.Info(),
+		Payment:         order.PaymentInfo(),
+		Charging:        order.ChargingInfo(),
+		Validity:        order.Validity,
+		PriceComponents: settlement.Components,
+		Breakdown:       settlement.Breakdown,
+	}, nil
+}
+
+func (s *MockLifecycleService) Start(ctx context.Context, orderID string, req model.StartChargingRequest) (model.StartChargingResponse, error) {
+	_ = req
+
+	order, err := s.repo.Update(ctx, orderID, func(o *Order) error {
+		now := s.now().UTC()
+		if err := o.Apply(ActionStart, now); err != nil {
+			return err
+		}
+		o.TrackingURL = "https://track.bluechargenet-aggregator.io/session/" + o.ID
+		o.ChargingTelemetry = sampleTelemetry(now)
+		return nil
+	})
+	if err != nil {
+		return model.StartChargingResponse{}, err
+	}
+
+	return model.StartChargingResponse{
+		Order:    order.Info(),
+		Payment:  order.PaymentInfo(),
+		Charging: order.ChargingInfo(),
+	}, nil
+}
+
+func (s *MockLifecycleService) Unplug(ctx context.Context, orderID string) (model.UnplugResponse, error) {
+	// Unplugging ends the idle time, so the bill is settled again with the
+	// idle fee.
+	var settlement Settlement
+	order, err := s.repo.Update(ctx, orderID, func(o *Order) error {
+		now := s.now().UTC()
+		if err := o.Apply(ActionUnplug, now); err != nil {
+			return err
+		}
+		var err error
+		if settlement, err = Settle(o, now); err != nil {
+			return err
+		}
+		o.Settlement = settlement.Components
+		return nil
+	})
+	if err != nil {
+		return model.UnplugResponse{}, err
+	}
+
+	return model.UnplugResponse{
+		Order:           order.Info(),
+		Payment:         order.PaymentInfo(),
+		Charging:        order.ChargingInfo(),
+		PriceComponents: settlement.Components,
+		Breakdown:       settlement.Breakdown,
+	}, nil
+}
+
+// processRefunds hands the order's pending refunds to the Refunder and
+// returns the order with their outcome.
+func (s *MockLifecycleService) processRefunds(ctx context.Context, orderID string) (*Order, error) {
+	if s.refunds != nil {
+		s.refunds.ProcessRefunds(ctx, orderID)
+	}
+	return s.repo.Get(ctx, orderID)
+}
+
+// refundOf returns the REFUND component's value among components, or zero.
+func refundOf(components []model.PriceComponent) money.Decimal {
+	for _, c := range components {
+		if c.Type == pricing.ComponentRefund {
+			return c.Value
+		}
+	}
+	return money.Decimal{}
+}
+
+// evaluateCancellation prices cancelling o at now under the cancellation
+// terms booked with it. operator reports whether the caller holds the
+// operator role, whose cancel codes may waive the fee.
+func evaluateCancellation(o *Order, code, reason string, operator bool, now time.Time) pricing.CancellationQuote {
+	paid := model.Amount{Value: money.In(money.Decimal{}, o.Amount.Currency), Currency: o.Amount.Currency}
+	if o.isPaid() {
+		paid = o.Amount
+	}
+	return o.Policy.Cancellation.Evaluate(pricing.Cancellation{
+		Paid:     paid,
+		Start:    o.ReservationStart(),
+		At:       now,
+		Code:     code,
+		Reason:   reason,
+		Operator: operator,
+	})
+}
+
+// cancellationComponents lists the cancellation fee and, when the order was
+// paid, the payment. refunded adds the refund recorded on cancelling, which
+// settles the order.
+func cancellationComponents(o *Order, quote pricing.CancellationQuote, refunded bool) []model.PriceComponent {
+	cur := o.Amount.Currency
+	components := []model.PriceComponent{
+		pricing.Component(pricing.ComponentFee, quote.Fee, cur, "Cancellation charges ("+quote.Rule+")"),
+	}
+	if o.isPaid() {
+		components = append(components, paidComponent(o))
+	}
+	if refunded && quote.Refund.Sign() > 0 {
+		components = append(components,
+			pricing.Component(pricing.ComponentRefund, quote.Refund, cur, "Cancellation refund"))
+	}
+	return components
+}
+
+func paidComponent(o *Order) model.PriceComponent {
+	return pricing.Component(pricing.ComponentPaid, o.Amount.Value.Neg(), o.Amount.Currency, "Amount paid")
+}
+
+// sampleTelemetry returns the swagger example telemetry stamped with now.
+func sampleTelemetry(now time.Time) *model.ChargingTelemetry {
+	return &model.ChargingTelemetry{
+		EventTime: now.Format(time.RFC3339),
+		Metrics: []model.ChargingMetric{
 			{
-				Type:        "BASE",
-				Value:       100,
-				Currency:    "INR",
-				Description: "Base charging session cost (100 INR)",
+				Name:     "STATE_OF_CHARGE",
+				Value:    62.5,
+				UnitCode: "PERCENTAGE",
 			},
 			{
-				Type:        "SURCHARGE",
-				Value:       20,
-				Currency:    "INR",
-				Description: "Surge price (20%)",
+				Name:     "POWER",
+				Value:    18.4,
+				UnitCode: "KW",
 			},
 			{
-				Type:        "DISCOUNT",
-				Value:       -15,
-				Currency:    "INR",
-				Description: "Offer discount (15%)",
+				Name:     MetricEnergy,
+				Value:    10.2,
+				UnitCode: "KWH",
 			},
 			{
-				Type:        "FEE",
-				Value:       10,
-				Currency:    "INR",
-				Description: "Service fee",
+				Name:     "VOLTAGE",
+				Value:    392,
+				UnitCode: "VLT",
 			},
 			{
-				Type:        "FEE",
-				Value:       13.64,
-				Currency:    "INR",
-				Description: "Overcharge estimation",
+				Name:     "CURRENT",
+				Value:    47,
+				UnitCode: "AMP",
 			},
 			{
-				Type:        "REFUND",
-				Value:       "-300.00",
-				Currency:    "INR",
-				Description: "Cancellation refund",
+				Name:     MetricSessionDuration,
+				Value:    10,
+				UnitCode: "min",
 			},
 		},
-	}, nil
-}
-
-func (s *MockLifecycleService) Start(ctx context.Context, orderID string, req model.StartChargingRequest) (model.StartChargingResponse, error) {
-	_ = ctx
-	_ = orderID
-	_ = req
-
-	return model.StartChargingResponse{
-		Order: model.OrderInfo{
-			ID:     "order-bpp-789012",
-			Status: "ACTIVE",
-			Mode:   "RESERVATION",
-		},
-		Payment: &model.PaymentInfo{
-			Status: "PAID",
-		},
-		Charging: &model.ChargingInfo{
-			Status: "ACTIVE",
-		},
-	}, nil
+	}
 }
-
-
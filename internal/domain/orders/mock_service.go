@@ -2,78 +2,48 @@ package orders
 
 import (
 	"context"
+	"time"
 
 	"bff-go-mvp/internal/model"
 )
 
-// MockService implements Service and returns static data that matches
-// the example in swagger.yaml for GET /v1/orders/{order_id}.
-type MockService struct{}
+// MockService implements Service by reading orders from the shared
+// repository.
+type MockService struct {
+	repo Repository
+	now  func() time.Time
+}
 
-func NewMockService() *MockService {
-	return &MockService{}
+func NewMockService(repo Repository) *MockService {
+	return &MockService{repo: repo, now: time.Now}
 }
 
 func (s *MockService) GetOrder(ctx context.Context, orderID string) (model.OrderResponse, error) {
-	_ = ctx
-	_ = orderID
-
+	order, err := s.repo.Get(ctx, orderID)
+	if err != nil {
+		return model.OrderResponse{}, err
+	}
+
+	// A stopped session is billed again so that the idle fee accrues while
+	// the vehicle stays plugged in.
+	var settlement Settlement
+	if len(order.Settlement) > 0 {
+		if settlement, err = Settle(order, s.now().UTC()); err != nil {
+			return model.OrderResponse{}, err
+		}
+	}
+
+	vehicle := order.Vehicle
 	return model.OrderResponse{
-		Order: model.OrderInfo{
-			ID:     "order-bpp-789012",
-			Status: "ACTIVE",
-			Mode:   "RESERVATION",
-		},
-		Payment: &model.PaymentInfo{
-			Status: "PAID",
-		},
-		Charging: &model.ChargingInfo{
-			Status: "ACTIVE",
-		},
-		ConnectorID:   "",
-		ConnectorType: "",
-		Vehicle: &model.Vehicle{
-			Type:  "",
-			Model: "",
-			Make:  "",
-		},
-		TrackingURL: "https://track.bluechargenet-aggregator.io/session/SESSION-9876543210",
-		ChargingTelemetry: &model.ChargingTelemetry{
-			EventTime: "2025-01-27T17:00:00Z",
-			Metrics: []model.ChargingMetric{
-				{
-					Name:     "STATE_OF_CHARGE",
-					Value:    62.5,
-					UnitCode: "PERCENTAGE",
-				},
-				{
-					Name:     "POWER",
-					Value:    18.4,
-					UnitCode: "KWH",
-				},
-				{
-					Name:     "ENERGY",
-					Value:    10.2,
-					UnitCode: "KW",
-				},
-				{
-					Name:     "VOLTAGE",
-					Value:    392,
-					UnitCode: "VLT",
-				},
-				{
-					Name:     "CURRENT",
-					Value:    47,
-					UnitCode: "AMP",
-				},
-				{
-					Name:     "SESSION_DURATION",
-					Value:    10,
-					UnitCode: "min",
-				},
-			},
-		},
+		Order:             order.Info(),
+		Payment:           order.PaymentInfo(),
+		Charging:          order.ChargingInfo(),
+		ConnectorID:       order.ConnectorID,
+		ConnectorType:     order.ConnectorType,
+		Vehicle:           &vehicle,
+		TrackingURL:       order.TrackingURL,
+		ChargingTelemetry: order.ChargingTelemetry,
+		PriceComponents:   settlement.Components,
+		Breakdown:         settlement.Breakdown,
 	}, nil
 }
-
-
@@ -0,0 +1,290 @@
+package orders
+
+import (
+	"time"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/pricing"
+)
+
+// Order status values, following the API documentation enums.
+const (
+	StatusQuoted    = "quoted_price"
+	StatusActive    = "ACTIVE"
+	StatusCompleted = "COMPLETED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Payment status values.
+const (
+	PaymentPending  = "PENDING"
+	PaymentPaid     = "PAID"
+	PaymentFailed   = "FAILED"
+	PaymentRefunded = "REFUNDED"
+	// PaymentVoided marks payment attempts that were still pending when the
+	// order was cancelled. The PSP may capture them later; see
+	// ResolvePaymentAttempt.
+	PaymentVoided = "VOIDED"
+)
+
+// Charging status values.
+const (
+	ChargingIdle      = "IDLE"
+	ChargingActive    = "ACTIVE"
+	ChargingCompleted = "COMPLETED"
+	ChargingStopped   = "STOPPED"
+	ChargingUnplugged = "UNPLUGGED"
+)
+
+// ModeReservation is the only order mode supported today.
+const ModeReservation = "RESERVATION"
+
+// Order is the state the BFF keeps for a charging order, from the first
+// estimate through payment, charging and rating.
+type Order struct {
+	ID             string
+	Owner          Owner
+	Mode           string
+	Status         string
+	PaymentStatus  string
+	ChargingStatus string
+
+	EvseID        string
+	ConnectorID   string
+	ConnectorType string
+	OfferID       string
+	Vehicle       model.Vehicle
+	TimeWindow    *model.TimeWindow
+
+	// Offer and Policy are the tariff and pricing terms booked with the
+	// order. Stopping bills the session with them and cancelling applies
+	// Policy.Cancellation, which Cancellation publishes. They are not
+	// modified after booking.
+	Offer  model.Offer
+	Policy pricing.Policy
+
+	Amount                     model.Amount
+	Energy                     *model.Energy
+	DurationInMinutes          string
+	PercentageOfBatteryCharged string
+	PriceComponents            []model.PriceComponent
+	Validity                   *model.Validity
+	Cancellation               *model.CancellationPolicy
+	AcceptedPaymentMethod      []string
+	// PaymentAttempts are the attempts to collect the payment, oldest
+	// first. PaidAttemptID is the captured attempt the payment status
+	// follows; other captured attempts are refunded in full.
+	PaymentAttempts []PaymentAttempt
+	PaidAttemptID   string
+	// Refunds return money from the captured payment, oldest first.
+	Refunds []Refund
+	// Settlement is the final bill, set when charging stops and again, with
+	// the idle fee, when the connector is unplugged.
+	Settlement []model.PriceComponent
+
+	TrackingURL       string
+	ChargingTelemetry *model.ChargingTelemetry
+	Rating            *model.RatingRequest
+
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	ChargingStartedAt time.Time
+	ChargingEndedAt   time.Time
+	UnpluggedAt       time.Time
+}
+
+// PaymentAttempt is one attempt to collect an order's payment through the
+// payment gateway. Status is PaymentPending until the PSP reports the
+// outcome, then PaymentPaid or PaymentFailed; cancelling the order voids it
+// meanwhile.
+type PaymentAttempt struct {
+	ID     string
+	Method string
+	Amount model.Amount
+	// Provider is the PSP the payment was created at; only its webhooks
+	// report on the attempt.
+	Provider string
+	// Reference is the PSP's payment ID and URL where the buyer pays.
+	Reference string
+	URL       string
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// LastPaymentAttempt returns the latest payment attempt, or nil when
+// payment was never initiated.
+func (o *Order) LastPaymentAttempt() *PaymentAttempt {
+	if len(o.PaymentAttempts) == 0 {
+		return nil
+	}
+	return &o.PaymentAttempts[len(o.PaymentAttempts)-1]
+}
+
+// FindPaymentAttempt returns the payment attempt with the given ID, or nil.
+func (o *Order) FindPaymentAttempt(id string) *PaymentAttempt {
+	for i := range o.PaymentAttempts {
+		if o.PaymentAttempts[i].ID == id {
+			return &o.PaymentAttempts[i]
+		}
+	}
+	return nil
+}
+
+// ResolvePaymentAttempt records the PSP's outcome, PaymentPaid or
+// PaymentFailed, of the pending or voided attempt attemptID. A captured
+// attempt pays the order when it can still be paid. Otherwise the order was
+// cancelled or another attempt paid it, and the capture is refunded in full
+// with reason RefundReasonUnappliedPayment; on a cancelled order that was not
+// paid, the capture becomes its payment. A failed attempt marks the payment
+// FAILED, so that it can be retried, when no other attempt is pending.
+// Reporting the outcome an attempt already has is a no-op.
+func (o *Order) ResolvePaymentAttempt(attemptID, status string, now time.Time) error {
+	a := o.FindPaymentAttempt(attemptID)
+	switch {
+	case a == nil:
+		return apperror.NotFound("payment attempt not found").WithDetail("attempt_id", attemptID)
+	case a.Status == status:
+		return nil
+	case a.Status != PaymentPending && a.Status != PaymentVoided:
+		return apperror.Conflict("payment attempt is already "+a.Status).
+			WithDetail("attempt_id", attemptID).
+			WithDetail("attempt_status", a.Status)
+	}
+
+	switch status {
+	case PaymentPaid:
+		switch {
+		case o.Can(ActionPay):
+			_ = o.Apply(ActionPay, now)
+			o.PaidAttemptID = attemptID
+		default:
+			if !o.isPaid() {
+				o.PaymentStatus, o.PaidAttemptID = PaymentPaid, attemptID
+			}
+			o.addRefund(attemptID, a.Amount.Value, RefundReasonUnappliedPayment, now)
+		}
+	case PaymentFailed:
+		pending := false
+		for _, other := range o.PaymentAttempts {
+			pending = pending || (other.ID != attemptID && other.Status == PaymentPending)
+		}
+		if !pending && o.Status == StatusQuoted && o.PaymentStatus == PaymentPending {
+			o.PaymentStatus = PaymentFailed
+		}
+	default:
+		return apperror.Validation("unsupported payment attempt status " + status)
+	}
+	a.Status, a.UpdatedAt = status, now
+	return nil
+}
+
+// Info returns the order summary embedded in most API responses.
+func (o *Order) Info() model.OrderInfo {
+	return model.OrderInfo{
+		ID:     o.ID,
+		Mode:   o.Mode,
+		Status: o.Status,
+	}
+}
+
+// PaymentInfo returns the payment summary of the order.
+func (o *Order) PaymentInfo() *model.PaymentInfo {
+	return &model.PaymentInfo{Status: o.PaymentStatus}
+}
+
+// ChargingInfo returns the charging summary of the order.
+func (o *Order) ChargingInfo() *model.ChargingInfo {
+	return &model.ChargingInfo{Status: o.ChargingStatus}
+}
+
+// isPaid reports whether the order was paid, including paid and then
+// refunded.
+func (o *Order) isPaid() bool {
+	return o.PaymentStatus == PaymentPaid || o.PaymentStatus == PaymentRefunded
+}
+
+// IdleTime is how long the vehicle stayed plugged in after charging
+// completed, up to the unplug or, while still plugged in, up to now.
+func (o *Order) IdleTime(now time.Time) time.Duration {
+	if o.ChargingEndedAt.IsZero() {
+		return 0
+	}
+	end := now
+	if !o.UnpluggedAt.IsZero() {
+		end = o.UnpluggedAt
+	}
+	if end.Before(o.ChargingEndedAt) {
+		return 0
+	}
+	return end.Sub(o.ChargingEndedAt)
+}
+
+// ReservationStart is the start of the order's time window, or its creation
+// for orders booked without one.
+func (o *Order) ReservationStart() time.Time {
+	if o.TimeWindow != nil {
+		if start, err := time.Parse(time.RFC3339, o.TimeWindow.Start); err == nil {
+			return start
+		}
+	}
+	return o.CreatedAt
+}
+
+// Clone returns a deep copy so callers never share mutable state with the
+// repository.
+func (o *Order) Clone() *Order {
+	c := *o
+	c.Vehicle = o.Vehicle
+	if o.TimeWindow != nil {
+		tw := *o.TimeWindow
+		c.TimeWindow = &tw
+	}
+	if o.Energy != nil {
+		e := *o.Energy
+		c.Energy = &e
+	}
+	c.PriceComponents = append([]model.PriceComponent(nil), o.PriceComponents...)
+	c.Settlement = append([]model.PriceComponent(nil), o.Settlement...)
+	if o.Validity != nil {
+		v := *o.Validity
+		c.Validity = &v
+	}
+	if o.Cancellation != nil {
+		cp := *o.Cancellation
+		if cp.Fee != nil {
+			fee := *cp.Fee
+			cp.Fee = &fee
+		}
+		if cp.ExternalRef != nil {
+			ref := *cp.ExternalRef
+			cp.ExternalRef = &ref
+		}
+		if cp.MinimumFee != nil {
+			minFee := *cp.MinimumFee
+			cp.MinimumFee = &minFee
+		}
+		cp.Tiers = append([]model.CancellationTier(nil), cp.Tiers...)
+		cp.FeeWaivedFor = append([]string(nil), cp.FeeWaivedFor...)
+		c.Cancellation = &cp
+	}
+	c.AcceptedPaymentMethod = append([]string(nil), o.AcceptedPaymentMethod...)
+	c.PaymentAttempts = append([]PaymentAttempt(nil), o.PaymentAttempts...)
+	c.Refunds = append([]Refund(nil), o.Refunds...)
+	if o.ChargingTelemetry != nil {
+		t := *o.ChargingTelemetry
+		t.Metrics = append([]model.ChargingMetric(nil), o.ChargingTelemetry.Metrics...)
+		c.ChargingTelemetry = &t
+	}
+	if o.Rating != nil {
+		r := *o.Rating
+		if r.Feedback != nil {
+			fb := *r.Feedback
+			fb.Tags = append([]string(nil), r.Feedback.Tags...)
+			r.Feedback = &fb
+		}
+		c.Rating = &r
+	}
+	return &c
+}
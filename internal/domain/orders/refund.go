@@ -0,0 +1,150 @@
+package orders
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+)
+
+// Refund status values.
+const (
+	// RefundPending refunds are waiting to be sent to the PSP, for the first
+	// time or, after a failed attempt, at NextAttemptAt.
+	RefundPending = "PENDING"
+	// RefundProcessing refunds were accepted by the PSP, which reports the
+	// outcome later.
+	RefundProcessing = "PROCESSING"
+	RefundSucceeded  = "SUCCEEDED"
+	// RefundFailed refunds failed every attempt.
+	RefundFailed = "FAILED"
+)
+
+// Refund reasons.
+const (
+	RefundReasonCancellation  = "CANCELLATION"
+	RefundReasonUnusedPrepaid = "UNUSED_PREPAID"
+	// RefundReasonUnappliedPayment refunds a payment captured after the order
+	// was cancelled or paid by another attempt.
+	RefundReasonUnappliedPayment = "UNAPPLIED_PAYMENT"
+)
+
+// Refund returns money from an order's captured payment to the buyer.
+// Cancelling a paid order refunds what the cancellation fee leaves, and
+// stopping a session refunds what was prepaid but not used.
+type Refund struct {
+	ID string
+	// PaymentAttemptID is the captured payment attempt refunded.
+	PaymentAttemptID string
+	Amount           model.Amount
+	Reason           string
+	Status           string
+	// Attempts counts the attempts made at the PSP; Reference is the PSP's
+	// refund ID and LastError why the latest attempt failed.
+	Attempts      int
+	Reference     string
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Due reports whether the refund is pending and its next attempt is due at
+// now.
+func (r Refund) Due(now time.Time) bool {
+	return r.Status == RefundPending && !r.NextAttemptAt.After(now)
+}
+
+// Refunder sends an order's pending refunds through the payment gateway.
+// Failed attempts are recorded on the refunds and retried by the Refunder.
+type Refunder interface {
+	ProcessRefunds(ctx context.Context, orderID string)
+}
+
+// AddRefund records a pending refund of amount from the order's captured
+// payment, or returns nil when amount is not positive.
+func (o *Order) AddRefund(amount money.Decimal, reason string, now time.Time) *Refund {
+	return o.addRefund(o.PaidAttemptID, amount, reason, now)
+}
+
+func (o *Order) addRefund(attemptID string, amount money.Decimal, reason string, now time.Time) *Refund {
+	if amount.Sign() <= 0 {
+		return nil
+	}
+	o.Refunds = append(o.Refunds, Refund{
+		ID:               "rfd-" + uuid.NewString(),
+		PaymentAttemptID: attemptID,
+		Amount:           model.Amount{Value: amount, Currency: o.Amount.Currency},
+		Reason:           reason,
+		Status:           RefundPending,
+		NextAttemptAt:    now,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	})
+	return &o.Refunds[len(o.Refunds)-1]
+}
+
+// FindRefund returns the refund with the given ID, or nil.
+func (o *Order) FindRefund(id string) *Refund {
+	for i := range o.Refunds {
+		if o.Refunds[i].ID == id {
+			return &o.Refunds[i]
+		}
+	}
+	return nil
+}
+
+// CompleteRefund marks a refund SUCCEEDED. Once the succeeded refunds of
+// the payment that paid the order add up to the amount it captured, the
+// payment is REFUNDED; until then it stays PAID. Refunds of other captured
+// attempts leave the payment status alone.
+func (o *Order) CompleteRefund(id, reference string, now time.Time) error {
+	r := o.FindRefund(id)
+	switch {
+	case r == nil:
+		return apperror.NotFound("refund not found").WithDetail("refund_id", id)
+	case r.Status == RefundSucceeded:
+		return nil
+	}
+	r.Status, r.LastError, r.NextAttemptAt, r.UpdatedAt = RefundSucceeded, "", time.Time{}, now
+	if reference != "" {
+		r.Reference = reference
+	}
+	refunded := money.In(money.Decimal{}, o.Amount.Currency)
+	for _, other := range o.Refunds {
+		if other.Status == RefundSucceeded && other.PaymentAttemptID == r.PaymentAttemptID {
+			refunded = refunded.Add(other.Amount.Value)
+		}
+	}
+	if r.PaymentAttemptID == o.PaidAttemptID && o.PaymentStatus == PaymentPaid &&
+		refunded.Cmp(o.captured(r.PaymentAttemptID)) >= 0 {
+		o.PaymentStatus = PaymentRefunded
+	}
+	return nil
+}
+
+// captured returns the amount the payment attempt captured, or the order
+// amount when the order was paid without a recorded attempt.
+func (o *Order) captured(attemptID string) money.Decimal {
+	if a := o.FindPaymentAttempt(attemptID); a != nil {
+		return a.Amount.Value
+	}
+	return o.Amount.Value
+}
+
+// refundedFor sums the refunds recorded for reason, reporting false when
+// there are none.
+func (o *Order) refundedFor(reason string) (money.Decimal, bool) {
+	total := money.In(money.Decimal{}, o.Amount.Currency)
+	found := false
+	for _, r := range o.Refunds {
+		if r.Reason == reason {
+			total, found = total.Add(r.Amount.Value), true
+		}
+	}
+	return total, found
+}
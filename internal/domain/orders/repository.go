@@ -0,0 +1,130 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"bff-go-mvp/internal/apperror"
+)
+
+// ErrOrderNotFound is the cause of the NOT_FOUND error returned when no order
+// exists for the given ID.
+var ErrOrderNotFound = errors.New("order not found")
+
+// NotFoundError returns the NOT_FOUND error for an unknown order.
+func NotFoundError(id string) error {
+	return apperror.Wrap(apperror.KindNotFound, "Order not found.", ErrOrderNotFound).
+		WithDetail("order_id", id)
+}
+
+// Repository stores orders shared by the estimate, payment, lifecycle,
+// feedback and support services.
+type Repository interface {
+	// Create stores a new order. The order ID must be set and unused.
+	Create(ctx context.Context, order *Order) error
+	// Get returns a copy of the order or a NOT_FOUND error wrapping
+	// ErrOrderNotFound.
+	Get(ctx context.Context, id string) (*Order, error)
+	// Update applies fn to the stored order atomically and returns a copy of
+	// the result. If fn returns an error the order is left unchanged.
+	Update(ctx context.Context, id string, fn func(*Order) error) (*Order, error)
+	// DueRefunds returns the IDs of the orders with a pending refund due at
+	// now.
+	DueRefunds(ctx context.Context, now time.Time) ([]string, error)
+}
+
+// MemoryRepository is an in-memory Repository safe for concurrent use.
+type MemoryRepository struct {
+	mu     sync.RWMutex
+	orders map[string]*Order
+	now    func() time.Time
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		orders: make(map[string]*Order),
+		now:    time.Now,
+	}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, order *Order) error {
+	_ = ctx
+	if order.ID == "" {
+		return errors.New("order id is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orders[order.ID]; exists {
+		return fmt.Errorf("order %s already exists", order.ID)
+	}
+
+	stored := order.Clone()
+	now := r.now().UTC()
+	stored.CreatedAt = now
+	stored.UpdatedAt = now
+	r.orders[order.ID] = stored
+	return nil
+}
+
+// Check reports whether the store can serve requests. The in-memory store
+// is always available once the lock can be taken.
+func (r *MemoryRepository) Check(ctx context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return ctx.Err()
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, id string) (*Order, error) {
+	_ = ctx
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.orders[id]
+	if !ok {
+		return nil, NotFoundError(id)
+	}
+	return stored.Clone(), nil
+}
+
+func (r *MemoryRepository) Update(ctx context.Context, id string, fn func(*Order) error) (*Order, error) {
+	_ = ctx
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.orders[id]
+	if !ok {
+		return nil, NotFoundError(id)
+	}
+
+	working := stored.Clone()
+	if err := fn(working); err != nil {
+		return nil, err
+	}
+	working.ID = id
+	working.UpdatedAt = r.now().UTC()
+	r.orders[id] = working
+	return working.Clone(), nil
+}
+
+func (r *MemoryRepository) DueRefunds(ctx context.Context, now time.Time) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ids []string
+	for id, o := range r.orders {
+		for _, rf := range o.Refunds {
+			if rf.Due(now) {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	return ids, ctx.Err()
+}
@@ -0,0 +1,128 @@
+package orders
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/pricing"
+)
+
+// Charging telemetry metrics read by Settle.
+const (
+	MetricEnergy          = "ENERGY"
+	MetricSessionDuration = "SESSION_DURATION"
+)
+
+// Telemetry units, as factors to kWh and minutes.
+var (
+	energyUnits   = map[string]float64{"": 1, "KWH": 1, "WH": 0.001}
+	durationUnits = map[string]float64{"": 1, "MIN": 1, "SEC": 1.0 / 60, "HUR": 60}
+)
+
+// Settlement is the final bill of a charging session.
+type Settlement struct {
+	Usage pricing.Usage
+	// Idle is the time the vehicle stayed plugged in after charging.
+	Idle time.Duration
+	// Components bill the usage under the booked offer and policy, and the
+	// idle fee, then settle it against the amount paid: a payment, and a
+	// refund when the payment exceeds the bill (or the refund the stop
+	// recorded). Breakdown.Total is the balance still due.
+	Components []model.PriceComponent
+	Breakdown  *model.PriceBreakdown
+}
+
+// Settle bills o's session at now from its recorded telemetry: the ENERGY
+// and SESSION_DURATION metrics, priced with the booked offer and policy,
+// plus the offer's idle fee for the time between charging completing and
+// the connector being unplugged (or now, while it is still plugged in).
+// Stop estimates, stops and unplugs all settle with it, so a preview agrees
+// with the charge when the telemetry has not moved on.
+func Settle(o *Order, now time.Time) (Settlement, error) {
+	if o.Offer.ID == "" {
+		return Settlement{}, apperror.Conflict("order has no booked offer to bill").
+			WithDetail("order_id", o.ID)
+	}
+	usage, err := usageOf(o.ChargingTelemetry)
+	if err != nil {
+		return Settlement{}, apperror.Conflict(err.Error()).WithDetail("order_id", o.ID)
+	}
+	quote, err := pricing.PriceUsage(o.Offer, o.Policy, usage)
+	if err != nil {
+		return Settlement{}, apperror.Internal(err)
+	}
+
+	idleFee, err := pricing.IdleFeeOf(o.Offer)
+	if err != nil {
+		return Settlement{}, apperror.Internal(err)
+	}
+
+	cur := quote.Amount.Currency
+	components := quote.Components
+	idle := o.IdleTime(now)
+	if idleFee != nil {
+		if c, ok := pricing.IdleCharge(*idleFee, idle); ok {
+			components = append(components, c)
+		}
+	}
+	if o.isPaid() {
+		components = append(components, paidComponent(o))
+	}
+	breakdown, err := pricing.Breakdown(cur, components)
+	if err != nil {
+		return Settlement{}, apperror.Internal(err)
+	}
+	// Once the stop has recorded the refund, the bill shows it as made, so
+	// an idle fee billed later is left to pay.
+	refund, refunded := o.refundedFor(RefundReasonUnusedPrepaid)
+	if !refunded {
+		refund = breakdown.Total.Value.Neg()
+	}
+	if refund.Sign() > 0 {
+		components = append(components,
+			pricing.Component(pricing.ComponentRefund, refund, cur, "Refund of unused prepaid amount"))
+		if breakdown, err = pricing.Breakdown(cur, components); err != nil {
+			return Settlement{}, apperror.Internal(err)
+		}
+	}
+	return Settlement{Usage: usage, Idle: idle, Components: components, Breakdown: breakdown}, nil
+}
+
+// usageOf reads the energy delivered and the session duration from
+// telemetry. Energy is in KWH (the default) or WH; duration in MIN (the
+// default), SEC or HUR.
+func usageOf(t *model.ChargingTelemetry) (pricing.Usage, error) {
+	if t == nil {
+		return pricing.Usage{}, fmt.Errorf("no charging telemetry recorded")
+	}
+	var u pricing.Usage
+	var haveEnergy, haveDuration bool
+	for _, m := range t.Metrics {
+		switch strings.ToUpper(m.Name) {
+		case MetricEnergy:
+			factor, ok := energyUnits[strings.ToUpper(m.UnitCode)]
+			if !ok {
+				return pricing.Usage{}, fmt.Errorf("unsupported %s unit %q", MetricEnergy, m.UnitCode)
+			}
+			u.Energy, haveEnergy = m.Value*factor, true
+		case MetricSessionDuration:
+			factor, ok := durationUnits[strings.ToUpper(m.UnitCode)]
+			if !ok {
+				return pricing.Usage{}, fmt.Errorf("unsupported %s unit %q", MetricSessionDuration, m.UnitCode)
+			}
+			u.Minutes, haveDuration = m.Value*factor, true
+		}
+	}
+	switch {
+	case !haveEnergy:
+		return pricing.Usage{}, fmt.Errorf("charging telemetry has no %s reading", MetricEnergy)
+	case !haveDuration:
+		return pricing.Usage{}, fmt.Errorf("charging telemetry has no %s reading", MetricSessionDuration)
+	case u.Energy < 0 || u.Minutes < 0:
+		return pricing.Usage{}, fmt.Errorf("charging telemetry has negative readings")
+	}
+	return u, nil
+}
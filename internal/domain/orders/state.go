@@ -0,0 +1,209 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bff-go-mvp/internal/apperror"
+)
+
+// Action is an operation that moves an order through its lifecycle.
+type Action string
+
+const (
+	ActionPay    Action = "payment"
+	ActionStart  Action = "start"
+	ActionStop   Action = "stop"
+	ActionUnplug Action = "unplug"
+	ActionCancel Action = "cancel"
+	ActionRate   Action = "rating"
+)
+
+// actionOrder is the order in which allowed actions are reported.
+var actionOrder = []Action{ActionPay, ActionStart, ActionStop, ActionUnplug, ActionCancel, ActionRate}
+
+// State is the combined order, payment and charging status of an order.
+type State struct {
+	Order    string
+	Payment  string
+	Charging string
+}
+
+func (s State) String() string {
+	return fmt.Sprintf("order=%s payment=%s charging=%s", s.Order, s.Payment, s.Charging)
+}
+
+// ErrInvalidTransition is matched by every TransitionError.
+var ErrInvalidTransition = errors.New("invalid order state transition")
+
+// conflictMessages are the documented 409 messages for each action.
+var conflictMessages = map[Action]string{
+	ActionPay:    "Payment already processed or order in invalid state for payment.",
+	ActionStart:  "Order cannot be started in its current state or charging already in progress.",
+	ActionStop:   "Charging session cannot be stopped in its current state.",
+	ActionUnplug: "Connector cannot be unplugged before charging completes or after it was unplugged.",
+	ActionCancel: "Order cannot be cancelled in its current state.",
+	ActionRate:   "Order cannot be rated in its current state.",
+}
+
+// TransitionError reports an action that is not allowed in the order's
+// current state, along with the actions that are.
+type TransitionError struct {
+	OrderID string
+	Action  Action
+	State   State
+	Allowed []Action
+}
+
+func (e *TransitionError) Error() string {
+	allowed := make([]string, 0, len(e.Allowed))
+	for _, a := range e.Allowed {
+		allowed = append(allowed, string(a))
+	}
+	return fmt.Sprintf("order %s: cannot %s in state %s (allowed: %s)",
+		e.OrderID, e.Action, e.State, strings.Join(allowed, ", "))
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// AppError returns the CONFLICT error reported to clients, listing the
+// current state and the allowed actions.
+func (e *TransitionError) AppError() *apperror.Error {
+	allowed := make([]string, 0, len(e.Allowed))
+	for _, a := range e.Allowed {
+		allowed = append(allowed, string(a))
+	}
+	return apperror.Wrap(apperror.KindConflict, conflictMessages[e.Action], e).
+		WithDetail("order_id", e.OrderID).
+		WithDetail("action", string(e.Action)).
+		WithDetail("order_status", e.State.Order).
+		WithDetail("payment_status", e.State.Payment).
+		WithDetail("charging_status", e.State.Charging).
+		WithDetail("allowed_actions", allowed)
+}
+
+// transition guards and applies one action.
+type transition struct {
+	allowed func(State) bool
+	apply   func(o *Order, now time.Time)
+}
+
+var transitions = map[Action]transition{
+	// Payment settles a quoted order; a failed payment may be retried.
+	ActionPay: {
+		allowed: func(s State) bool {
+			return s.Order == StatusQuoted && (s.Payment == PaymentPending || s.Payment == PaymentFailed)
+		},
+		apply: func(o *Order, _ time.Time) {
+			o.Status = StatusActive
+			o.PaymentStatus = PaymentPaid
+		},
+	},
+	// Charging starts once on a paid, active order.
+	ActionStart: {
+		allowed: func(s State) bool {
+			return s.Order == StatusActive && s.Payment == PaymentPaid && s.Charging == ChargingIdle
+		},
+		apply: func(o *Order, now time.Time) {
+			o.ChargingStatus = ChargingActive
+			o.ChargingStartedAt = now
+		},
+	},
+	// Stopping an active session completes the order.
+	ActionStop: {
+		allowed: func(s State) bool {
+			return s.Order == StatusActive && s.Charging == ChargingActive
+		},
+		apply: func(o *Order, now time.Time) {
+			o.Status = StatusCompleted
+			o.ChargingStatus = ChargingCompleted
+			o.ChargingEndedAt = now
+		},
+	},
+	// Unplugging after a completed session ends the idle time billed as the
+	// idle fee.
+	ActionUnplug: {
+		allowed: func(s State) bool {
+			return s.Order == StatusCompleted && s.Charging == ChargingCompleted
+		},
+		apply: func(o *Order, now time.Time) {
+			o.ChargingStatus = ChargingUnplugged
+			o.UnpluggedAt = now
+		},
+	},
+	// Orders can be cancelled before charging starts. Paid orders stay PAID
+	// until the refund that cancelling records completes, and pending payment
+	// attempts are voided.
+	ActionCancel: {
+		allowed: func(s State) bool {
+			return s.Order == StatusQuoted || (s.Order == StatusActive && s.Charging == ChargingIdle)
+		},
+		apply: func(o *Order, now time.Time) {
+			o.Status = StatusCancelled
+			for i := range o.PaymentAttempts {
+				if a := &o.PaymentAttempts[i]; a.Status == PaymentPending {
+					a.Status, a.UpdatedAt = PaymentVoided, now
+				}
+			}
+		},
+	},
+	// Only completed sessions can be rated; a later rating replaces the earlier one.
+	ActionRate: {
+		allowed: func(s State) bool {
+			return s.Order == StatusCompleted
+		},
+		apply: func(*Order, time.Time) {},
+	},
+}
+
+// State returns the order's combined status.
+func (o *Order) State() State {
+	return State{Order: o.Status, Payment: o.PaymentStatus, Charging: o.ChargingStatus}
+}
+
+// AllowedActions lists the actions permitted in the order's current state.
+func (o *Order) AllowedActions() []Action {
+	s := o.State()
+	allowed := []Action{}
+	for _, a := range actionOrder {
+		if transitions[a].allowed(s) {
+			allowed = append(allowed, a)
+		}
+	}
+	return allowed
+}
+
+// Can reports whether the action is permitted in the order's current state.
+func (o *Order) Can(action Action) bool {
+	t, ok := transitions[action]
+	return ok && t.allowed(o.State())
+}
+
+// Check returns a CONFLICT error wrapping a *TransitionError if the action
+// is not permitted.
+func (o *Order) Check(action Action) error {
+	if o.Can(action) {
+		return nil
+	}
+	te := &TransitionError{
+		OrderID: o.ID,
+		Action:  action,
+		State:   o.State(),
+		Allowed: o.AllowedActions(),
+	}
+	return te.AppError()
+}
+
+// Apply performs the action, updating the order's statuses, or returns the
+// error from Check leaving the order unchanged.
+func (o *Order) Apply(action Action, now time.Time) error {
+	if err := o.Check(action); err != nil {
+		return err
+	}
+	transitions[action].apply(o, now)
+	return nil
+}
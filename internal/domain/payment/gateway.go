@@ -0,0 +1,266 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+)
+
+// Method is how the buyer pays an order.
+type Method string
+
+// Payment methods. Each is served by its own gateway adapter.
+const (
+	// MethodUPIIntent opens the buyer's UPI app with a upi:// link.
+	MethodUPIIntent Method = "UPI_INTENT"
+	// MethodUPICollect sends a collect request to the buyer's VPA.
+	MethodUPICollect Method = "UPI_COLLECT"
+	// MethodCard and MethodWallet pay on the PSP's checkout page.
+	MethodCard   Method = "CARD"
+	MethodWallet Method = "WALLET"
+)
+
+// Methods lists the supported payment methods.
+var Methods = []Method{MethodUPIIntent, MethodUPICollect, MethodCard, MethodWallet}
+
+// acceptedAs maps a method to the offer's acceptedPaymentMethod entry that
+// allows it.
+var acceptedAs = map[Method]string{
+	MethodUPIIntent:  "UPI",
+	MethodUPICollect: "UPI",
+	MethodCard:       "CARD",
+	MethodWallet:     "WALLET",
+}
+
+// Initiation is a payment to be collected for an order.
+type Initiation struct {
+	OrderID string
+	// AttemptID identifies this payment attempt at the PSP.
+	AttemptID string
+	Method    Method
+	// Amount is the order's quoted amount.
+	Amount model.Amount
+	// VPA is the buyer's UPI address, required for UPI collect.
+	VPA string
+	// Wallet optionally names the wallet to pay with.
+	Wallet string
+	// ReturnURL is where the buyer is sent after checkout.
+	ReturnURL string
+}
+
+// Session is a payment created at the PSP.
+type Session struct {
+	// Reference is the PSP's payment ID.
+	Reference string
+	// URL is where the buyer pays: a upi:// intent link or a checkout page.
+	URL string
+	// Captured reports that the PSP collected the payment on creation.
+	Captured bool
+}
+
+// RefundInitiation is money to return from a captured payment.
+type RefundInitiation struct {
+	OrderID string
+	// RefundID identifies the refund at the PSP across retries.
+	RefundID string
+	// PaymentID is the PSP's ID of the captured payment.
+	PaymentID string
+	Amount    model.Amount
+	Reason    string
+}
+
+// RefundSession is a refund created at the PSP.
+type RefundSession struct {
+	// Reference is the PSP's refund ID.
+	Reference string
+	// Status is PSPRefundSucceeded, PSPRefundFailed (with FailureReason),
+	// or PSPRefundPending when the PSP reports the outcome later.
+	Status        string
+	FailureReason string
+}
+
+// Gateway collects a payment with one payment method and refunds it the
+// same way.
+type Gateway interface {
+	Initiate(ctx context.Context, in Initiation) (Session, error)
+	Refund(ctx context.Context, in RefundInitiation) (RefundSession, error)
+}
+
+// Gateways selects the gateway adapter of each payment method.
+type Gateways map[Method]Gateway
+
+// NewGateways returns the adapters of every method, all backed by psp.
+// UPI intent links are addressed to the merchant.
+func NewGateways(psp PSP, merchant Merchant) Gateways {
+	return Gateways{
+		MethodUPIIntent:  UPIIntentGateway{PSP: psp, Merchant: merchant},
+		MethodUPICollect: UPICollectGateway{PSP: psp},
+		MethodCard:       CardGateway{PSP: psp},
+		MethodWallet:     WalletGateway{PSP: psp},
+	}
+}
+
+// Merchant is the payee of UPI intent links.
+type Merchant struct {
+	VPA  string
+	Name string
+}
+
+// UPIIntentGateway creates the payment at the PSP and returns a upi:// link
+// that opens the buyer's UPI app with the payment filled in.
+type UPIIntentGateway struct {
+	PSP      PSP
+	Merchant Merchant
+}
+
+func (g UPIIntentGateway) Initiate(ctx context.Context, in Initiation) (Session, error) {
+	p, err := g.PSP.CreatePayment(ctx, pspRequest(in))
+	if err != nil {
+		return Session{}, err
+	}
+	q := url.Values{}
+	q.Set("pa", g.Merchant.VPA)
+	q.Set("pn", g.Merchant.Name)
+	q.Set("tr", p.ID)
+	q.Set("tn", "Order "+in.OrderID)
+	q.Set("am", in.Amount.Value.String())
+	q.Set("cu", in.Amount.Currency)
+	return Session{Reference: p.ID, URL: "upi://pay?" + q.Encode(), Captured: p.Captured()}, nil
+}
+
+func (g UPIIntentGateway) Refund(ctx context.Context, in RefundInitiation) (RefundSession, error) {
+	return refund(ctx, g.PSP, in)
+}
+
+// UPICollectGateway asks the PSP to send a collect request to the buyer's
+// VPA; the URL is the PSP's status page for it.
+type UPICollectGateway struct {
+	PSP PSP
+}
+
+func (g UPICollectGateway) Initiate(ctx context.Context, in Initiation) (Session, error) {
+	if !validVPA(in.VPA) {
+		return Session{}, apperror.Validation("vpa must be a UPI address such as name@bank for UPI_COLLECT").
+			WithDetail("field", "vpa")
+	}
+	p, err := g.PSP.CreatePayment(ctx, pspRequest(in))
+	if err != nil {
+		return Session{}, err
+	}
+	return Session{Reference: p.ID, URL: p.CheckoutURL, Captured: p.Captured()}, nil
+}
+
+func (g UPICollectGateway) Refund(ctx context.Context, in RefundInitiation) (RefundSession, error) {
+	return refund(ctx, g.PSP, in)
+}
+
+// CardGateway sends the buyer to the PSP's card checkout page.
+type CardGateway struct {
+	PSP PSP
+}
+
+func (g CardGateway) Initiate(ctx context.Context, in Initiation) (Session, error) {
+	p, err := g.PSP.CreatePayment(ctx, pspRequest(in))
+	if err != nil {
+		return Session{}, err
+	}
+	return Session{Reference: p.ID, URL: p.CheckoutURL, Captured: p.Captured()}, nil
+}
+
+func (g CardGateway) Refund(ctx context.Context, in RefundInitiation) (RefundSession, error) {
+	return refund(ctx, g.PSP, in)
+}
+
+// WalletGateway sends the buyer to the PSP's checkout page, preselecting
+// the requested wallet.
+type WalletGateway struct {
+	PSP PSP
+}
+
+func (g WalletGateway) Initiate(ctx context.Context, in Initiation) (Session, error) {
+	p, err := g.PSP.CreatePayment(ctx, pspRequest(in))
+	if err != nil {
+		return Session{}, err
+	}
+	return Session{Reference: p.ID, URL: p.CheckoutURL, Captured: p.Captured()}, nil
+}
+
+func (g WalletGateway) Refund(ctx context.Context, in RefundInitiation) (RefundSession, error) {
+	return refund(ctx, g.PSP, in)
+}
+
+// refund asks the PSP to refund a payment; it routes the money back the
+// way the payment came: to the payer's VPA, card or wallet.
+func refund(ctx context.Context, psp PSP, in RefundInitiation) (RefundSession, error) {
+	r, err := psp.CreateRefund(ctx, PSPRefundRequest{
+		Reference: in.RefundID,
+		PaymentID: in.PaymentID,
+		OrderID:   in.OrderID,
+		Amount:    in.Amount,
+		Reason:    in.Reason,
+	})
+	if err != nil {
+		return RefundSession{}, err
+	}
+	return RefundSession{Reference: r.ID, Status: r.Status, FailureReason: r.FailureReason}, nil
+}
+
+func pspRequest(in Initiation) PSPRequest {
+	return PSPRequest{
+		Reference: in.AttemptID,
+		OrderID:   in.OrderID,
+		Method:    in.Method,
+		Amount:    in.Amount,
+		VPA:       in.VPA,
+		Wallet:    in.Wallet,
+		ReturnURL: in.ReturnURL,
+	}
+}
+
+// validVPA reports whether vpa looks like a UPI address, handle@provider.
+func validVPA(vpa string) bool {
+	handle, provider, ok := strings.Cut(vpa, "@")
+	return ok && handle != "" && provider != "" && !strings.ContainsAny(vpa, " /?&#")
+}
+
+// resolveMethod returns the requested method, or the first method the
+// order accepts, and checks that the order accepts it.
+func resolveMethod(requested string, accepted []string) (Method, error) {
+	allowed := func(m Method) bool {
+		if len(accepted) == 0 {
+			return true
+		}
+		for _, a := range accepted {
+			if strings.EqualFold(a, acceptedAs[m]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if requested == "" {
+		for _, m := range Methods {
+			if allowed(m) {
+				return m, nil
+			}
+		}
+		return "", apperror.Conflict("order accepts none of the supported payment methods").
+			WithDetail("accepted_payment_methods", accepted)
+	}
+
+	m := Method(strings.ToUpper(requested))
+	if _, ok := acceptedAs[m]; !ok {
+		return "", apperror.Validation(fmt.Sprintf("method %q is not supported", requested)).
+			WithDetail("field", "method")
+	}
+	if !allowed(m) {
+		return "", apperror.Validation(fmt.Sprintf("method %s is not accepted for this order", m)).
+			WithDetail("field", "method").
+			WithDetail("accepted_payment_methods", accepted)
+	}
+	return m, nil
+}
@@ -0,0 +1,40 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"bff-go-mvp/internal/httpclient"
+	"bff-go-mvp/internal/model"
+)
+
+// HTTPService implements Service by forwarding to a downstream REST backend
+// that serves POST /v1/orders/{order_id}/payment, GET
+// /v1/orders/{order_id}/refunds and, for verified events, POST
+// /v1/webhooks/payments/{provider}.
+type HTTPService struct {
+	client *httpclient.Client
+}
+
+func NewHTTPService(client *httpclient.Client) *HTTPService {
+	return &HTTPService{client: client}
+}
+
+func (s *HTTPService) InitiatePayment(ctx context.Context, orderID string, req model.PaymentRequest) (model.PaymentResponse, error) {
+	var resp model.PaymentResponse
+	err := s.client.Do(ctx, http.MethodPost, "/v1/orders/"+url.PathEscape(orderID)+"/payment", nil, req, &resp)
+	return resp, err
+}
+
+func (s *HTTPService) HandleEvent(ctx context.Context, provider string, event PSPEvent) (model.PaymentWebhookResponse, error) {
+	var resp model.PaymentWebhookResponse
+	err := s.client.Do(ctx, http.MethodPost, "/v1/webhooks/payments/"+url.PathEscape(provider), nil, event, &resp)
+	return resp, err
+}
+
+func (s *HTTPService) ListRefunds(ctx context.Context, orderID string) (model.RefundsResponse, error) {
+	var resp model.RefundsResponse
+	err := s.client.Do(ctx, http.MethodGet, "/v1/orders/"+url.PathEscape(orderID)+"/refunds", nil, nil, &resp)
+	return resp, err
+}
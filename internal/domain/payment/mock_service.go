@@ -2,48 +2,265 @@ package payment
 
 import (
 	"context"
+	"net/url"
+	"time"
 
+	"github.com/google/uuid"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/orders"
 	"bff-go-mvp/internal/model"
 )
 
-// MockService implements Service and returns static data that matches
-// the example in swagger.yaml for POST /v1/orders/{order_id}/payment.
-type MockService struct{}
+// MockService implements Service against the shared order repository,
+// collecting payments through the gateway adapter of the chosen method.
+// Each initiation records a payment attempt on the order. The order becomes
+// ACTIVE once the PSP captures the payment, which the fake PSP does on
+// creation when auto-capture is on, or else when its webhook reports the
+// capture (see HandleEvent).
+type MockService struct {
+	repo     orders.Repository
+	gateways Gateways
+	merchant Merchant
+	// provider names the PSP behind the gateways in webhook paths.
+	provider string
+	refunds  *RefundService
+	now      func() time.Time
+}
 
-func NewMockService() *MockService {
-	return &MockService{}
+func NewMockService(repo orders.Repository, gateways Gateways, merchant Merchant, provider string, refunds *RefundService) *MockService {
+	return &MockService{repo: repo, gateways: gateways, merchant: merchant, provider: provider, refunds: refunds, now: time.Now}
 }
 
-func (s *MockService) InitiatePayment(ctx context.Context, orderID string, body map[string]interface{}) (model.PaymentResponse, error) {
-	_ = ctx
-	_ = body
+func (s *MockService) InitiatePayment(ctx context.Context, orderID string, req model.PaymentRequest) (model.PaymentResponse, error) {
+	order, err := s.repo.Get(ctx, orderID)
+	if err != nil {
+		return model.PaymentResponse{}, err
+	}
+	if err := order.Check(orders.ActionPay); err != nil {
+		return model.PaymentResponse{}, err
+	}
+	method, err := resolveMethod(req.Method, order.AcceptedPaymentMethod)
+	if err != nil {
+		return model.PaymentResponse{}, err
+	}
+	if req.ReturnURL != "" {
+		if u, err := url.Parse(req.ReturnURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return model.PaymentResponse{}, apperror.Validation("returnUrl must be an absolute http(s) URL").
+				WithDetail("field", "returnUrl")
+		}
+	}
 
-	resp := model.PaymentResponse{
-		Order: model.OrderInfo{
-			ID:     "order-bpp-789012",
-			Status: "ACTIVE",
-			Mode:   "RESERVATION",
-		},
-		Amount: model.Amount{
-			Value:    128.64,
-			Currency: "INR",
-		},
-		BeneficiaryID: "",
-		AcceptedPaymentMethod: []string{
-			"BankTransfer",
-			"UPI",
-			"Wallet",
-		},
-		PaymentURL: "",
-		Validity: &model.Validity{
-			StartDate: "2025-01-27T00:00:00Z",
-			EndDate:   "2025-04-27T23:59:59Z",
-		},
+	gateway, ok := s.gateways[method]
+	if !ok {
+		return model.PaymentResponse{}, apperror.Validation("method "+string(method)+" is not available").
+			WithDetail("field", "method")
 	}
 
-	_ = orderID // in a real impl this would be used
+	// The attempt is reserved on the order before the PSP is called, so that
+	// concurrent initiations cannot each create a payment. Initiating again
+	// with the same method resumes the pending attempt instead of asking the
+	// buyer to pay twice.
+	var attempt orders.PaymentAttempt
+	resumed := false
+	order, err = s.repo.Update(ctx, orderID, func(o *orders.Order) error {
+		if err := o.Check(orders.ActionPay); err != nil {
+			return err
+		}
+		for _, a := range o.PaymentAttempts {
+			if a.Status == orders.PaymentPending && a.Reference == "" {
+				return apperror.Conflict("payment initiation is already in progress").
+					WithDetail("attempt_id", a.ID)
+			}
+		}
+		if a := o.LastPaymentAttempt(); a != nil && a.Status == orders.PaymentPending && a.Method == string(method) {
+			attempt, resumed = *a, true
+			return nil
+		}
+		now := s.now().UTC()
+		attempt = orders.PaymentAttempt{
+			ID:        "pay-" + uuid.NewString(),
+			Method:    string(method),
+			Amount:    o.Amount,
+			Provider:  s.provider,
+			Status:    orders.PaymentPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		o.PaymentAttempts = append(o.PaymentAttempts, attempt)
+		return nil
+	})
+	if err != nil {
+		return model.PaymentResponse{}, err
+	}
+	if resumed {
+		return s.response(order, order.FindPaymentAttempt(attempt.ID)), nil
+	}
 
-	return resp, nil
+	session, err := gateway.Initiate(ctx, Initiation{
+		OrderID:   order.ID,
+		AttemptID: attempt.ID,
+		Method:    method,
+		Amount:    attempt.Amount,
+		VPA:       req.VPA,
+		Wallet:    req.Wallet,
+		ReturnURL: req.ReturnURL,
+	})
+	if err != nil {
+		s.release(ctx, orderID, attempt.ID)
+		return model.PaymentResponse{}, err
+	}
+
+	order, err = s.repo.Update(ctx, orderID, func(o *orders.Order) error {
+		a := o.FindPaymentAttempt(attempt.ID)
+		if a == nil {
+			return apperror.NotFound("payment attempt not found").WithDetail("attempt_id", attempt.ID)
+		}
+		now := s.now().UTC()
+		a.Reference, a.URL, a.UpdatedAt = session.Reference, session.URL, now
+		if session.Captured {
+			return o.ResolvePaymentAttempt(a.ID, orders.PaymentPaid, now)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.PaymentResponse{}, err
+	}
+	order = s.sendRefunds(ctx, order)
+	return s.response(order, order.FindPaymentAttempt(attempt.ID)), nil
+}
+
+// release drops the reservation of an attempt the PSP did not create, so
+// that payment can be initiated again.
+func (s *MockService) release(ctx context.Context, orderID, attemptID string) {
+	_, _ = s.repo.Update(context.WithoutCancel(ctx), orderID, func(o *orders.Order) error {
+		for i, a := range o.PaymentAttempts {
+			if a.ID == attemptID && a.Reference == "" {
+				o.PaymentAttempts = append(o.PaymentAttempts[:i], o.PaymentAttempts[i+1:]...)
+				break
+			}
+		}
+		return nil
+	})
 }
 
+// HandleEvent applies a PSP event to the payment attempt it reports on: a
+// capture pays the order and a failure lets the buyer retry. The event must
+// come from the provider the attempt was created at and match its PSP
+// payment and, for a capture, its amount. Refund events go to the refund
+// service, which keeps the refunds the payment status follows.
+func (s *MockService) HandleEvent(ctx context.Context, provider string, event PSPEvent) (model.PaymentWebhookResponse, error) {
+	if event.Type == EventRefundSucceeded || event.Type == EventRefundFailed {
+		return s.handleRefundEvent(ctx, provider, event)
+	}
+	p := event.Payment
+	if event.ID == "" || p.OrderID == "" || p.Reference == "" {
+		return model.PaymentWebhookResponse{}, apperror.Validation("id, payment.orderId and payment.reference are required")
+	}
+	var status string
+	switch event.Type {
+	case EventPaymentCaptured:
+		status = orders.PaymentPaid
+	case EventPaymentFailed:
+		status = orders.PaymentFailed
+	default:
+		return model.PaymentWebhookResponse{}, apperror.Validation("unsupported event type").WithDetail("type", event.Type)
+	}
 
+	order, err := s.repo.Update(ctx, p.OrderID, func(o *orders.Order) error {
+		a := o.FindPaymentAttempt(p.Reference)
+		switch {
+		case a == nil:
+			return apperror.NotFound("payment attempt not found").WithDetail("attempt_id", p.Reference)
+		case a.Provider != provider:
+			return apperror.Conflict("payment attempt belongs to another provider").
+				WithDetail("attempt_id", a.ID).
+				WithDetail("provider", provider)
+		case a.Reference != p.ID:
+			return apperror.Conflict("payment does not belong to the attempt").
+				WithDetail("attempt_id", a.ID).
+				WithDetail("payment_id", p.ID)
+		case status == orders.PaymentPaid && (p.Amount.Currency != a.Amount.Currency || p.Amount.Value.Cmp(a.Amount.Value) != 0):
+			return apperror.Conflict("captured amount does not match the attempt").
+				WithDetail("attempt_id", a.ID).
+				WithDetail("expected", a.Amount.Value.String()+" "+a.Amount.Currency)
+		}
+		return o.ResolvePaymentAttempt(a.ID, status, s.now().UTC())
+	})
+	if err != nil {
+		return model.PaymentWebhookResponse{}, err
+	}
+	order = s.sendRefunds(ctx, order)
+	info := order.Info()
+	a := order.FindPaymentAttempt(p.Reference)
+	return model.PaymentWebhookResponse{
+		EventID: event.ID,
+		Status:  "processed",
+		Order:   &info,
+		Payment: order.PaymentInfo(),
+		Attempt: attemptInfo(a),
+	}, nil
+}
+
+// sendRefunds sends the refund recorded when a capture could no longer pay
+// the order, and returns the order with its outcome. The refund sweep
+// retries it when the order cannot be read back.
+func (s *MockService) sendRefunds(ctx context.Context, order *orders.Order) *orders.Order {
+	now := s.now().UTC()
+	for _, r := range order.Refunds {
+		if !r.Due(now) {
+			continue
+		}
+		s.refunds.ProcessRefunds(ctx, order.ID)
+		if updated, err := s.repo.Get(ctx, order.ID); err == nil {
+			return updated
+		}
+		break
+	}
+	return order
+}
+
+func (s *MockService) handleRefundEvent(ctx context.Context, provider string, event PSPEvent) (model.PaymentWebhookResponse, error) {
+	if event.ID == "" {
+		return model.PaymentWebhookResponse{}, apperror.Validation("id is required")
+	}
+	order, refund, err := s.refunds.HandleEvent(ctx, provider, event)
+	if err != nil {
+		return model.PaymentWebhookResponse{}, err
+	}
+	info, r := order.Info(), refundInfo(*refund)
+	return model.PaymentWebhookResponse{
+		EventID: event.ID,
+		Status:  "processed",
+		Order:   &info,
+		Payment: order.PaymentInfo(),
+		Refund:  &r,
+	}, nil
+}
+
+// ListRefunds lists the order's refunds from the refund service.
+func (s *MockService) ListRefunds(ctx context.Context, orderID string) (model.RefundsResponse, error) {
+	return s.refunds.ListRefunds(ctx, orderID)
+}
+
+func attemptInfo(a *orders.PaymentAttempt) *model.PaymentAttempt {
+	return &model.PaymentAttempt{
+		ID:        a.ID,
+		Method:    a.Method,
+		Status:    a.Status,
+		Reference: a.Reference,
+	}
+}
+
+func (s *MockService) response(o *orders.Order, a *orders.PaymentAttempt) model.PaymentResponse {
+	return model.PaymentResponse{
+		Order:                 o.Info(),
+		Payment:               o.PaymentInfo(),
+		Amount:                o.Amount,
+		BeneficiaryID:         s.merchant.VPA,
+		AcceptedPaymentMethod: o.AcceptedPaymentMethod,
+		PaymentURL:            a.URL,
+		Attempt:               attemptInfo(a),
+		Validity:              o.Validity,
+	}
+}
@@ -0,0 +1,138 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"bff-go-mvp/internal/httpclient"
+	"bff-go-mvp/internal/model"
+)
+
+// PSP payment statuses.
+const (
+	PSPStatusCreated  = "CREATED"
+	PSPStatusCaptured = "CAPTURED"
+	PSPStatusFailed   = "FAILED"
+	PSPStatusRefunded = "REFUNDED"
+)
+
+// PSP refund statuses.
+const (
+	PSPRefundPending   = "PENDING"
+	PSPRefundSucceeded = "SUCCEEDED"
+	PSPRefundFailed    = "FAILED"
+)
+
+// PSP event types, sent to the PSP's callback URL when a payment's status
+// changes.
+const (
+	EventPaymentCaptured = "payment.captured"
+	EventPaymentFailed   = "payment.failed"
+	EventRefundSucceeded = "refund.succeeded"
+	EventRefundFailed    = "refund.failed"
+)
+
+// PSPEvent is a PSP callback about a payment. The PSP signs it with the
+// provider's webhook secret (see package webhook); Payment.Reference is the
+// BFF's payment attempt ID. Refund events also carry the refund.
+type PSPEvent struct {
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Payment   PSPPayment `json:"payment"`
+	Refund    *PSPRefund `json:"refund,omitempty"`
+}
+
+// PSP is the payment service provider API the gateway adapters call. It is
+// served over HTTP by a PSP (see PSPClient) or in-process by the fake PSP.
+type PSP interface {
+	// CreatePayment creates a payment. A payment requested again with the
+	// same reference is returned as it is, so the reference is the
+	// idempotency key of a payment attempt.
+	CreatePayment(ctx context.Context, req PSPRequest) (PSPPayment, error)
+	// CreateRefund refunds part or all of a captured payment. A refund
+	// requested again with the same reference is returned as it is, unless
+	// it failed.
+	CreateRefund(ctx context.Context, req PSPRefundRequest) (PSPRefund, error)
+	// Check reports whether the PSP is reachable; it is the payment_gateway
+	// readiness check.
+	Check(ctx context.Context) error
+}
+
+// PSPRequest creates a payment at the PSP.
+type PSPRequest struct {
+	// Reference is the BFF's payment attempt ID.
+	Reference string       `json:"reference"`
+	OrderID   string       `json:"orderId"`
+	Method    Method       `json:"method"`
+	Amount    model.Amount `json:"amount"`
+	VPA       string       `json:"vpa,omitempty"`
+	Wallet    string       `json:"wallet,omitempty"`
+	ReturnURL string       `json:"returnUrl,omitempty"`
+}
+
+// PSPPayment is a payment at the PSP.
+type PSPPayment struct {
+	ID          string       `json:"id"`
+	Reference   string       `json:"reference"`
+	OrderID     string       `json:"orderId"`
+	Method      Method       `json:"method"`
+	Amount      model.Amount `json:"amount"`
+	Status      string       `json:"status"`
+	CheckoutURL string       `json:"checkoutUrl"`
+	CreatedAt   time.Time    `json:"createdAt"`
+}
+
+// PSPRefundRequest refunds a payment at the PSP.
+type PSPRefundRequest struct {
+	// Reference is the BFF's refund ID.
+	Reference string       `json:"reference"`
+	PaymentID string       `json:"paymentId"`
+	OrderID   string       `json:"orderId"`
+	Amount    model.Amount `json:"amount"`
+	Reason    string       `json:"reason,omitempty"`
+}
+
+// PSPRefund is a refund at the PSP.
+type PSPRefund struct {
+	ID            string       `json:"id"`
+	Reference     string       `json:"reference"`
+	PaymentID     string       `json:"paymentId"`
+	OrderID       string       `json:"orderId"`
+	Amount        model.Amount `json:"amount"`
+	Status        string       `json:"status"`
+	FailureReason string       `json:"failureReason,omitempty"`
+	CreatedAt     time.Time    `json:"createdAt"`
+}
+
+// Captured reports whether the PSP collected the payment.
+func (p PSPPayment) Captured() bool {
+	return p.Status == PSPStatusCaptured
+}
+
+// PSPClient implements PSP against a PSP's REST API:
+// POST /v1/payments creates a payment and POST /v1/refunds a refund.
+type PSPClient struct {
+	client *httpclient.Client
+}
+
+func NewPSPClient(client *httpclient.Client) *PSPClient {
+	return &PSPClient{client: client}
+}
+
+func (c *PSPClient) CreatePayment(ctx context.Context, req PSPRequest) (PSPPayment, error) {
+	var p PSPPayment
+	err := c.client.Do(ctx, http.MethodPost, "/v1/payments", nil, req, &p)
+	return p, err
+}
+
+func (c *PSPClient) CreateRefund(ctx context.Context, req PSPRefundRequest) (PSPRefund, error) {
+	var r PSPRefund
+	err := c.client.Do(ctx, http.MethodPost, "/v1/refunds", nil, req, &r)
+	return r, err
+}
+
+func (c *PSPClient) Check(ctx context.Context) error {
+	return c.client.Check(ctx)
+}
@@ -0,0 +1,268 @@
+package payment
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/orders"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+)
+
+// RetryPolicy bounds refund attempts. A failed attempt is retried after
+// Backoff, doubling after each further failure, until MaxAttempts were made.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// delay returns how long to wait after the given number of failed attempts.
+func (p RetryPolicy) delay(attempts int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+	}
+	return d
+}
+
+// attemptTimeout bounds one refund attempt at the PSP. A refund is claimed
+// for as long before it is sent, so that it is not sent twice at once and,
+// when the outcome of the attempt was never recorded, is sent again after.
+const attemptTimeout = 30 * time.Second
+
+// RefundService sends the refunds recorded on orders through the gateway
+// adapter of the refunded payment attempt's method. It implements
+// orders.Refunder: a refund that fails is retried under the retry policy
+// until it succeeds or runs out of attempts. Retries are driven from the
+// order store by Run, so they survive a restart.
+type RefundService struct {
+	repo     orders.Repository
+	gateways Gateways
+	retry    RetryPolicy
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+func NewRefundService(repo orders.Repository, gateways Gateways, retry RetryPolicy, logger *zap.Logger) *RefundService {
+	return &RefundService{repo: repo, gateways: gateways, retry: retry, logger: logger, now: time.Now}
+}
+
+// ProcessRefunds sends the order's pending refunds that are due. They are
+// sent on a context detached from ctx, so that the end of the request that
+// recorded them does not abandon them.
+func (s *RefundService) ProcessRefunds(ctx context.Context, orderID string) {
+	ctx = context.WithoutCancel(ctx)
+	now := s.now().UTC()
+	var due []orders.Refund
+	order, err := s.repo.Update(ctx, orderID, func(o *orders.Order) error {
+		due = due[:0]
+		for i := range o.Refunds {
+			if r := &o.Refunds[i]; r.Due(now) {
+				r.NextAttemptAt = now.Add(attemptTimeout)
+				due = append(due, *r)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn("refunds not processed", zap.String("order_id", orderID), zap.Error(err))
+		return
+	}
+	for _, r := range due {
+		s.attempt(ctx, order, r)
+	}
+}
+
+// Run processes the due refunds of every order each interval until ctx is
+// done: retries of failed attempts and attempts whose outcome was never
+// recorded.
+func (s *RefundService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep(ctx)
+		}
+	}
+}
+
+// Sweep processes the due refunds of every order once. It stops between
+// orders when ctx is done; the refunds of an order already claimed are
+// still sent.
+func (s *RefundService) Sweep(ctx context.Context) {
+	ids, err := s.repo.DueRefunds(ctx, s.now().UTC())
+	if err != nil {
+		s.logger.Warn("due refunds not listed", zap.Error(err))
+		return
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		s.ProcessRefunds(ctx, id)
+	}
+}
+
+// attempt sends refund r to the PSP and records the outcome.
+func (s *RefundService) attempt(ctx context.Context, order *orders.Order, r orders.Refund) {
+	sendCtx, cancel := context.WithTimeout(ctx, attemptTimeout)
+	session, err := s.send(sendCtx, order, r)
+	cancel()
+	_, uerr := s.repo.Update(ctx, order.ID, func(o *orders.Order) error {
+		rf := o.FindRefund(r.ID)
+		if rf == nil || rf.Status != orders.RefundPending {
+			return nil
+		}
+		now := s.now().UTC()
+		rf.Attempts++
+		rf.UpdatedAt = now
+		if session.Reference != "" {
+			rf.Reference = session.Reference
+		}
+		switch {
+		case err != nil:
+			s.fail(rf, err.Error(), now)
+		case session.Status == PSPRefundFailed:
+			s.fail(rf, session.FailureReason, now)
+		case session.Status == PSPRefundSucceeded:
+			return o.CompleteRefund(rf.ID, session.Reference, now)
+		default:
+			rf.Status, rf.LastError = orders.RefundProcessing, ""
+		}
+		return nil
+	})
+	if uerr != nil {
+		s.logger.Error("refund outcome not recorded",
+			zap.String("order_id", order.ID), zap.String("refund_id", r.ID), zap.Error(uerr))
+		return
+	}
+	if err != nil {
+		s.logger.Warn("refund attempt failed",
+			zap.String("order_id", order.ID), zap.String("refund_id", r.ID), zap.Error(err))
+	}
+}
+
+// send refunds r through the gateway of the payment attempt it refunds.
+func (s *RefundService) send(ctx context.Context, o *orders.Order, r orders.Refund) (RefundSession, error) {
+	a := o.FindPaymentAttempt(r.PaymentAttemptID)
+	if a == nil || a.Status != orders.PaymentPaid {
+		return RefundSession{}, apperror.Conflict("order has no captured payment to refund").
+			WithDetail("refund_id", r.ID)
+	}
+	gateway, ok := s.gateways[Method(a.Method)]
+	if !ok {
+		return RefundSession{}, apperror.Conflict("method "+a.Method+" is not available").
+			WithDetail("refund_id", r.ID)
+	}
+	return gateway.Refund(ctx, RefundInitiation{
+		OrderID:   o.ID,
+		RefundID:  r.ID,
+		PaymentID: a.Reference,
+		Amount:    r.Amount,
+		Reason:    r.Reason,
+	})
+}
+
+// fail records a failed attempt of rf at now: it is retried after the
+// backoff of the retry policy, or FAILED when it has no attempts left.
+func (s *RefundService) fail(rf *orders.Refund, reason string, now time.Time) {
+	rf.LastError = reason
+	if rf.Attempts >= s.retry.MaxAttempts {
+		rf.Status, rf.NextAttemptAt = orders.RefundFailed, time.Time{}
+		return
+	}
+	rf.Status, rf.NextAttemptAt = orders.RefundPending, now.Add(s.retry.delay(rf.Attempts))
+}
+
+// HandleEvent applies a refund.succeeded or refund.failed event from the
+// PSP to the refund it reports on. The event must come from the provider of
+// the refunded payment. A failure is retried like a failed attempt.
+func (s *RefundService) HandleEvent(ctx context.Context, provider string, event PSPEvent) (*orders.Order, *orders.Refund, error) {
+	r := event.Refund
+	if r == nil || r.OrderID == "" || r.Reference == "" {
+		return nil, nil, apperror.Validation("refund.orderId and refund.reference are required")
+	}
+	order, err := s.repo.Update(ctx, r.OrderID, func(o *orders.Order) error {
+		rf := o.FindRefund(r.Reference)
+		switch {
+		case rf == nil:
+			return apperror.NotFound("refund not found").WithDetail("refund_id", r.Reference)
+		case !paidAt(o, rf.PaymentAttemptID, provider):
+			return apperror.Conflict("refund belongs to another provider").
+				WithDetail("refund_id", rf.ID).
+				WithDetail("provider", provider)
+		case rf.Reference != "" && rf.Reference != r.ID:
+			return apperror.Conflict("refund does not match the PSP refund").
+				WithDetail("refund_id", rf.ID).
+				WithDetail("psp_refund_id", r.ID)
+		}
+		now := s.now().UTC()
+		if event.Type == EventRefundSucceeded {
+			return o.CompleteRefund(rf.ID, r.ID, now)
+		}
+		if rf.Status != orders.RefundProcessing {
+			// Failures of attempts that were already recorded are not counted
+			// twice.
+			return nil
+		}
+		rf.UpdatedAt = now
+		s.fail(rf, r.FailureReason, now)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, order.FindRefund(r.Reference), nil
+}
+
+// paidAt reports whether the payment attempt was created at provider.
+func paidAt(o *orders.Order, attemptID, provider string) bool {
+	a := o.FindPaymentAttempt(attemptID)
+	return a != nil && a.Provider == provider
+}
+
+// ListRefunds returns the order's refunds, oldest first.
+func (s *RefundService) ListRefunds(ctx context.Context, orderID string) (model.RefundsResponse, error) {
+	order, err := s.repo.Get(ctx, orderID)
+	if err != nil {
+		return model.RefundsResponse{}, err
+	}
+	resp := model.RefundsResponse{
+		Order:    order.Info(),
+		Payment:  order.PaymentInfo(),
+		Refunds:  make([]model.Refund, 0, len(order.Refunds)),
+		Refunded: model.Amount{Value: money.In(money.Decimal{}, order.Amount.Currency), Currency: order.Amount.Currency},
+	}
+	for _, r := range order.Refunds {
+		resp.Refunds = append(resp.Refunds, refundInfo(r))
+		if r.Status == orders.RefundSucceeded {
+			resp.Refunded.Value = resp.Refunded.Value.Add(r.Amount.Value)
+		}
+	}
+	return resp, nil
+}
+
+func refundInfo(r orders.Refund) model.Refund {
+	info := model.Refund{
+		ID:               r.ID,
+		Status:           r.Status,
+		Amount:           r.Amount,
+		Reason:           r.Reason,
+		PaymentAttemptID: r.PaymentAttemptID,
+		Reference:        r.Reference,
+		Attempts:         r.Attempts,
+		LastError:        r.LastError,
+		CreatedAt:        r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        r.UpdatedAt.Format(time.RFC3339),
+	}
+	if r.Status == orders.RefundPending && r.Attempts > 0 {
+		info.NextAttemptAt = r.NextAttemptAt.Format(time.RFC3339)
+	}
+	return info
+}
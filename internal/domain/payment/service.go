@@ -6,9 +6,13 @@ import (
 	"bff-go-mvp/internal/model"
 )
 
-// Service defines the behavior for the payment initiation use case.
+// Service defines the behavior for the payment initiation use case, the
+// payment provider's events about those payments and the refunds of them.
 type Service interface {
-	InitiatePayment(ctx context.Context, orderID string, body map[string]interface{}) (model.PaymentResponse, error)
+	InitiatePayment(ctx context.Context, orderID string, req model.PaymentRequest) (model.PaymentResponse, error)
+	// HandleEvent applies a PSP event whose signature was verified.
+	HandleEvent(ctx context.Context, provider string, event PSPEvent) (model.PaymentWebhookResponse, error)
+	ListRefunds(ctx context.Context, orderID string) (model.RefundsResponse, error)
 }
 
 
@@ -0,0 +1,210 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	// Station time zones must resolve even where the host has no zoneinfo.
+	_ "time/tzdata"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+)
+
+// DefaultTimezone is the time zone of availability windows for catalogs that
+// do not name one.
+const DefaultTimezone = "Asia/Kolkata"
+
+// lookahead bounds how far ahead the next opening is searched.
+const lookahead = 8 * 24 * time.Hour
+
+// maxTimeWindow bounds the length of a time_window. Opening hours are
+// expanded day by day over the window for every station.
+const maxTimeWindow = 31 * 24 * time.Hour
+
+// timeRange is the period a station must be open for. An open_now search
+// uses an empty range at the current time.
+type timeRange struct {
+	from, to time.Time
+}
+
+// parseTimeRange validates the request's time_window and open_now. It
+// returns nil when neither is set.
+func parseTimeRange(req model.SearchRequest, now time.Time) (*timeRange, error) {
+	tw := req.TimeWindow
+	hasWindow := tw != nil && (tw.Start != "" || tw.End != "")
+
+	if req.OpenNow {
+		if hasWindow {
+			return nil, apperror.Validation("open_now cannot be combined with time_window").
+				WithDetail("field", "open_now")
+		}
+		return &timeRange{from: now, to: now}, nil
+	}
+	if !hasWindow {
+		return nil, nil
+	}
+
+	if tw.Start == "" {
+		return nil, apperror.Validation("time_window.start is required when time_window.end is set").
+			WithDetail("field", "time_window.start")
+	}
+	from, err := time.Parse(time.RFC3339, tw.Start)
+	if err != nil {
+		return nil, invalidTime("time_window.start", tw.Start)
+	}
+	to := from
+	if tw.End != "" {
+		if to, err = time.Parse(time.RFC3339, tw.End); err != nil {
+			return nil, invalidTime("time_window.end", tw.End)
+		}
+		if to.Before(from) {
+			return nil, apperror.Validation("time_window.end must not be before time_window.start").
+				WithDetail("field", "time_window.end")
+		}
+		if to.Sub(from) > maxTimeWindow {
+			return nil, apperror.Validation("time_window must not be longer than 31 days").
+				WithDetail("field", "time_window.end")
+		}
+	}
+	return &timeRange{from: from, to: to}, nil
+}
+
+func invalidTime(field, value string) *apperror.Error {
+	return apperror.Validation(fmt.Sprintf("%s %q is not an RFC 3339 date-time", field, value)).
+		WithDetail("field", field)
+}
+
+// clock is a time of day.
+type clock struct {
+	hour, min, sec int
+}
+
+func parseClock(s string) (clock, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return clock{t.Hour(), t.Minute(), t.Second()}, nil
+		}
+	}
+	return clock{}, fmt.Errorf("invalid time of day %q", s)
+}
+
+func (c clock) seconds() int {
+	return c.hour*3600 + c.min*60 + c.sec
+}
+
+// on returns the clock time on the given local day.
+func (c clock) on(year int, month time.Month, day int, loc *time.Location) time.Time {
+	return time.Date(year, month, day, c.hour, c.min, c.sec, 0, loc)
+}
+
+// dailyWindow is one daily opening period. A window whose end is not after
+// its start runs overnight into the next day; equal ends mean 24 hours.
+type dailyWindow struct {
+	start, end clock
+}
+
+// openingHours are the daily windows of a station in its local time zone.
+// A station without windows is always open.
+type openingHours struct {
+	loc     *time.Location
+	windows []dailyWindow
+}
+
+// hoursOf reads a catalog's availability windows. It fails on an unknown
+// time zone or an unparseable window, in which case callers treat the
+// station's hours as unknown.
+func hoursOf(cat model.Catalog) (openingHours, error) {
+	tz := cat.Timezone
+	if tz == "" {
+		tz = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return openingHours{}, fmt.Errorf("catalog %q: %w", cat.ID, err)
+	}
+
+	h := openingHours{loc: loc}
+	for _, w := range cat.AvailabilityWindow {
+		start, err := parseClock(w.StartTime)
+		if err != nil {
+			return openingHours{}, fmt.Errorf("catalog %q: %w", cat.ID, err)
+		}
+		end, err := parseClock(w.EndTime)
+		if err != nil {
+			return openingHours{}, fmt.Errorf("catalog %q: %w", cat.ID, err)
+		}
+		h.windows = append(h.windows, dailyWindow{start: start, end: end})
+	}
+	return h, nil
+}
+
+// interval is an opening period [open, close).
+type interval struct {
+	open, close time.Time
+}
+
+// intervals returns the merged opening periods from the day before from until
+// lookahead after to.
+func (h openingHours) intervals(from, to time.Time) []interval {
+	first := from.In(h.loc).AddDate(0, 0, -1)
+	last := to.Add(lookahead).In(h.loc)
+
+	var out []interval
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		y, m, d := day.Date()
+		for _, w := range h.windows {
+			iv := interval{open: w.start.on(y, m, d, h.loc)}
+			switch s, e := w.start.seconds(), w.end.seconds(); {
+			case e == s:
+				iv.close = w.start.on(y, m, d+1, h.loc)
+			case e == 23*3600+59*60+59:
+				// "23:59:59" closes at midnight.
+				iv.close = time.Date(y, m, d+1, 0, 0, 0, 0, h.loc)
+			case e < s:
+				iv.close = w.end.on(y, m, d+1, h.loc)
+			default:
+				iv.close = w.end.on(y, m, d, h.loc)
+			}
+			out = append(out, iv)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].open.Before(out[j].open) })
+	merged := out[:0]
+	for _, iv := range out {
+		if n := len(merged); n > 0 && !iv.open.After(merged[n-1].close) {
+			if iv.close.After(merged[n-1].close) {
+				merged[n-1].close = iv.close
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
+
+// openThroughout reports whether the station is open for all of r.
+func (h openingHours) openThroughout(r timeRange) bool {
+	if len(h.windows) == 0 {
+		return true
+	}
+	for _, iv := range h.intervals(r.from, r.to) {
+		if !r.from.Before(iv.open) && r.from.Before(iv.close) && !r.to.After(iv.close) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextOpening returns when a station closed at t next opens, in its local
+// time zone.
+func (h openingHours) nextOpening(t time.Time) (time.Time, bool) {
+	for _, iv := range h.intervals(t, t) {
+		if iv.open.After(t) {
+			return iv.open, true
+		}
+	}
+	return time.Time{}, false
+}
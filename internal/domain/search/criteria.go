@@ -0,0 +1,344 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/pricing"
+	"bff-go-mvp/internal/vehicles"
+)
+
+// Sort keys accepted in sort.sort_by.
+const (
+	SortByDistance = "distance"
+	SortByPrice    = "price"
+	SortByRating   = "rating"
+	SortByPower    = "power"
+)
+
+// Sort orders accepted in sort.order.
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// ConnectorTypes is the documented connector_type enum. Catalog connector
+// types are compared after NormalizeConnectorType, so "Type 2" and "TYPE_2"
+// are the same.
+var ConnectorTypes = []string{
+	"TYPE_1", "TYPE_2", "CCS1", "CCS2", "CHADEMO", "GBT", "BHARAT_AC_001", "BHARAT_DC_001",
+}
+
+var sortKeys = []string{SortByDistance, SortByPrice, SortByRating, SortByPower}
+
+// NormalizeConnectorType maps connector type spellings such as "Type 2",
+// "type-2" and "TYPE_2" to the enum form.
+func NormalizeConnectorType(s string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToUpper(strings.TrimSpace(s)))
+}
+
+// Criteria is a validated set of search filters, availability constraints
+// and sort order that can be applied to the catalogs of any search backend.
+type Criteria struct {
+	cpo           string
+	connectorType string
+	minPowerKW    float64
+	amenities     []string
+	// vehicleConnectors holds the connector types the vehicle can charge
+	// from; nil when the request names no vehicle.
+	vehicleConnectors map[string]bool
+	sortBy            string
+	desc              bool
+	// open is the period stations must be open for, from time_window or
+	// open_now; nil when the request sets neither.
+	open *timeRange
+	now  time.Time
+}
+
+// NewCriteria validates the filters, sort, time_window and open_now of req.
+// now is the reference time for open_now and next openings, and registry
+// resolves the connectors of filters.vehicle. Values outside the documented
+// enums are rejected with a validation error.
+func NewCriteria(req model.SearchRequest, now time.Time, registry *vehicles.Registry) (Criteria, error) {
+	c := Criteria{now: now}
+
+	open, err := parseTimeRange(req, now)
+	if err != nil {
+		return Criteria{}, err
+	}
+	c.open = open
+
+	if f := req.Filters; f != nil {
+		c.cpo = strings.TrimSpace(f.CPO)
+		if f.ConnectorType != "" {
+			c.connectorType = NormalizeConnectorType(f.ConnectorType)
+			if !contains(ConnectorTypes, c.connectorType) {
+				return Criteria{}, invalid("filters.connector_type", f.ConnectorType, ConnectorTypes)
+			}
+		}
+		if f.MaxPowerKW < 0 {
+			return Criteria{}, apperror.Validation("filters.max_power_kw must not be negative").
+				WithDetail("field", "filters.max_power_kw")
+		}
+		c.minPowerKW = f.MaxPowerKW
+		for _, a := range f.Amenities {
+			if a = strings.TrimSpace(a); a != "" {
+				c.amenities = append(c.amenities, a)
+			}
+		}
+		if v := f.Vehicle; v != nil && (v.Make != "" || v.Model != "" || v.Type != "") {
+			if v.Type != "" && !contains(vehicles.Types(), strings.ToLower(strings.TrimSpace(v.Type))) {
+				return Criteria{}, invalid("filters.vehicle.type", v.Type, vehicles.Types())
+			}
+			spec, err := registry.Lookup(*v)
+			if err != nil {
+				return Criteria{}, err
+			}
+			c.vehicleConnectors = make(map[string]bool, len(spec.Connectors))
+			for _, t := range spec.Connectors {
+				c.vehicleConnectors[NormalizeConnectorType(t)] = true
+			}
+		}
+	}
+
+	if s := req.Sort; s != nil {
+		c.sortBy = strings.ToLower(strings.TrimSpace(s.SortBy))
+		if c.sortBy != "" && !contains(sortKeys, c.sortBy) {
+			return Criteria{}, invalid("sort.sort_by", s.SortBy, sortKeys)
+		}
+		switch strings.ToLower(strings.TrimSpace(s.Order)) {
+		case "", OrderAsc:
+		case OrderDesc:
+			c.desc = true
+		default:
+			return Criteria{}, invalid("sort.order", s.Order, []string{OrderAsc, OrderDesc})
+		}
+	}
+
+	return c, nil
+}
+
+func invalid(field, value string, allowed []string) *apperror.Error {
+	return apperror.Validation(fmt.Sprintf("Unsupported %s %q", field, value)).
+		WithDetail("field", field).
+		WithDetail("allowed", allowed)
+}
+
+// Apply returns the catalogs that match the filters, in the requested order.
+// Connector filters drop non-matching connectors and the offers that only
+// cover them; catalogs left without connectors are dropped. With a time
+// window or open_now, stations not open for the whole period are dropped;
+// otherwise stations closed now carry their next opening. Stations whose
+// hours cannot be read are kept. Catalogs missing the sort value come last
+// in either order; priceKey describes how prices compare.
+func (c Criteria) Apply(catalogs []model.Catalog) []model.Catalog {
+	out := make([]model.Catalog, 0, len(catalogs))
+	for _, cat := range catalogs {
+		if c.cpo != "" && !strings.EqualFold(cat.Provider.ID, c.cpo) {
+			continue
+		}
+		var ok bool
+		if cat, ok = c.applyHours(cat); !ok {
+			continue
+		}
+		if c.filtersConnectors() {
+			if cat, ok = c.filterConnectors(cat); !ok {
+				continue
+			}
+		}
+		out = append(out, cat)
+	}
+
+	if c.sortBy != "" {
+		sort.SliceStable(out, func(i, j int) bool {
+			gi, vi := sortKey(out[i], c.sortBy)
+			gj, vj := sortKey(out[j], c.sortBy)
+			if gi != gj {
+				return gi < gj
+			}
+			if c.desc {
+				return vi > vj
+			}
+			return vi < vj
+		})
+	}
+	return out
+}
+
+// applyHours checks a station's opening hours against the requested period,
+// or annotates its next opening when it is closed now.
+func (c Criteria) applyHours(cat model.Catalog) (model.Catalog, bool) {
+	hours, err := hoursOf(cat)
+	if err != nil {
+		return cat, true
+	}
+	if c.open != nil {
+		return cat, hours.openThroughout(*c.open)
+	}
+	if !hours.openThroughout(timeRange{from: c.now, to: c.now}) {
+		if next, ok := hours.nextOpening(c.now); ok {
+			cat.NextOpening = next.Format(time.RFC3339)
+		}
+	}
+	return cat, true
+}
+
+func (c Criteria) filtersConnectors() bool {
+	return c.connectorType != "" || c.minPowerKW > 0 || len(c.amenities) > 0 || c.vehicleConnectors != nil
+}
+
+func (c Criteria) matchesConnector(conn model.Connector) bool {
+	attrs := conn.ConnectorAttributes
+	connType := NormalizeConnectorType(attrs.ConnectorType)
+	if c.connectorType != "" && connType != c.connectorType {
+		return false
+	}
+	if c.vehicleConnectors != nil && !c.vehicleConnectors[connType] {
+		return false
+	}
+	if attrs.MaxPowerKW < c.minPowerKW {
+		return false
+	}
+	for _, want := range c.amenities {
+		if !containsFold(attrs.AmenityFeature, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterConnectors returns a copy of cat with only the matching connectors
+// and the offers for them, and false when no connector matches.
+func (c Criteria) filterConnectors(cat model.Catalog) (model.Catalog, bool) {
+	kept := make(map[string]bool)
+	var connectors []model.Connector
+	for _, conn := range cat.Connectors {
+		if c.matchesConnector(conn) {
+			connectors = append(connectors, conn)
+			kept[conn.ID] = true
+		}
+	}
+	if len(connectors) == 0 {
+		return model.Catalog{}, false
+	}
+
+	offers := make([]model.Offer, 0, len(cat.Offers))
+	for _, o := range cat.Offers {
+		if len(o.Items) == 0 || anyKept(o.Items, kept) {
+			offers = append(offers, o)
+		}
+	}
+
+	cat.Connectors = connectors
+	cat.Offers = offers
+	return cat, true
+}
+
+// Sort groups. Catalogs are ordered by group in either sort order, and by
+// value within a group.
+const (
+	groupValue = iota
+	// groupTimePrice holds catalogs priced only per minute or hour. Time and
+	// energy prices cannot be compared, so these follow the catalogs priced
+	// per kWh.
+	groupTimePrice
+	groupMissing
+)
+
+// sortKey returns the group and value a catalog is sorted by.
+func sortKey(cat model.Catalog, key string) (int, float64) {
+	switch key {
+	case SortByDistance:
+		if cat.DistanceMeters != nil {
+			return groupValue, *cat.DistanceMeters
+		}
+	case SortByPrice:
+		return priceKey(cat)
+	case SortByRating:
+		if cat.Rating != nil {
+			return groupValue, cat.Rating.Value
+		}
+	case SortByPower:
+		var highest float64
+		found := false
+		for _, conn := range cat.Connectors {
+			if !found || conn.ConnectorAttributes.MaxPowerKW > highest {
+				highest, found = conn.ConnectorAttributes.MaxPowerKW, true
+			}
+		}
+		if found {
+			return groupValue, highest
+		}
+	}
+	return groupMissing, 0
+}
+
+// priceKey represents a catalog by its cheapest rate per kWh or, without
+// energy offers, by its cheapest rate per minute. Rates are per single unit,
+// so an offer of 50 INR per 10 kWh is 5 INR per kWh. Offers in units the
+// pricing engine does not support are ignored.
+func priceKey(cat model.Catalog) (int, float64) {
+	group, lowest := groupMissing, 0.0
+	for _, o := range cat.Offers {
+		g, rate, ok := offerRate(o)
+		if ok && (g < group || (g == group && rate < lowest)) {
+			group, lowest = g, rate
+		}
+	}
+	return group, lowest
+}
+
+// offerRate returns the sort group of an offer and its price per kWh or per
+// minute.
+func offerRate(o model.Offer) (int, float64, bool) {
+	unit, quantity := pricing.UnitKWh, 1.0
+	if q := o.Price.ApplicableQuantity; q != nil {
+		if q.UnitCode != "" {
+			unit = strings.ToUpper(q.UnitCode)
+		}
+		if q.UnitQuantity > 0 {
+			quantity = q.UnitQuantity
+		}
+	}
+	rate := o.Price.Value / quantity
+	switch unit {
+	case pricing.UnitKWh:
+		return groupValue, rate, true
+	case pricing.UnitMinute:
+		return groupTimePrice, rate, true
+	case pricing.UnitHour:
+		return groupTimePrice, rate / 60, true
+	}
+	return 0, 0, false
+}
+
+func anyKept(ids []string, kept map[string]bool) bool {
+	for _, id := range ids {
+		if kept[id] {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, v string) bool {
+	for _, s := range values {
+		if strings.EqualFold(strings.TrimSpace(s), v) {
+			return true
+		}
+	}
+	return false
+}
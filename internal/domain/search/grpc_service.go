@@ -0,0 +1,156 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/transaction"
+	"bff-go-mvp/internal/translator"
+	"bff-go-mvp/internal/vehicles"
+	"bff-go-mvp/pkg/models"
+)
+
+const (
+	discoveryVersion = "2.0.0"
+	discoveryAction  = "discover"
+	discoveryDomain  = "beckn.one:deg:ev-charging"
+	discoveryTTL     = "PT30S"
+)
+
+// DiscoveryClient is the subset of the gRPC discovery client used by GRPCService.
+type DiscoveryClient interface {
+	CallDiscoveryService(ctx context.Context, req *models.DiscoveryRequest) (*models.DiscoveryResponse, error)
+}
+
+// GRPCService implements Service by issuing a Beckn discover call to the
+// downstream discovery service and translating the returned catalogs back to
+// the BFF search response. Filters, sort and pagination are applied over the
+// returned catalogs.
+type GRPCService struct {
+	client   DiscoveryClient
+	vehicles *vehicles.Registry
+	logger   *zap.Logger
+}
+
+func NewGRPCService(client DiscoveryClient, registry *vehicles.Registry, logger *zap.Logger) *GRPCService {
+	return &GRPCService{
+		client:   client,
+		vehicles: registry,
+		logger:   logger,
+	}
+}
+
+func (s *GRPCService) Search(ctx context.Context, page, perPage int, req model.SearchRequest) (model.SearchResponse, error) {
+	criteria, err := NewCriteria(req, time.Now(), s.vehicles)
+	if err != nil {
+		return model.SearchResponse{}, err
+	}
+
+	txnID := transaction.FromContext(ctx)
+	if txnID == "" {
+		txnID = transaction.New()
+	}
+	discoveryReq := buildDiscoveryRequest(req, txnID, time.Now().UTC())
+
+	discoveryResp, err := s.client.CallDiscoveryService(ctx, discoveryReq)
+	if err != nil {
+		return model.SearchResponse{}, fmt.Errorf("discovery call failed: %w", err)
+	}
+
+	catalogs, report := translator.DiscoveryToCatalogs(discoveryResp)
+	if report.HasIssues() {
+		transaction.Logger(ctx, s.logger).Warn("discovery response not fully translated",
+			zap.Int("missing", report.Count(translator.ReasonMissing)),
+			zap.Int("invalid", report.Count(translator.ReasonInvalid)),
+			zap.Int("unmapped", report.Count(translator.ReasonUnmapped)),
+			zap.Stringers("issues", report.Issues),
+		)
+	}
+
+	// The discovery service may ignore some filters, so they are applied
+	// again here together with the sort order.
+	catalogs = criteria.Apply(catalogs)
+
+	return model.SearchResponse{
+		Total:    len(catalogs),
+		Page:     page,
+		PerPage:  perPage,
+		Catalogs: paginate(catalogs, page, perPage),
+	}, nil
+}
+
+// buildDiscoveryRequest converts a BFF search request into a Beckn discover
+// request that belongs to the BFF transaction txnID.
+func buildDiscoveryRequest(req model.SearchRequest, txnID string, now time.Time) *models.DiscoveryRequest {
+	intent := &models.Intent{EvseID: req.EvseID}
+
+	if len(req.GeoCoordinates) == 2 {
+		// BFF coordinates are [lat, lon]; GeoJSON expects [lon, lat].
+		intent.Circle = &models.Circle{
+			Gps: models.Geo{
+				Type:        "Point",
+				Coordinates: []float64{req.GeoCoordinates[1], req.GeoCoordinates[0]},
+			},
+			RadiusMeters: req.DistanceMeters,
+		}
+	}
+
+	if req.TimeWindow != nil {
+		intent.TimeWindow = &models.TimeWindow{Start: req.TimeWindow.Start, End: req.TimeWindow.End}
+	}
+
+	if f := req.Filters; f != nil {
+		intent.Filters = &models.IntentFilters{
+			CPO:           f.CPO,
+			ConnectorType: f.ConnectorType,
+			MinPowerKW:    f.MaxPowerKW,
+			Amenities:     f.Amenities,
+		}
+		if f.Vehicle != nil {
+			intent.Filters.Vehicle = &models.IntentVehicle{Make: f.Vehicle.Make, Model: f.Vehicle.Model, Type: f.Vehicle.Type}
+		}
+	}
+
+	return &models.DiscoveryRequest{
+		Context: models.Context{
+			Version:       discoveryVersion,
+			Action:        discoveryAction,
+			Domain:        discoveryDomain,
+			TransactionID: txnID,
+			MessageID:     messageID(txnID, discoveryAction, now),
+			Timestamp:     now.Format(time.RFC3339),
+			TTL:           discoveryTTL,
+		},
+		Message: models.Message{
+			Intent: intent,
+		},
+	}
+}
+
+// messageID derives the Beckn message ID of an action within a transaction.
+// It is a name-based UUID, so the same call can be correlated from the
+// transaction ID and timestamp alone.
+func messageID(txnID, action string, now time.Time) string {
+	name := txnID + "/" + action + "/" + now.Format(time.RFC3339Nano)
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String()
+}
+
+// paginate returns the 1-based page of catalogs, or an empty slice when the
+// page is past the end. The page is compared with the page count before the
+// offset is computed, so that huge pages cannot overflow it.
+func paginate(catalogs []model.Catalog, page, perPage int) []model.Catalog {
+	if page < 1 || perPage < 1 || page-1 >= (len(catalogs)+perPage-1)/perPage {
+		return []model.Catalog{}
+	}
+	start := (page - 1) * perPage
+	end := start + perPage
+	if end > len(catalogs) {
+		end = len(catalogs)
+	}
+	return catalogs[start:end]
+}
@@ -0,0 +1,31 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"bff-go-mvp/internal/httpclient"
+	"bff-go-mvp/internal/model"
+)
+
+// HTTPService implements Service by forwarding to a downstream REST backend
+// that serves POST /v1/search.
+type HTTPService struct {
+	client *httpclient.Client
+}
+
+func NewHTTPService(client *httpclient.Client) *HTTPService {
+	return &HTTPService{client: client}
+}
+
+func (s *HTTPService) Search(ctx context.Context, page, perPage int, req model.SearchRequest) (model.SearchResponse, error) {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+
+	var resp model.SearchResponse
+	err := s.client.Do(ctx, http.MethodPost, "/v1/search", query, req, &resp)
+	return resp, err
+}
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"bff-go-mvp/internal/geo"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/vehicles"
+)
+
+// IndexService implements Service over an in-memory set of stations. Radius
+// searches use a geohash index and return catalogs nearest first with their
+// distance; EVSE searches return the stations that have a connector with
+// that EVSE ID. Filters and sort are then applied with Criteria.
+type IndexService struct {
+	index    *geo.Index[model.Catalog]
+	byEVSE   map[string][]model.Catalog
+	vehicles *vehicles.Registry
+}
+
+// NewIndexService indexes stations. Every station needs valid
+// address.geo_coordinates.
+func NewIndexService(stations []model.Catalog, registry *vehicles.Registry) (*IndexService, error) {
+	items := make([]geo.Item[model.Catalog], 0, len(stations))
+	byEVSE := make(map[string][]model.Catalog)
+	for _, c := range stations {
+		p, ok := point(c.Address.GeoCoordinates)
+		if !ok {
+			return nil, fmt.Errorf("station %q: invalid geo_coordinates %v", c.ID, c.Address.GeoCoordinates)
+		}
+		items = append(items, geo.Item[model.Catalog]{Point: p, Value: c})
+
+		seen := make(map[string]bool)
+		for _, conn := range c.Connectors {
+			id := conn.ConnectorAttributes.EvseID
+			if id != "" && !seen[id] {
+				seen[id] = true
+				byEVSE[id] = append(byEVSE[id], c)
+			}
+		}
+	}
+
+	return &IndexService{
+		index:    geo.NewIndex(items),
+		byEVSE:   byEVSE,
+		vehicles: registry,
+	}, nil
+}
+
+// Len returns the number of indexed stations.
+func (s *IndexService) Len() int {
+	return s.index.Len()
+}
+
+func (s *IndexService) Search(ctx context.Context, page, perPage int, req model.SearchRequest) (model.SearchResponse, error) {
+	criteria, err := NewCriteria(req, time.Now(), s.vehicles)
+	if err != nil {
+		return model.SearchResponse{}, err
+	}
+	center, hasCenter := point(req.GeoCoordinates)
+
+	var catalogs []model.Catalog
+	switch {
+	case req.EvseID != "":
+		for _, c := range s.byEVSE[req.EvseID] {
+			if hasCenter {
+				p, _ := point(c.Address.GeoCoordinates)
+				c = withDistance(c, geo.Distance(center, p))
+			}
+			catalogs = append(catalogs, c)
+		}
+	case hasCenter:
+		for _, hit := range s.index.Within(center, req.DistanceMeters) {
+			catalogs = append(catalogs, withDistance(hit.Value, hit.DistanceMeters))
+		}
+	}
+	catalogs = criteria.Apply(catalogs)
+
+	return model.SearchResponse{
+		Total:    len(catalogs),
+		Page:     page,
+		PerPage:  perPage,
+		Catalogs: paginate(catalogs, page, perPage),
+	}, nil
+}
+
+// point converts BFF [lat, lon] coordinates to a geo.Point.
+func point(coords []float64) (geo.Point, bool) {
+	if len(coords) != 2 {
+		return geo.Point{}, false
+	}
+	p := geo.Point{Lat: coords[0], Lon: coords[1]}
+	return p, p.Valid()
+}
+
+// withDistance returns a copy of c carrying its distance, rounded to the
+// meter.
+func withDistance(c model.Catalog, meters float64) model.Catalog {
+	d := math.Round(meters)
+	c.DistanceMeters = &d
+	return c
+}
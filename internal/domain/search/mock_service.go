@@ -2,101 +2,121 @@ package search
 
 import (
 	"context"
+	"time"
 
 	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/vehicles"
 )
 
 // MockService implements Service and returns static data that matches
-// the example in swagger.yaml for POST /v1/search.
-type MockService struct{}
+// the example in swagger.yaml for POST /v1/search, narrowed and ordered by
+// the request filters and sort.
+type MockService struct {
+	vehicles *vehicles.Registry
+}
 
-func NewMockService() *MockService {
-	return &MockService{}
+func NewMockService(registry *vehicles.Registry) *MockService {
+	return &MockService{vehicles: registry}
 }
 
 func (s *MockService) Search(ctx context.Context, page, perPage int, req model.SearchRequest) (model.SearchResponse, error) {
-	// Static mock response matching the desired structure.
+	criteria, err := NewCriteria(req, time.Now(), s.vehicles)
+	if err != nil {
+		return model.SearchResponse{}, err
+	}
+
 	resp := model.SearchResponse{
-		Total:   1,
-		Page:    page,
-		PerPage: perPage,
-		Catalogs: []model.Catalog{
-			{
-				ID: "catalog-ev-charging-001",
-				Provider: model.Provider{
-					ID: "ecopower-charging",
-					Descriptor: model.ProviderDescriptor{
-						Name: "EcoPower Charging Pvt Ltd",
-					},
-				},
-				Address: model.Address{
-					Name:           "MG JVLR Jogeshwari Caves Road",
-					GeoCoordinates: []float64{12.9716, 77.5946},
+		Page:     page,
+		PerPage:  perPage,
+		Catalogs: criteria.Apply(MockCatalogs()),
+	}
+	resp.Total = len(resp.Catalogs)
+	return resp, nil
+}
+
+// MockCatalogs returns the static catalogs the mock searches, matching the
+// example in swagger.yaml. The mock estimate service prices their offers.
+func MockCatalogs() []model.Catalog {
+	return []model.Catalog{
+		{
+			ID: "catalog-ev-charging-001",
+			Provider: model.Provider{
+				ID: "ecopower-charging",
+				Descriptor: model.ProviderDescriptor{
+					Name: "EcoPower Charging Pvt Ltd",
 				},
-				Rating: &model.Rating{
-					Value: 4.5,
-					Count: 128,
+			},
+			Address: model.Address{
+				Name:           "MG JVLR Jogeshwari Caves Road",
+				GeoCoordinates: []float64{12.9716, 77.5946},
+			},
+			Rating: &model.Rating{
+				Value: 4.5,
+				Count: 128,
+			},
+			AvailabilityWindow: []model.AvailabilityWindow{
+				{
+					StartTime: "06:00:00",
+					EndTime:   "22:00:00",
 				},
-				AvailabilityWindow: []model.AvailabilityWindow{
-					{
-						StartTime: "06:00:00",
-						EndTime:   "22:00:00",
+			},
+			AvailablePowerType: []string{"DC", "AC"},
+			Connectors: []model.Connector{
+				{
+					ID:       "ev-charger-ccs2-001",
+					IsActive: true,
+					ConnectorAttributes: model.ConnectorAttributes{
+						ConnectorType:        "TYPE 2",
+						MaxPowerKW:           60,
+						MinPowerKW:           5,
+						SocketCount:          2,
+						ReservationSupported: true,
+						Status:               "Available",
+						ChargingSpeed:        "FAST",
+						PowerType:            "DC",
+						ConnectorFormat:      "CABLE",
 					},
 				},
-				AvailablePowerType: []string{"DC", "AC"},
-				Connectors: []model.Connector{
-					{
-						ID:       "ev-charger-ccs2-001",
-						IsActive: true,
-						ConnectorAttributes: model.ConnectorAttributes{
-							ConnectorType:        "TYPE 2",
-							MaxPowerKW:           60,
-							MinPowerKW:           5,
-							SocketCount:          2,
-							ReservationSupported: true,
-							Status:               "Available",
-							ChargingSpeed:        "FAST",
-							PowerType:            "DC",
-							ConnectorFormat:      "CABLE",
-						},
+			},
+			Offers: []model.Offer{
+				{
+					ID: "offer-ccs2-60kw-kwh",
+					Descriptor: model.OfferDescriptor{
+						Name: "Per-kWh Tariff - CCS2 60kW",
 					},
-				},
-				Offers: []model.Offer{
-					{
-						ID: "offer-ccs2-60kw-kwh",
-						Descriptor: model.OfferDescriptor{
-							Name: "Per-kWh Tariff - CCS2 60kW",
-						},
-						Items: []string{
-							"ev-charger-ccs2-001",
-						},
-						Price: model.Price{
-							Currency: "INR",
-							Value:    18,
-							ApplicableQuantity: &model.ApplicableQuantity{
-								UnitText:     "Kilowatt Hour",
-								UnitCode:     "KWH",
-								UnitQuantity: 1,
-							},
+					Items: []string{
+						"ev-charger-ccs2-001",
+					},
+					Price: model.Price{
+						Currency: "INR",
+						Value:    18,
+						ApplicableQuantity: &model.ApplicableQuantity{
+							UnitText:     "Kilowatt Hour",
+							UnitCode:     "KWH",
+							UnitQuantity: 1,
 						},
-						Validity: &model.Validity{
-							StartDate: "2025-10-01T00:00:00Z",
-							EndDate:   "2026-03-31T23:59:59Z",
+					},
+					Validity: &model.Validity{
+						StartDate: "2025-10-01T00:00:00Z",
+						EndDate:   "2026-03-31T23:59:59Z",
+					},
+					AcceptedPaymentMethod: []string{"UPI", "Card", "Wallet"},
+					OfferAttributes: &model.OfferAttributes{
+						BuyerFinderFee: &model.BuyerFinderFee{
+							FeeType:  "PERCENTAGE",
+							FeeValue: 2.5,
 						},
-						AcceptedPaymentMethod: []string{"UPI", "Card", "Wallet"},
-						OfferAttributes: &model.OfferAttributes{
-							BuyerFinderFee: &model.BuyerFinderFee{
-								FeeType:  "PERCENTAGE",
-								FeeValue: 2.5,
-							},
-							IdleFeePolicy: "₹2/min after 10 min post-charge",
+						IdleFee: &model.IdleFee{
+							Rate:               2,
+							Currency:           "INR",
+							Unit:               "MIN",
+							GracePeriodMinutes: 10,
 						},
-						Provider: "ecopower-charging",
+						IdleFeePolicy: "₹2/min after 10 min post-charge",
 					},
+					Provider: "ecopower-charging",
 				},
 			},
 		},
 	}
-
-	return resp, nil
 }
@@ -0,0 +1,69 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"bff-go-mvp/internal/model"
+)
+
+// LoadStations reads a station fixture file. See ParseStations for the
+// accepted formats.
+func LoadStations(path string) ([]model.Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read stations: %w", err)
+	}
+	return ParseStations(data)
+}
+
+// ParseStations decodes stations in one of two formats:
+//   - a GeoJSON FeatureCollection of Point features whose properties are a
+//     catalog; the geometry ([lon, lat]) sets the catalog address coordinates
+//   - a JSON array of catalogs positioned by address.geo_coordinates
+//     ([lat, lon])
+func ParseStations(data []byte) ([]model.Catalog, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var catalogs []model.Catalog
+		if err := json.Unmarshal(data, &catalogs); err != nil {
+			return nil, fmt.Errorf("decode stations: %w", err)
+		}
+		return catalogs, nil
+	}
+
+	var fc featureCollection
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("decode stations: %w", err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("decode stations: expected a FeatureCollection or an array, got type %q", fc.Type)
+	}
+
+	catalogs := make([]model.Catalog, 0, len(fc.Features))
+	for i, f := range fc.Features {
+		var lonLat []float64
+		if f.Geometry.Type != "Point" || json.Unmarshal(f.Geometry.Coordinates, &lonLat) != nil || len(lonLat) != 2 {
+			return nil, fmt.Errorf("decode stations: feature %d: geometry must be a Point", i)
+		}
+		c := f.Properties
+		c.Address.GeoCoordinates = []float64{lonLat[1], lonLat[0]}
+		catalogs = append(catalogs, c)
+	}
+	return catalogs, nil
+}
+
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+	Properties model.Catalog `json:"properties"`
+}
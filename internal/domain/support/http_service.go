@@ -0,0 +1,26 @@
+package support
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"bff-go-mvp/internal/httpclient"
+	"bff-go-mvp/internal/model"
+)
+
+// HTTPService implements Service by forwarding to a downstream REST backend
+// that serves GET /v1/orders/{order_id}/support.
+type HTTPService struct {
+	client *httpclient.Client
+}
+
+func NewHTTPService(client *httpclient.Client) *HTTPService {
+	return &HTTPService{client: client}
+}
+
+func (s *HTTPService) GetSupport(ctx context.Context, orderID string) (model.SupportResponse, error) {
+	var resp model.SupportResponse
+	err := s.client.Do(ctx, http.MethodGet, "/v1/orders/"+url.PathEscape(orderID)+"/support", nil, nil, &resp)
+	return resp, err
+}
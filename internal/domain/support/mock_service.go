@@ -3,26 +3,28 @@ package support
 import (
 	"context"
 
+	"bff-go-mvp/internal/domain/orders"
 	"bff-go-mvp/internal/model"
 )
 
-// MockService implements Service and returns static data based on swagger example.
-type MockService struct{}
+// MockService implements Service and returns the support contact from the
+// swagger example for any order in the shared repository.
+type MockService struct {
+	repo orders.Repository
+}
 
-func NewMockService() *MockService {
-	return &MockService{}
+func NewMockService(repo orders.Repository) *MockService {
+	return &MockService{repo: repo}
 }
 
 func (s *MockService) GetSupport(ctx context.Context, orderID string) (model.SupportResponse, error) {
-	_ = ctx
-	_ = orderID
+	order, err := s.repo.Get(ctx, orderID)
+	if err != nil {
+		return model.SupportResponse{}, err
+	}
 
 	return model.SupportResponse{
-		Order: model.OrderInfo{
-			ID:     "order-bpp-789012",
-			Status: "CANCELLED",
-			Mode:   "RESERVATION",
-		},
+		Order: order.Info(),
 		Name:  "BlueCharge Support Team",
 		Phone: "18001080",
 		Email: "support@bluechargenet-aggregator.io",
@@ -36,5 +38,3 @@ func (s *MockService) GetSupport(ctx context.Context, orderID string) (model.Sup
 		},
 	}, nil
 }
-
-
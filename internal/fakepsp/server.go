@@ -0,0 +1,349 @@
+// Package fakepsp is a local payment service provider for development and
+// tests. It serves the PSP API called by the payment gateway adapters, a
+// checkout page where the buyer approves or declines a payment, and calls
+// back a configured URL with the outcome, signed like a real provider's
+// webhook.
+package fakepsp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/payment"
+	"bff-go-mvp/internal/httpx"
+	"bff-go-mvp/internal/webhook"
+)
+
+// Options configure a Server.
+type Options struct {
+	// BaseURL is the public URL the server is reached at; checkout URLs are
+	// built from it.
+	BaseURL string
+	// AutoCapture captures payments as soon as they are created. They are
+	// reported in the response only, not called back.
+	AutoCapture bool
+	// CallbackURL receives a payment.PSPEvent when the buyer approves or
+	// declines a payment on the checkout page; empty disables callbacks.
+	CallbackURL string
+	// WebhookSecret signs callbacks (see package webhook); empty sends them
+	// unsigned.
+	WebhookSecret string
+	// Client sends callbacks; nil uses a client with a 10 second timeout.
+	Client *http.Client
+	Logger *zap.Logger
+}
+
+// Server is an in-memory PSP. It implements payment.PSP for in-process use,
+// and Handler serves the same API over HTTP:
+//
+//	POST /v1/payments            create a payment
+//	GET  /v1/payments/{id}       read a payment
+//	POST /v1/refunds             refund a captured payment
+//	GET  /checkout/{id}          checkout page
+//	POST /checkout/{id}          approve or decline (form field outcome)
+//	GET  /health                 liveness
+type Server struct {
+	opts Options
+	now  func() time.Time
+
+	mu       sync.Mutex
+	payments map[string]*entry
+	refunds  map[string]*payment.PSPRefund
+}
+
+type entry struct {
+	payment.PSPPayment
+	returnURL string
+}
+
+func New(opts Options) *Server {
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+	return &Server{opts: opts, now: time.Now, payments: map[string]*entry{}, refunds: map[string]*payment.PSPRefund{}}
+}
+
+// CreatePayment creates a payment, captured at once with AutoCapture. A
+// payment with the same reference is returned as it is.
+func (s *Server) CreatePayment(_ context.Context, req payment.PSPRequest) (payment.PSPPayment, error) {
+	switch {
+	case req.Reference == "":
+		return payment.PSPPayment{}, apperror.Validation("reference is required").WithDetail("field", "reference")
+	case req.Amount.Currency == "" || req.Amount.Value.Sign() <= 0:
+		return payment.PSPPayment{}, apperror.Validation("amount must be positive").WithDetail("field", "amount")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.payments {
+		if e.Reference == req.Reference {
+			return e.PSPPayment, nil
+		}
+	}
+
+	id := "psp_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	e := &entry{
+		PSPPayment: payment.PSPPayment{
+			ID:          id,
+			Reference:   req.Reference,
+			OrderID:     req.OrderID,
+			Method:      req.Method,
+			Amount:      req.Amount,
+			Status:      payment.PSPStatusCreated,
+			CheckoutURL: s.opts.BaseURL + "/checkout/" + id,
+			CreatedAt:   s.now().UTC(),
+		},
+		returnURL: req.ReturnURL,
+	}
+	if s.opts.AutoCapture {
+		e.Status = payment.PSPStatusCaptured
+	}
+
+	s.payments[id] = e
+	return e.PSPPayment, nil
+}
+
+// Payment returns a payment by ID.
+func (s *Server) Payment(id string) (payment.PSPPayment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.payments[id]
+	if !ok {
+		return payment.PSPPayment{}, false
+	}
+	return e.PSPPayment, true
+}
+
+// CreateRefund refunds a captured payment at once. A refund with the same
+// reference is returned as it is; the refunds of a payment cannot exceed
+// it, and refunding it in full marks it REFUNDED.
+func (s *Server) CreateRefund(_ context.Context, req payment.PSPRefundRequest) (payment.PSPRefund, error) {
+	if req.Reference == "" {
+		return payment.PSPRefund{}, apperror.Validation("reference is required").WithDetail("field", "reference")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.refunds {
+		if r.Reference == req.Reference && r.Status != payment.PSPRefundFailed {
+			return *r, nil
+		}
+	}
+	e, ok := s.payments[req.PaymentID]
+	switch {
+	case !ok:
+		return payment.PSPRefund{}, apperror.NotFound("payment not found").WithDetail("payment_id", req.PaymentID)
+	case e.Status != payment.PSPStatusCaptured:
+		return payment.PSPRefund{}, apperror.Conflict("payment is "+strings.ToLower(e.Status)+", not captured").
+			WithDetail("payment_id", req.PaymentID)
+	case req.Amount.Currency != e.Amount.Currency || req.Amount.Value.Sign() <= 0:
+		return payment.PSPRefund{}, apperror.Validation("amount must be positive and in the payment's currency").
+			WithDetail("field", "amount")
+	}
+	refunded := req.Amount.Value
+	for _, r := range s.refunds {
+		if r.PaymentID == e.ID && r.Status != payment.PSPRefundFailed {
+			refunded = refunded.Add(r.Amount.Value)
+		}
+	}
+	if refunded.Cmp(e.Amount.Value) > 0 {
+		return payment.PSPRefund{}, apperror.Conflict("refunds exceed the captured amount").
+			WithDetail("payment_id", req.PaymentID)
+	}
+
+	r := &payment.PSPRefund{
+		ID:        "rfnd_" + strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Reference: req.Reference,
+		PaymentID: e.ID,
+		OrderID:   e.OrderID,
+		Amount:    req.Amount,
+		Status:    payment.PSPRefundSucceeded,
+		CreatedAt: s.now().UTC(),
+	}
+	s.refunds[r.ID] = r
+	if refunded.Cmp(e.Amount.Value) == 0 {
+		e.Status = payment.PSPStatusRefunded
+	}
+	return *r, nil
+}
+
+// Check always succeeds; the server is in-process.
+func (s *Server) Check(context.Context) error {
+	return nil
+}
+
+// Complete approves (captures) or declines (fails) a created payment and
+// calls back with the outcome.
+func (s *Server) Complete(ctx context.Context, id string, approve bool) (payment.PSPPayment, error) {
+	s.mu.Lock()
+	e, ok := s.payments[id]
+	if !ok {
+		s.mu.Unlock()
+		return payment.PSPPayment{}, apperror.NotFound("payment not found").WithDetail("payment_id", id)
+	}
+	if e.Status != payment.PSPStatusCreated {
+		s.mu.Unlock()
+		return payment.PSPPayment{}, apperror.Conflict("payment is already "+strings.ToLower(e.Status)).
+			WithDetail("payment_id", id)
+	}
+	event := payment.PSPEvent{ID: "evt_" + strings.ReplaceAll(uuid.NewString(), "-", ""), CreatedAt: s.now().UTC()}
+	if approve {
+		e.Status, event.Type = payment.PSPStatusCaptured, payment.EventPaymentCaptured
+	} else {
+		e.Status, event.Type = payment.PSPStatusFailed, payment.EventPaymentFailed
+	}
+	event.Payment = e.PSPPayment
+	s.mu.Unlock()
+
+	if err := s.callback(ctx, event); err != nil {
+		s.opts.Logger.Warn("payment callback failed",
+			zap.String("payment_id", id), zap.String("event_id", event.ID), zap.Error(err))
+	}
+	return event.Payment, nil
+}
+
+// callback posts event to the callback URL.
+func (s *Server) callback(ctx context.Context, event payment.PSPEvent) error {
+	if s.opts.CallbackURL == "" {
+		return nil
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.opts.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.opts.WebhookSecret != "" {
+		req.Header.Set(webhook.SignatureHeader, webhook.Sign(s.opts.WebhookSecret, s.now(), body))
+	}
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("callback returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Handler serves the PSP API and checkout pages.
+func (s *Server) Handler() http.Handler {
+	r := mux.NewRouter()
+	r.HandleFunc("/v1/payments", s.handleCreate).Methods(http.MethodPost)
+	r.HandleFunc("/v1/payments/{id}", s.handleGet).Methods(http.MethodGet)
+	r.HandleFunc("/v1/refunds", s.handleRefund).Methods(http.MethodPost)
+	r.HandleFunc("/checkout/{id}", s.handleCheckout).Methods(http.MethodGet)
+	r.HandleFunc("/checkout/{id}", s.handleComplete).Methods(http.MethodPost)
+	r.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
+		httpx.WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}).Methods(http.MethodGet)
+	return r
+}
+
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req payment.PSPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request body."))
+		return
+	}
+	p, err := s.CreatePayment(r.Context(), req)
+	if err != nil {
+		httpx.WriteAppError(w, apperror.From(err))
+		return
+	}
+	httpx.WriteJSON(w, http.StatusCreated, p)
+}
+
+func (s *Server) handleRefund(w http.ResponseWriter, r *http.Request) {
+	var req payment.PSPRefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request body."))
+		return
+	}
+	refund, err := s.CreateRefund(r.Context(), req)
+	if err != nil {
+		httpx.WriteAppError(w, apperror.From(err))
+		return
+	}
+	httpx.WriteJSON(w, http.StatusCreated, refund)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.Payment(mux.Vars(r)["id"])
+	if !ok {
+		httpx.WriteAppError(w, apperror.NotFound("payment not found"))
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) handleCheckout(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.Payment(mux.Vars(r)["id"])
+	if !ok {
+		http.Error(w, "payment not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = checkoutPage.Execute(w, p)
+}
+
+func (s *Server) handleComplete(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	p, err := s.Complete(r.Context(), id, r.FormValue("outcome") == "approve")
+	if err != nil {
+		http.Error(w, err.Error(), httpx.StatusFor(apperror.From(err).Kind))
+		return
+	}
+
+	s.mu.Lock()
+	target := s.payments[id].returnURL
+	s.mu.Unlock()
+	if target == "" {
+		target = p.CheckoutURL
+	} else if u, err := url.Parse(target); err == nil {
+		q := u.Query()
+		q.Set("payment_id", p.ID)
+		q.Set("status", p.Status)
+		u.RawQuery = q.Encode()
+		target = u.String()
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+var checkoutPage = template.Must(template.New("checkout").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Fake PSP checkout</title></head>
+<body>
+<h1>Pay {{.Amount.Value}} {{.Amount.Currency}}</h1>
+<p>Order {{.OrderID}} &middot; {{.Method}} &middot; payment {{.ID}}</p>
+<p>Status: <strong>{{.Status}}</strong></p>
+{{if eq .Status "CREATED"}}
+<form method="post">
+<button name="outcome" value="approve">Approve</button>
+<button name="outcome" value="decline">Decline</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,38 @@
+// Package geo provides great-circle distances and an in-memory geohash index
+// for radius queries over points.
+package geo
+
+import "math"
+
+// EarthRadiusMeters is the mean Earth radius used for distance calculations.
+const EarthRadiusMeters = 6371008.8
+
+// Point is a WGS84 position in decimal degrees.
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Valid reports whether p lies within the latitude and longitude ranges.
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+// Distance returns the haversine distance between a and b in meters.
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLon := radians(b.Lon - a.Lon)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,115 @@
+package geo
+
+import "math"
+
+const (
+	geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+	// maxPrecision is the geohash length stored in the index, about 3.7cm.
+	maxPrecision = 12
+)
+
+// Geohash encodes p as a geohash of the given length.
+func Geohash(p Point, precision int) string {
+	latLo, latHi := -90.0, 90.0
+	lonLo, lonHi := -180.0, 180.0
+
+	hash := make([]byte, 0, precision)
+	even := true
+	bit, ch := 0, 0
+	for len(hash) < precision {
+		if even {
+			mid := (lonLo + lonHi) / 2
+			if p.Lon >= mid {
+				ch |= 1 << (4 - bit)
+				lonLo = mid
+			} else {
+				lonHi = mid
+			}
+		} else {
+			mid := (latLo + latHi) / 2
+			if p.Lat >= mid {
+				ch |= 1 << (4 - bit)
+				latLo = mid
+			} else {
+				latHi = mid
+			}
+		}
+		even = !even
+		if bit < 4 {
+			bit++
+			continue
+		}
+		hash = append(hash, geohashAlphabet[ch])
+		bit, ch = 0, 0
+	}
+	return string(hash)
+}
+
+// cellSize returns the height and width in degrees of a geohash cell of the
+// given length. Longitude takes the extra bit when the bit count is odd.
+func cellSize(precision int) (latDeg, lonDeg float64) {
+	bits := 5 * precision
+	lonBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / math.Exp2(float64(latBits)), 360 / math.Exp2(float64(lonBits))
+}
+
+// coveringCells returns the geohash cells of the given length that together
+// contain every point within radius meters of center: the cell of center
+// and its eight neighbours, or fewer near the poles. It returns nil when no
+// precision is coarse enough, in which case the caller must scan everything.
+func coveringCells(center Point, radius float64) []string {
+	precision := precisionFor(center, radius)
+	if precision == 0 {
+		return nil
+	}
+
+	latDeg, lonDeg := cellSize(precision)
+	seen := make(map[string]bool, 9)
+	cells := make([]string, 0, 9)
+	for _, dLat := range []float64{-latDeg, 0, latDeg} {
+		lat := center.Lat + dLat
+		if lat < -90 || lat > 90 {
+			continue
+		}
+		for _, dLon := range []float64{-lonDeg, 0, lonDeg} {
+			cell := Geohash(Point{Lat: lat, Lon: wrapLon(center.Lon + dLon)}, precision)
+			if !seen[cell] {
+				seen[cell] = true
+				cells = append(cells, cell)
+			}
+		}
+	}
+	return cells
+}
+
+// precisionFor returns the longest geohash whose cells are at least as tall
+// and as wide as the circle's extent in each direction, so that the circle
+// never reaches past the neighbouring cells. It returns 0 when no length
+// qualifies, for example when the circle contains a pole.
+func precisionFor(center Point, radius float64) int {
+	angular := radius / EarthRadiusMeters
+	cosLat := math.Cos(radians(center.Lat))
+	if angular >= math.Pi/2 || math.Sin(angular) >= cosLat {
+		return 0
+	}
+	spanLat := degrees(angular)
+	spanLon := degrees(math.Asin(math.Sin(angular) / cosLat))
+
+	for p := maxPrecision; p > 0; p-- {
+		latDeg, lonDeg := cellSize(p)
+		if latDeg >= spanLat && lonDeg >= spanLon {
+			return p
+		}
+	}
+	return 0
+}
+
+// wrapLon maps a longitude into [-180, 180).
+func wrapLon(lon float64) float64 {
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
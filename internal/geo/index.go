@@ -0,0 +1,93 @@
+package geo
+
+import (
+	"sort"
+	"strings"
+)
+
+// Item is a value placed at a point.
+type Item[T any] struct {
+	Point Point
+	Value T
+}
+
+// Hit is an item returned by a radius query with its distance from the
+// query center.
+type Hit[T any] struct {
+	Item[T]
+	DistanceMeters float64
+}
+
+type entry[T any] struct {
+	hash string
+	seq  int
+	item Item[T]
+}
+
+// Index is an immutable geohash index. Items are kept sorted by geohash, so
+// every cell is a contiguous range that is found by binary search. It is safe
+// for concurrent use.
+type Index[T any] struct {
+	entries []entry[T]
+}
+
+// NewIndex builds an index over items. Callers should reject items whose
+// point is not Valid.
+func NewIndex[T any](items []Item[T]) *Index[T] {
+	entries := make([]entry[T], len(items))
+	for i, it := range items {
+		entries[i] = entry[T]{hash: Geohash(it.Point, maxPrecision), seq: i, item: it}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].hash < entries[j].hash })
+	return &Index[T]{entries: entries}
+}
+
+// Len returns the number of indexed items.
+func (ix *Index[T]) Len() int {
+	return len(ix.entries)
+}
+
+// Within returns the items within radius meters of center, nearest first.
+// Items at the same distance keep their insertion order.
+func (ix *Index[T]) Within(center Point, radius float64) []Hit[T] {
+	if radius <= 0 {
+		return nil
+	}
+
+	type match struct {
+		seq int
+		hit Hit[T]
+	}
+	var matches []match
+	collect := func(e entry[T]) {
+		if d := Distance(center, e.item.Point); d <= radius {
+			matches = append(matches, match{seq: e.seq, hit: Hit[T]{Item: e.item, DistanceMeters: d}})
+		}
+	}
+
+	if cells := coveringCells(center, radius); cells != nil {
+		for _, cell := range cells {
+			start := sort.Search(len(ix.entries), func(i int) bool { return ix.entries[i].hash >= cell })
+			for i := start; i < len(ix.entries) && strings.HasPrefix(ix.entries[i].hash, cell); i++ {
+				collect(ix.entries[i])
+			}
+		}
+	} else {
+		for _, e := range ix.entries {
+			collect(e)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].hit.DistanceMeters != matches[j].hit.DistanceMeters {
+			return matches[i].hit.DistanceMeters < matches[j].hit.DistanceMeters
+		}
+		return matches[i].seq < matches[j].seq
+	})
+
+	hits := make([]Hit[T], len(matches))
+	for i, m := range matches {
+		hits[i] = m.hit
+	}
+	return hits
+}
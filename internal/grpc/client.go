@@ -2,60 +2,92 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
 
 	"bff-go-mvp/pkg/models"
+	discoverypb "bff-go-mvp/proto/discovery/gen"
 )
 
 // Client represents a gRPC client for discovery service
 type Client struct {
-	serviceAddress string
+	conn      *grpc.ClientConn
+	discovery discoverypb.DiscoveryServiceClient
+}
+
+// NewClient creates a new gRPC client for the given service address.
+// The connection is established lazily on the first call; when no dial
+// options are given the client uses plaintext transport credentials.
+func NewClient(serviceAddress string, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	conn, err := grpc.NewClient(serviceAddress, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create grpc client for %s: %w", serviceAddress, err)
+	}
+
+	return NewClientFromConn(conn), nil
 }
 
-// NewClient creates a new gRPC client
-func NewClient(serviceAddress string) *Client {
+// NewClientFromConn wraps an existing connection, e.g. one dialed against
+// an in-process bufconn listener in tests.
+func NewClientFromConn(conn *grpc.ClientConn) *Client {
 	return &Client{
-		serviceAddress: serviceAddress,
+		conn:      conn,
+		discovery: discoverypb.NewDiscoveryServiceClient(conn),
 	}
 }
 
-// CallDiscoveryService calls the discovery service via gRPC and returns a mock response
-// In a real implementation, this would make an actual gRPC call
+// CallDiscoveryService calls the discovery service via gRPC
 func (c *Client) CallDiscoveryService(ctx context.Context, req *models.DiscoveryRequest) (*models.DiscoveryResponse, error) {
-	// Mock implementation - returns the request with some modifications
-	// In production, this would:
-	// 1. Convert models.DiscoveryRequest to protobuf DiscoveryRequest
-	// 2. Make gRPC call to the service
-	// 3. Convert protobuf DiscoveryResponse to models.DiscoveryResponse
-
-	// For now, return a mock response based on the request
-	response := &models.DiscoveryResponse{
-		Context: models.Context{
-			Version:       req.Context.Version,
-			Action:        "on_discover",
-			Domain:        req.Context.Domain,
-			Location:      req.Context.Location,
-			BapID:         req.Context.BapID,
-			BapURI:        req.Context.BapURI,
-			BppID:         req.Context.BppID,
-			BppURI:        req.Context.BppURI,
-			TransactionID: req.Context.TransactionID,
-			MessageID:     fmt.Sprintf("resp-%s", req.Context.MessageID),
-			Timestamp:     req.Context.Timestamp,
-			TTL:           req.Context.TTL,
-			SchemaContext: req.Context.SchemaContext,
-		},
-		Message: models.Message{
-			Catalogs: req.Message.Catalogs, // Echo back the catalogs for now
-		},
+	pbReq, err := discoveryRequestToProto(req)
+	if err != nil {
+		return nil, fmt.Errorf("convert discovery request: %w", err)
 	}
 
-	return response, nil
+	ctx, end := startCall(ctx, discoverypb.DiscoveryService_ServiceDesc.ServiceName, "Discover")
+	pbResp, err := c.discovery.Discover(ctx, pbReq)
+	end(err)
+	if err != nil {
+		return nil, toAppError(fmt.Errorf("discover: %w", err))
+	}
+
+	return discoveryResponseFromProto(pbResp), nil
+}
+
+// Check reports whether the connection is ready, triggering a connection
+// attempt when idle and waiting for it until ctx is done. It is used as the
+// readiness check of the discovery backend.
+func (c *Client) Check(ctx context.Context) error {
+	for {
+		state := c.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Idle:
+			c.conn.Connect()
+		case connectivity.Shutdown:
+			return errors.New("grpc connection is shut down")
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("grpc connection is %s", strings.ToLower(state.String()))
+		}
+	}
+}
+
+// Conn returns the underlying gRPC connection.
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
 }
 
 // Close closes the gRPC client connection
 func (c *Client) Close() error {
-	// In a real implementation, this would close the gRPC connection
-	return nil
+	return c.conn.Close()
 }
-
@@ -0,0 +1,393 @@
+package grpc
+
+import (
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"bff-go-mvp/pkg/models"
+	discoverypb "bff-go-mvp/proto/discovery/gen"
+)
+
+// This file converts between the JSON-shaped pkg/models discovery types and
+// the generated protobuf messages. Free-form attribute maps travel as
+// google.protobuf.Struct, so only JSON-compatible values can be sent.
+
+func discoveryRequestToProto(req *models.DiscoveryRequest) (*discoverypb.DiscoveryRequest, error) {
+	msg, err := messageToProto(req.Message)
+	if err != nil {
+		return nil, err
+	}
+	return &discoverypb.DiscoveryRequest{
+		Context: contextToProto(req.Context),
+		Message: msg,
+	}, nil
+}
+
+func discoveryResponseFromProto(resp *discoverypb.DiscoveryResponse) *models.DiscoveryResponse {
+	return &models.DiscoveryResponse{
+		Context: contextFromProto(resp.GetContext()),
+		Message: messageFromProto(resp.GetMessage()),
+	}
+}
+
+func contextToProto(c models.Context) *discoverypb.Context {
+	return &discoverypb.Context{
+		Version: c.Version,
+		Action:  c.Action,
+		Domain:  c.Domain,
+		Location: &discoverypb.Location{
+			Country: &discoverypb.Country{Code: c.Location.Country.Code},
+			City:    &discoverypb.City{Code: c.Location.City.Code},
+		},
+		BapId:         c.BapID,
+		BapUri:        c.BapURI,
+		BppId:         c.BppID,
+		BppUri:        c.BppURI,
+		TransactionId: c.TransactionID,
+		MessageId:     c.MessageID,
+		Timestamp:     c.Timestamp,
+		Ttl:           c.TTL,
+		SchemaContext: c.SchemaContext,
+	}
+}
+
+func contextFromProto(c *discoverypb.Context) models.Context {
+	return models.Context{
+		Version: c.GetVersion(),
+		Action:  c.GetAction(),
+		Domain:  c.GetDomain(),
+		Location: models.Location{
+			Country: models.Country{Code: c.GetLocation().GetCountry().GetCode()},
+			City:    models.City{Code: c.GetLocation().GetCity().GetCode()},
+		},
+		BapID:         c.GetBapId(),
+		BapURI:        c.GetBapUri(),
+		BppID:         c.GetBppId(),
+		BppURI:        c.GetBppUri(),
+		TransactionID: c.GetTransactionId(),
+		MessageID:     c.GetMessageId(),
+		Timestamp:     c.GetTimestamp(),
+		TTL:           c.GetTtl(),
+		SchemaContext: c.GetSchemaContext(),
+	}
+}
+
+func messageToProto(m models.Message) (*discoverypb.Message, error) {
+	catalogs := make([]*discoverypb.Catalog, 0, len(m.Catalogs))
+	for _, c := range m.Catalogs {
+		pc, err := catalogToProto(c)
+		if err != nil {
+			return nil, err
+		}
+		catalogs = append(catalogs, pc)
+	}
+	return &discoverypb.Message{
+		Intent:   intentToProto(m.Intent),
+		Catalogs: catalogs,
+	}, nil
+}
+
+func messageFromProto(m *discoverypb.Message) models.Message {
+	catalogs := make([]models.Catalog, 0, len(m.GetCatalogs()))
+	for _, c := range m.GetCatalogs() {
+		catalogs = append(catalogs, catalogFromProto(c))
+	}
+	return models.Message{
+		Intent:   intentFromProto(m.GetIntent()),
+		Catalogs: catalogs,
+	}
+}
+
+func intentToProto(i *models.Intent) *discoverypb.Intent {
+	if i == nil {
+		return nil
+	}
+	out := &discoverypb.Intent{EvseId: i.EvseID}
+	if i.Circle != nil {
+		out.Circle = &discoverypb.Circle{
+			Gps:          geoToProto(i.Circle.Gps),
+			RadiusMeters: i.Circle.RadiusMeters,
+		}
+	}
+	if i.TimeWindow != nil {
+		out.TimeWindow = &discoverypb.TimeWindow{Start: i.TimeWindow.Start, End: i.TimeWindow.End}
+	}
+	if f := i.Filters; f != nil {
+		out.Filters = &discoverypb.IntentFilters{
+			Cpo:           f.CPO,
+			ConnectorType: f.ConnectorType,
+			MinPowerKw:    f.MinPowerKW,
+			Amenities:     f.Amenities,
+		}
+		if f.Vehicle != nil {
+			out.Filters.Vehicle = &discoverypb.Vehicle{Make: f.Vehicle.Make, Model: f.Vehicle.Model, Type: f.Vehicle.Type}
+		}
+	}
+	return out
+}
+
+func intentFromProto(i *discoverypb.Intent) *models.Intent {
+	if i == nil {
+		return nil
+	}
+	out := &models.Intent{EvseID: i.GetEvseId()}
+	if c := i.GetCircle(); c != nil {
+		out.Circle = &models.Circle{Gps: geoFromProto(c.GetGps()), RadiusMeters: c.GetRadiusMeters()}
+	}
+	if tw := i.GetTimeWindow(); tw != nil {
+		out.TimeWindow = &models.TimeWindow{Start: tw.GetStart(), End: tw.GetEnd()}
+	}
+	if f := i.GetFilters(); f != nil {
+		out.Filters = &models.IntentFilters{
+			CPO:           f.GetCpo(),
+			ConnectorType: f.GetConnectorType(),
+			MinPowerKW:    f.GetMinPowerKw(),
+			Amenities:     f.GetAmenities(),
+		}
+		if v := f.GetVehicle(); v != nil {
+			out.Filters.Vehicle = &models.IntentVehicle{Make: v.GetMake(), Model: v.GetModel(), Type: v.GetType()}
+		}
+	}
+	return out
+}
+
+func catalogToProto(c models.Catalog) (*discoverypb.Catalog, error) {
+	items := make([]*discoverypb.Item, 0, len(c.Items))
+	for _, it := range c.Items {
+		pi, err := itemToProto(it)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, pi)
+	}
+	return &discoverypb.Catalog{
+		Context:     c.Context,
+		Type:        c.Type,
+		Id:          c.ID,
+		Descriptor_: descriptorToProto(c.Descriptor),
+		Provider:    providerToProto(c.Provider),
+		Items:       items,
+	}, nil
+}
+
+func catalogFromProto(c *discoverypb.Catalog) models.Catalog {
+	items := make([]models.Item, 0, len(c.GetItems()))
+	for _, it := range c.GetItems() {
+		items = append(items, itemFromProto(it))
+	}
+	return models.Catalog{
+		Context:    c.GetContext(),
+		Type:       c.GetType(),
+		ID:         c.GetId(),
+		Descriptor: descriptorFromProto(c.GetDescriptor_()),
+		Provider:   providerFromProto(c.GetProvider()),
+		Items:      items,
+	}
+}
+
+func descriptorToProto(d models.Descriptor) *discoverypb.Descriptor {
+	return &discoverypb.Descriptor{Name: d.Name, ShortDesc: d.ShortDesc}
+}
+
+func descriptorFromProto(d *discoverypb.Descriptor) models.Descriptor {
+	return models.Descriptor{Name: d.GetName(), ShortDesc: d.GetShortDesc()}
+}
+
+func providerToProto(p models.Provider) *discoverypb.Provider {
+	contacts := make([]*discoverypb.Contact, 0, len(p.Contact))
+	for _, c := range p.Contact {
+		contacts = append(contacts, &discoverypb.Contact{Phone: c.Phone, Email: c.Email})
+	}
+	return &discoverypb.Provider{
+		Context:     p.Context,
+		Type:        p.Type,
+		Id:          p.ID,
+		Descriptor_: descriptorToProto(p.Descriptor),
+		Address:     addressToProto(p.Address),
+		Contact:     contacts,
+	}
+}
+
+func providerFromProto(p *discoverypb.Provider) models.Provider {
+	var contacts []models.Contact
+	for _, c := range p.GetContact() {
+		contacts = append(contacts, models.Contact{Phone: c.GetPhone(), Email: c.GetEmail()})
+	}
+	return models.Provider{
+		Context:    p.GetContext(),
+		Type:       p.GetType(),
+		ID:         p.GetId(),
+		Descriptor: descriptorFromProto(p.GetDescriptor_()),
+		Address:    addressFromProto(p.GetAddress()),
+		Contact:    contacts,
+	}
+}
+
+func addressToProto(a models.Address) *discoverypb.Address {
+	return &discoverypb.Address{
+		AreaCode: a.AreaCode,
+		City:     &discoverypb.City{Code: a.City.Code},
+		State:    &discoverypb.State{Code: a.State.Code},
+		Country:  &discoverypb.Country{Code: a.Country.Code},
+		Full:     a.Full,
+	}
+}
+
+func addressFromProto(a *discoverypb.Address) models.Address {
+	return models.Address{
+		AreaCode: a.GetAreaCode(),
+		City:     models.City{Code: a.GetCity().GetCode()},
+		State:    models.State{Code: a.GetState().GetCode()},
+		Country:  models.Country{Code: a.GetCountry().GetCode()},
+		Full:     a.GetFull(),
+	}
+}
+
+func itemToProto(it models.Item) (*discoverypb.Item, error) {
+	attrs, err := attributesToProto(it.ItemAttributes)
+	if err != nil {
+		return nil, err
+	}
+	availableAt := make([]*discoverypb.LocationItem, 0, len(it.AvailableAt))
+	for _, l := range it.AvailableAt {
+		availableAt = append(availableAt, &discoverypb.LocationItem{
+			Type:    l.Type,
+			Geo:     geoToProto(l.Geo),
+			Address: addressToProto(l.Address),
+		})
+	}
+	offers := make([]*discoverypb.Offer, 0, len(it.Offers))
+	for _, o := range it.Offers {
+		po, err := offerToProto(o)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, po)
+	}
+	out := &discoverypb.Item{
+		Context:     it.Context,
+		Type:        it.Type,
+		Id:          it.ID,
+		Descriptor_: descriptorToProto(it.Descriptor),
+		Category: &discoverypb.Category{
+			Type:        it.Category.Type,
+			Id:          it.Category.ID,
+			Descriptor_: descriptorToProto(it.Category.Descriptor),
+		},
+		ItemAttributes: attrs,
+		AvailableAt:    availableAt,
+		Offers:         offers,
+	}
+	if it.Rating != nil {
+		out.Rating = &discoverypb.Rating{Value: it.Rating.Value, Count: int32(it.Rating.Count)}
+	}
+	return out, nil
+}
+
+func itemFromProto(it *discoverypb.Item) models.Item {
+	var availableAt []models.LocationItem
+	for _, l := range it.GetAvailableAt() {
+		availableAt = append(availableAt, models.LocationItem{
+			Type:    l.GetType(),
+			Geo:     geoFromProto(l.GetGeo()),
+			Address: addressFromProto(l.GetAddress()),
+		})
+	}
+	var offers []models.Offer
+	for _, o := range it.GetOffers() {
+		offers = append(offers, offerFromProto(o))
+	}
+	out := models.Item{
+		Context:    it.GetContext(),
+		Type:       it.GetType(),
+		ID:         it.GetId(),
+		Descriptor: descriptorFromProto(it.GetDescriptor_()),
+		Category: models.Category{
+			Type:       it.GetCategory().GetType(),
+			ID:         it.GetCategory().GetId(),
+			Descriptor: descriptorFromProto(it.GetCategory().GetDescriptor_()),
+		},
+		ItemAttributes: attributesFromProto(it.GetItemAttributes()),
+		AvailableAt:    availableAt,
+		Offers:         offers,
+	}
+	if r := it.GetRating(); r != nil {
+		out.Rating = &models.Rating{Value: r.GetValue(), Count: int(r.GetCount())}
+	}
+	return out
+}
+
+func geoToProto(g models.Geo) *discoverypb.Geo {
+	return &discoverypb.Geo{Type: g.Type, Coordinates: g.Coordinates}
+}
+
+func geoFromProto(g *discoverypb.Geo) models.Geo {
+	return models.Geo{Type: g.GetType(), Coordinates: g.GetCoordinates()}
+}
+
+func offerToProto(o models.Offer) (*discoverypb.Offer, error) {
+	attrs, err := attributesToProto(o.OfferAttributes)
+	if err != nil {
+		return nil, err
+	}
+	return &discoverypb.Offer{
+		Context:     o.Context,
+		Type:        o.Type,
+		Id:          o.ID,
+		Descriptor_: descriptorToProto(o.Descriptor),
+		Price: &discoverypb.Price{
+			Currency: o.Price.Currency,
+			Value:    o.Price.Value,
+			ApplicableQuantity: &discoverypb.ApplicableQuantity{
+				UnitText:     o.Price.ApplicableQuantity.UnitText,
+				UnitCode:     o.Price.ApplicableQuantity.UnitCode,
+				UnitQuantity: int32(o.Price.ApplicableQuantity.UnitQuantity),
+			},
+		},
+		Validity: &discoverypb.Validity{
+			Type:      o.Validity.Type,
+			StartDate: o.Validity.StartDate,
+			EndDate:   o.Validity.EndDate,
+		},
+		AcceptedPaymentMethod: o.AcceptedPaymentMethod,
+		OfferAttributes:       attrs,
+	}, nil
+}
+
+func offerFromProto(o *discoverypb.Offer) models.Offer {
+	return models.Offer{
+		Context:    o.GetContext(),
+		Type:       o.GetType(),
+		ID:         o.GetId(),
+		Descriptor: descriptorFromProto(o.GetDescriptor_()),
+		Price: models.Price{
+			Currency: o.GetPrice().GetCurrency(),
+			Value:    o.GetPrice().GetValue(),
+			ApplicableQuantity: models.ApplicableQuantity{
+				UnitText:     o.GetPrice().GetApplicableQuantity().GetUnitText(),
+				UnitCode:     o.GetPrice().GetApplicableQuantity().GetUnitCode(),
+				UnitQuantity: int(o.GetPrice().GetApplicableQuantity().GetUnitQuantity()),
+			},
+		},
+		Validity: models.Validity{
+			Type:      o.GetValidity().GetType(),
+			StartDate: o.GetValidity().GetStartDate(),
+			EndDate:   o.GetValidity().GetEndDate(),
+		},
+		AcceptedPaymentMethod: o.GetAcceptedPaymentMethod(),
+		OfferAttributes:       attributesFromProto(o.GetOfferAttributes()),
+	}
+}
+
+func attributesToProto(attrs map[string]interface{}) (*structpb.Struct, error) {
+	if attrs == nil {
+		return nil, nil
+	}
+	return structpb.NewStruct(attrs)
+}
+
+func attributesFromProto(s *structpb.Struct) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.AsMap()
+}
@@ -0,0 +1,27 @@
+package grpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"bff-go-mvp/internal/apperror"
+)
+
+// toAppError classifies a failed RPC by its status code. The gRPC status
+// stays in the error chain, so status.Code still works on the result.
+func toAppError(err error) error {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return apperror.Wrap(apperror.KindBadRequest, "Invalid request parameters.", err)
+	case codes.NotFound:
+		return apperror.Wrap(apperror.KindNotFound, "Resource not found.", err)
+	case codes.Unauthenticated:
+		return apperror.Wrap(apperror.KindUnauthorized, "Invalid or missing authentication token.", err)
+	case codes.PermissionDenied:
+		return apperror.Wrap(apperror.KindForbidden, "Access to the resource is not allowed.", err)
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return apperror.Wrap(apperror.KindUnavailable, "Discovery service is temporarily unavailable.", err)
+	default:
+		return apperror.Internal(err)
+	}
+}
@@ -0,0 +1,68 @@
+// Package grpctest provides an in-process discovery server backed by
+// bufconn, so gRPC-backed services can be exercised without a network.
+package grpctest
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	discoverypb "bff-go-mvp/proto/discovery/gen"
+)
+
+const bufSize = 1024 * 1024
+
+// Server is a discovery gRPC server listening on an in-memory pipe.
+type Server struct {
+	listener *bufconn.Listener
+	server   *grpc.Server
+}
+
+// NewServer starts serving impl on a fresh bufconn listener.
+func NewServer(impl discoverypb.DiscoveryServiceServer) *Server {
+	lis := bufconn.Listen(bufSize)
+	srv := grpc.NewServer()
+	discoverypb.RegisterDiscoveryServiceServer(srv, impl)
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	return &Server{listener: lis, server: srv}
+}
+
+// Dial returns a client connection to the in-process server.
+func (s *Server) Dial() (*grpc.ClientConn, error) {
+	return grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+}
+
+// Close stops the server and releases the listener.
+func (s *Server) Close() {
+	s.server.Stop()
+	_ = s.listener.Close()
+}
+
+// DiscoverFunc adapts a function into a DiscoveryServiceServer.
+type DiscoverFunc func(ctx context.Context, req *discoverypb.DiscoveryRequest) (*discoverypb.DiscoveryResponse, error)
+
+type funcServer struct {
+	discoverypb.UnimplementedDiscoveryServiceServer
+	fn DiscoverFunc
+}
+
+func (f funcServer) Discover(ctx context.Context, req *discoverypb.DiscoveryRequest) (*discoverypb.DiscoveryResponse, error) {
+	return f.fn(ctx, req)
+}
+
+// NewFuncServer starts a server whose Discover RPC is handled by fn.
+func NewFuncServer(fn DiscoverFunc) *Server {
+	return NewServer(funcServer{fn: fn})
+}
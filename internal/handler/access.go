@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/domain/orders"
+	"bff-go-mvp/internal/httpx"
+	"bff-go-mvp/internal/transaction"
+)
+
+// authorizeOrder writes the error response and returns false when the caller
+// may not act on the order.
+func authorizeOrder(w http.ResponseWriter, r *http.Request, access *orders.AccessPolicy, logger *zap.Logger, orderID string) bool {
+	if err := access.Authorize(r.Context(), orderID); err != nil {
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), logger), "order authorization failed", err)
+		return false
+	}
+	return true
+}
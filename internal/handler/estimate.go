@@ -2,75 +2,91 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"go.uber.org/zap"
 
+	"bff-go-mvp/internal/apperror"
 	"bff-go-mvp/internal/domain/estimate"
+	"bff-go-mvp/internal/domain/orders"
 	"bff-go-mvp/internal/httpx"
 	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+	"bff-go-mvp/internal/transaction"
 )
 
 // EstimateHandler handles POST /v1/estimate requests.
 type EstimateHandler struct {
 	service estimate.Service
+	access  *orders.AccessPolicy
 	logger  *zap.Logger
 }
 
-func NewEstimateHandler(service estimate.Service, logger *zap.Logger) *EstimateHandler {
+func NewEstimateHandler(service estimate.Service, access *orders.AccessPolicy, logger *zap.Logger) *EstimateHandler {
 	return &EstimateHandler{
 		service: service,
+		access:  access,
 		logger:  logger,
 	}
 }
 
 // GetEstimates handles the estimate API.
 // @Summary Get charging cost and time estimate
-// @Description Returns an estimated cost, duration, and other pricing details for a charging session.
+// @Description Returns an estimated cost, duration, and other pricing details for a charging session. The selected offer is priced for the requested energy, or for as much energy as the requested amount covers; price components always sum to the amount. Duration and battery gain follow the vehicle's charging curve from state_of_charge.
 // @Tags Estimate
 // @Accept json
 // @Produce json
-// @Param X-Transaction-Id header string true "Unique transaction identifier"
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
+// @Param X-Bpp-Id header string false "Backend provider identifier"
+// @Param X-Money-Format header string false "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent" Enums(decimal, legacy)
 // @Param request body model.EstimateRequest true "Estimate request payload"
 // @Success 200 {object} model.EstimateResponse
 // @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 422 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Security BearerAuth
 // @Router /v1/estimate [post]
 func (h *EstimateHandler) GetEstimates(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		httpx.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
-		return
-	}
-
-	// Required header in swagger: X-Transaction-Id
-	txnID := r.Header.Get("X-Transaction-Id")
-	if txnID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Missing required header X-Transaction-Id")
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
 	var req model.EstimateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Warn("failed to decode estimate request", zap.Error(err))
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request body")
+		transaction.Logger(r.Context(), h.logger).Warn("failed to decode estimate request", zap.Error(err))
+		if errors.Is(err, money.ErrOutOfRange) {
+			httpx.WriteAppError(w, apperror.Validation("amount is out of range").WithDetail("field", "amount.value"))
+			return
+		}
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request body"))
 		return
 	}
 
 	// Basic required field validation per swagger: evse_id, vehicle, connector_id
 	if req.EvseID == "" || req.ConnectorID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request parameters or missing required fields.")
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request parameters or missing required fields."))
 		return
 	}
 
 	resp, err := h.service.Estimate(r.Context(), req)
 	if err != nil {
-		h.logger.Error("estimate service failed", zap.Error(err))
-		httpx.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Server error occurred while processing the request.")
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "estimate service failed", err)
 		return
 	}
 
-	// Echo back transaction id and set a mock BPP id
-	w.Header().Set("X-Transaction-Id", txnID)
-	w.Header().Set("X-Bpp-Id", "mock-bpp-id")
-	httpx.WriteJSON(w, http.StatusOK, resp)
+	// The caller owns the order the estimate created.
+	if err := h.access.Claim(r.Context(), resp.Order.ID); err != nil {
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "recording order owner failed", err)
+		return
+	}
+
+	// Echo the BPP id when the caller addressed a specific provider
+	if bppID := r.Header.Get("X-Bpp-Id"); bppID != "" {
+		w.Header().Set("X-Bpp-Id", bppID)
+	}
+	httpx.WriteMoneyJSON(w, r, http.StatusOK, resp)
 }
@@ -7,20 +7,25 @@ import (
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"bff-go-mvp/internal/apperror"
 	"bff-go-mvp/internal/domain/feedback"
+	"bff-go-mvp/internal/domain/orders"
 	"bff-go-mvp/internal/httpx"
 	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/transaction"
 )
 
 // FeedbackHandler handles rating/feedback endpoints.
 type FeedbackHandler struct {
 	service feedback.Service
+	access  *orders.AccessPolicy
 	logger  *zap.Logger
 }
 
-func NewFeedbackHandler(service feedback.Service, logger *zap.Logger) *FeedbackHandler {
+func NewFeedbackHandler(service feedback.Service, access *orders.AccessPolicy, logger *zap.Logger) *FeedbackHandler {
 	return &FeedbackHandler{
 		service: service,
+		access:  access,
 		logger:  logger,
 	}
 }
@@ -31,54 +36,58 @@ func NewFeedbackHandler(service feedback.Service, logger *zap.Logger) *FeedbackH
 // @Tags Feedback
 // @Accept json
 // @Produce json
-// @Param X-Transaction-Id header string true "Unique transaction identifier"
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
 // @Param X-Bpp-Id header string true "Backend provider identifier"
 // @Param order_id path string true "Order ID"
 // @Param request body model.RatingRequest true "Rating request payload"
 // @Success 201 {object} model.RatingResponse
 // @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Security BearerAuth
 // @Router /v1/orders/{order_id}/rating [post]
 func (h *FeedbackHandler) SetOrderRating(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		httpx.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
-	txnID := r.Header.Get("X-Transaction-Id")
 	bppID := r.Header.Get("X-Bpp-Id")
-	if txnID == "" || bppID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid rating value or malformed request.")
+	if bppID == "" {
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid rating value or malformed request."))
 		return
 	}
 
 	vars := mux.Vars(r)
 	orderID := vars["order_id"]
 	if orderID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Missing order_id in path.")
+		httpx.WriteAppError(w, apperror.BadRequest("Missing order_id in path."))
+		return
+	}
+	if !authorizeOrder(w, r, h.access, h.logger, orderID) {
 		return
 	}
 
 	var req model.RatingRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Warn("failed to decode rating request", zap.Error(err))
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid rating value or malformed request.")
+		transaction.Logger(r.Context(), h.logger).Warn("failed to decode rating request", zap.Error(err))
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid rating value or malformed request."))
 		return
 	}
 
 	if req.Value < 1 || req.Value > 5 {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid rating value or malformed request.")
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid rating value or malformed request."))
 		return
 	}
 
 	resp, err := h.service.SetRating(r.Context(), orderID, req)
 	if err != nil {
-		h.logger.Error("set rating failed", zap.Error(err))
-		httpx.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Server error occurred while processing the request.")
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "set rating failed", err)
 		return
 	}
 
-	w.Header().Set("X-Transaction-Id", txnID)
 	w.Header().Set("X-Bpp-Id", bppID)
 	httpx.WriteJSON(w, http.StatusCreated, resp)
 }
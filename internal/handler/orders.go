@@ -6,69 +6,77 @@ import (
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"bff-go-mvp/internal/apperror"
 	"bff-go-mvp/internal/domain/orders"
 	"bff-go-mvp/internal/httpx"
 	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/transaction"
 )
 
 // OrdersHandler handles order-related endpoints.
 type OrdersHandler struct {
 	service orders.Service
+	access  *orders.AccessPolicy
 	logger  *zap.Logger
 }
 
-func NewOrdersHandler(service orders.Service, logger *zap.Logger) *OrdersHandler {
+func NewOrdersHandler(service orders.Service, access *orders.AccessPolicy, logger *zap.Logger) *OrdersHandler {
 	return &OrdersHandler{
 		service: service,
+		access:  access,
 		logger:  logger,
 	}
 }
 
 // GetOrder handles GET /v1/orders/{order_id}.
 // @Summary Get order details
-// @Description Returns current status and details of an order.
+// @Description Returns current status and details of an order. Once charging has stopped it includes the session's bill, with the idle fee accrued while the vehicle stays plugged in.
 // @Tags Orders
 // @Accept json
 // @Produce json
-// @Param X-Transaction-Id header string true "Unique transaction identifier"
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
 // @Param X-Bpp-Id header string true "Backend provider identifier"
 // @Param order_id path string true "Order ID"
+// @Param X-Money-Format header string false "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent" Enums(decimal, legacy)
 // @Success 200 {object} model.OrderResponse
 // @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Security BearerAuth
 // @Router /v1/orders/{order_id} [get]
 func (h *OrdersHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		httpx.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
-	// Required headers: X-Transaction-Id, X-Bpp-Id
-	txnID := r.Header.Get("X-Transaction-Id")
+	// Required header: X-Bpp-Id
 	bppID := r.Header.Get("X-Bpp-Id")
-	if txnID == "" || bppID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request parameters or missing required fields.")
+	if bppID == "" {
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request parameters or missing required fields."))
 		return
 	}
 
 	vars := mux.Vars(r)
 	orderID := vars["order_id"]
 	if orderID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Missing order_id in path.")
+		httpx.WriteAppError(w, apperror.BadRequest("Missing order_id in path."))
+		return
+	}
+	if !authorizeOrder(w, r, h.access, h.logger, orderID) {
 		return
 	}
 
 	resp, err := h.service.GetOrder(r.Context(), orderID)
 	if err != nil {
-		h.logger.Error("orders service failed", zap.Error(err))
-		httpx.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Server error occurred while processing the request.")
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "orders service failed", err)
 		return
 	}
 
 	// Echo headers as per swagger
-	w.Header().Set("X-Transaction-Id", txnID)
 	w.Header().Set("X-Bpp-Id", bppID)
-	httpx.WriteJSON(w, http.StatusOK, resp)
+	httpx.WriteMoneyJSON(w, r, http.StatusOK, resp)
 }
 
 // keep model types referenced for Swagger annotations
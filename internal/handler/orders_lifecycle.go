@@ -7,31 +7,35 @@ import (
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"bff-go-mvp/internal/apperror"
 	"bff-go-mvp/internal/domain/orders"
 	"bff-go-mvp/internal/httpx"
 	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/transaction"
 )
 
 // OrdersLifecycleHandler handles start/stop/cancel related endpoints.
 type OrdersLifecycleHandler struct {
 	service orders.LifecycleService
+	access  *orders.AccessPolicy
 	logger  *zap.Logger
 }
 
-func NewOrdersLifecycleHandler(service orders.LifecycleService, logger *zap.Logger) *OrdersLifecycleHandler {
+func NewOrdersLifecycleHandler(service orders.LifecycleService, access *orders.AccessPolicy, logger *zap.Logger) *OrdersLifecycleHandler {
 	return &OrdersLifecycleHandler{
 		service: service,
+		access:  access,
 		logger:  logger,
 	}
 }
 
 // EstimateCancel handles GET /v1/orders/{order_id}/cancel.
 // @Summary Estimate cancellation charges
-// @Description Returns an estimate of fees applicable if the order is cancelled.
+// @Description Returns an estimate of fees applicable if the order is cancelled, under the cancellation terms booked with the order. A cancel_code for an operator fault (see cancellation.feeWaivedFor in the estimate) waives the fee when the caller holds the operator role; buyers' codes do not.
 // @Tags Orders
 // @Accept json
 // @Produce json
-// @Param X-Transaction-Id header string true "Unique transaction identifier"
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
 // @Param X-Bpp-Id header string true "Backend provider identifier"
 // @Param order_id path string true "Order ID"
 // @Param activity query string false "Activity context for cancellation"
@@ -39,14 +43,18 @@ func NewOrdersLifecycleHandler(service orders.LifecycleService, logger *zap.Logg
 // @Param cancel_code query string false "Reason code for cancellation"
 // @Success 200 {object} model.CancelEstimateResponse
 // @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Security BearerAuth
 // @Router /v1/orders/{order_id}/cancel [get]
 func (h *OrdersLifecycleHandler) EstimateCancel(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		httpx.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
 		return
 	}
-	txnID, bppID, orderID, ok := h.validateHeadersAndOrderID(w, r)
+	bppID, orderID, ok := h.validateHeadersAndOrderID(w, r)
 	if !ok {
 		return
 	}
@@ -58,35 +66,40 @@ func (h *OrdersLifecycleHandler) EstimateCancel(w http.ResponseWriter, r *http.R
 
 	resp, err := h.service.EstimateCancel(r.Context(), orderID, activity, cancelReason, cancelCode)
 	if err != nil {
-		h.logger.Error("estimate cancel failed", zap.Error(err))
-		httpx.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Server error occurred while processing the request.")
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "estimate cancel failed", err)
 		return
 	}
 
-	h.writeStandardHeaders(w, txnID, bppID)
+	h.writeStandardHeaders(w, bppID)
 	httpx.WriteJSON(w, http.StatusOK, resp)
 }
 
 // Cancel handles POST /v1/orders/{order_id}/cancel.
 // @Summary Cancel an order
-// @Description Cancels an order and returns the final cancellation outcome.
+// @Description Cancels an order and returns the final cancellation outcome. The fee is computed exactly as by the cancellation estimate; cancel_code and cancel_reason may be sent as query parameters or body fields.
 // @Tags Orders
 // @Accept json
 // @Produce json
-// @Param X-Transaction-Id header string true "Unique transaction identifier"
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
 // @Param X-Bpp-Id header string true "Backend provider identifier"
 // @Param order_id path string true "Order ID"
+// @Param cancel_reason query string false "Reason description for cancellation"
+// @Param cancel_code query string false "Reason code for cancellation; operator-fault codes waive the fee for callers with the operator role"
 // @Param request body object false "Optional cancellation payload"
 // @Success 202 {object} model.CancelResponse
 // @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Security BearerAuth
 // @Router /v1/orders/{order_id}/cancel [post]
 func (h *OrdersLifecycleHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		httpx.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
 		return
 	}
-	txnID, bppID, orderID, ok := h.validateHeadersAndOrderID(w, r)
+	bppID, orderID, ok := h.validateHeadersAndOrderID(w, r)
 	if !ok {
 		return
 	}
@@ -94,43 +107,60 @@ func (h *OrdersLifecycleHandler) Cancel(w http.ResponseWriter, r *http.Request)
 	var body map[string]interface{}
 	if r.Body != nil {
 		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err.Error() != "EOF" {
-			h.logger.Warn("failed to decode cancel request body", zap.Error(err))
-			httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request body.")
+			transaction.Logger(r.Context(), h.logger).Warn("failed to decode cancel request body", zap.Error(err))
+			httpx.WriteAppError(w, apperror.BadRequest("Invalid request body."))
 			return
 		}
 	}
 
+	// The reason may come as query parameters, as for the estimate; body
+	// fields win.
+	q := r.URL.Query()
+	for _, key := range []string{"cancel_code", "cancel_reason"} {
+		if v := q.Get(key); v != "" {
+			if body == nil {
+				body = map[string]interface{}{}
+			}
+			if _, ok := body[key]; !ok {
+				body[key] = v
+			}
+		}
+	}
+
 	resp, err := h.service.Cancel(r.Context(), orderID, body)
 	if err != nil {
-		h.logger.Error("cancel failed", zap.Error(err))
-		httpx.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Server error occurred while processing the request.")
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "cancel failed", err)
 		return
 	}
 
-	h.writeStandardHeaders(w, txnID, bppID)
+	h.writeStandardHeaders(w, bppID)
 	httpx.WriteJSON(w, http.StatusAccepted, resp)
 }
 
 // EstimateStop handles GET /v1/orders/{order_id}/stop.
 // @Summary Estimate stop charging outcome
-// @Description Returns an estimate of charges and status if the session is stopped now.
+// @Description Returns an estimate of charges and status if the session is stopped now: the session's ENERGY and SESSION_DURATION telemetry billed with the booked offer, less the prepaid amount. Stopping bills the same way.
 // @Tags Orders
 // @Accept json
 // @Produce json
-// @Param X-Transaction-Id header string true "Unique transaction identifier"
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
 // @Param X-Bpp-Id header string true "Backend provider identifier"
 // @Param order_id path string true "Order ID"
 // @Param activity query string false "Activity context for stop"
 // @Success 200 {object} model.StopEstimateResponse
 // @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Security BearerAuth
 // @Router /v1/orders/{order_id}/stop [get]
 func (h *OrdersLifecycleHandler) EstimateStop(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		httpx.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
 		return
 	}
-	txnID, bppID, orderID, ok := h.validateHeadersAndOrderID(w, r)
+	bppID, orderID, ok := h.validateHeadersAndOrderID(w, r)
 	if !ok {
 		return
 	}
@@ -139,35 +169,39 @@ func (h *OrdersLifecycleHandler) EstimateStop(w http.ResponseWriter, r *http.Req
 
 	resp, err := h.service.EstimateStop(r.Context(), orderID, activity)
 	if err != nil {
-		h.logger.Error("estimate stop failed", zap.Error(err))
-		httpx.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Server error occurred while processing the request.")
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "estimate stop failed", err)
 		return
 	}
 
-	h.writeStandardHeaders(w, txnID, bppID)
+	h.writeStandardHeaders(w, bppID)
 	httpx.WriteJSON(w, http.StatusOK, resp)
 }
 
 // StopCharging handles PUT /v1/orders/{order_id}/stop.
 // @Summary Stop charging session
-// @Description Stops an ongoing charging session and returns final pricing information.
+// @Description Stops an ongoing charging session and returns final pricing information: the session's ENERGY and SESSION_DURATION telemetry billed with the booked offer, then a refund of any unused prepaid amount; breakdown.total is the balance due.
 // @Tags Orders
 // @Accept json
 // @Produce json
-// @Param X-Transaction-Id header string true "Unique transaction identifier"
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
 // @Param X-Bpp-Id header string true "Backend provider identifier"
 // @Param order_id path string true "Order ID"
+// @Param X-Money-Format header string false "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent" Enums(decimal, legacy)
 // @Param request body model.StopChargingRequest false "Optional stop reason payload"
 // @Success 200 {object} model.StopChargingResponse
 // @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Security BearerAuth
 // @Router /v1/orders/{order_id}/stop [put]
 func (h *OrdersLifecycleHandler) StopCharging(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
-		httpx.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
 		return
 	}
-	txnID, bppID, orderID, ok := h.validateHeadersAndOrderID(w, r)
+	bppID, orderID, ok := h.validateHeadersAndOrderID(w, r)
 	if !ok {
 		return
 	}
@@ -175,21 +209,20 @@ func (h *OrdersLifecycleHandler) StopCharging(w http.ResponseWriter, r *http.Req
 	var req model.StopChargingRequest
 	if r.Body != nil {
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err.Error() != "EOF" {
-			h.logger.Warn("failed to decode stop request", zap.Error(err))
-			httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request body.")
+			transaction.Logger(r.Context(), h.logger).Warn("failed to decode stop request", zap.Error(err))
+			httpx.WriteAppError(w, apperror.BadRequest("Invalid request body."))
 			return
 		}
 	}
 
 	resp, err := h.service.Stop(r.Context(), orderID, req)
 	if err != nil {
-		h.logger.Error("stop charging failed", zap.Error(err))
-		httpx.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Server error occurred while processing the request.")
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "stop charging failed", err)
 		return
 	}
 
-	h.writeStandardHeaders(w, txnID, bppID)
-	httpx.WriteJSON(w, http.StatusOK, resp)
+	h.writeStandardHeaders(w, bppID)
+	httpx.WriteMoneyJSON(w, r, http.StatusOK, resp)
 }
 
 // StartCharging handles PUT /v1/orders/{order_id}/start.
@@ -198,20 +231,24 @@ func (h *OrdersLifecycleHandler) StopCharging(w http.ResponseWriter, r *http.Req
 // @Tags Orders
 // @Accept json
 // @Produce json
-// @Param X-Transaction-Id header string true "Unique transaction identifier"
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
 // @Param X-Bpp-Id header string true "Backend provider identifier"
 // @Param order_id path string true "Order ID"
 // @Param request body model.StartChargingRequest false "Start charging payload"
 // @Success 202 {object} model.StartChargingResponse
 // @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Security BearerAuth
 // @Router /v1/orders/{order_id}/start [put]
 func (h *OrdersLifecycleHandler) StartCharging(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
-		httpx.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
 		return
 	}
-	txnID, bppID, orderID, ok := h.validateHeadersAndOrderID(w, r)
+	bppID, orderID, ok := h.validateHeadersAndOrderID(w, r)
 	if !ok {
 		return
 	}
@@ -219,42 +256,79 @@ func (h *OrdersLifecycleHandler) StartCharging(w http.ResponseWriter, r *http.Re
 	var req model.StartChargingRequest
 	if r.Body != nil {
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err.Error() != "EOF" {
-			h.logger.Warn("failed to decode start request", zap.Error(err))
-			httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request body.")
+			transaction.Logger(r.Context(), h.logger).Warn("failed to decode start request", zap.Error(err))
+			httpx.WriteAppError(w, apperror.BadRequest("Invalid request body."))
 			return
 		}
 	}
 
 	resp, err := h.service.Start(r.Context(), orderID, req)
 	if err != nil {
-		h.logger.Error("start charging failed", zap.Error(err))
-		httpx.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Server error occurred while processing the request.")
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "start charging failed", err)
 		return
 	}
 
-	h.writeStandardHeaders(w, txnID, bppID)
+	h.writeStandardHeaders(w, bppID)
 	httpx.WriteJSON(w, http.StatusAccepted, resp)
 }
 
-func (h *OrdersLifecycleHandler) validateHeadersAndOrderID(w http.ResponseWriter, r *http.Request) (txnID, bppID, orderID string, ok bool) {
-	txnID = r.Header.Get("X-Transaction-Id")
+// Unplug handles PUT /v1/orders/{order_id}/unplug.
+// @Summary Report connector unplugged
+// @Description Records that the vehicle was unplugged after charging completed. The time between charging completing and the unplug, beyond the offer's grace period, is billed as an IDLE_FEE component under the offer's idle fee policy, and the session is settled again.
+// @Tags Orders
+// @Produce json
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
+// @Param X-Bpp-Id header string true "Backend provider identifier"
+// @Param order_id path string true "Order ID"
+// @Param X-Money-Format header string false "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent" Enums(decimal, legacy)
+// @Success 200 {object} model.UnplugResponse
+// @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Security BearerAuth
+// @Router /v1/orders/{order_id}/unplug [put]
+func (h *OrdersLifecycleHandler) Unplug(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
+		return
+	}
+	bppID, orderID, ok := h.validateHeadersAndOrderID(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Unplug(r.Context(), orderID)
+	if err != nil {
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "unplug failed", err)
+		return
+	}
+
+	h.writeStandardHeaders(w, bppID)
+	httpx.WriteMoneyJSON(w, r, http.StatusOK, resp)
+}
+
+func (h *OrdersLifecycleHandler) validateHeadersAndOrderID(w http.ResponseWriter, r *http.Request) (bppID, orderID string, ok bool) {
 	bppID = r.Header.Get("X-Bpp-Id")
-	if txnID == "" || bppID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request parameters or missing required fields.")
-		return "", "", "", false
+	if bppID == "" {
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request parameters or missing required fields."))
+		return "", "", false
 	}
 
 	vars := mux.Vars(r)
 	orderID = vars["order_id"]
 	if orderID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Missing order_id in path.")
-		return "", "", "", false
+		httpx.WriteAppError(w, apperror.BadRequest("Missing order_id in path."))
+		return "", "", false
+	}
+	if !authorizeOrder(w, r, h.access, h.logger, orderID) {
+		return "", "", false
 	}
 
-	return txnID, bppID, orderID, true
+	return bppID, orderID, true
 }
 
-func (h *OrdersLifecycleHandler) writeStandardHeaders(w http.ResponseWriter, txnID, bppID string) {
-	w.Header().Set("X-Transaction-Id", txnID)
+func (h *OrdersLifecycleHandler) writeStandardHeaders(w http.ResponseWriter, bppID string) {
 	w.Header().Set("X-Bpp-Id", bppID)
 }
@@ -7,81 +7,142 @@ import (
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/orders"
 	"bff-go-mvp/internal/domain/payment"
 	"bff-go-mvp/internal/httpx"
 	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/transaction"
 )
 
-// PaymentHandler handles POST /v1/orders/{order_id}/payment requests.
+// PaymentHandler handles the payment and refunds of orders.
 type PaymentHandler struct {
 	service payment.Service
+	access  *orders.AccessPolicy
 	logger  *zap.Logger
 }
 
-func NewPaymentHandler(service payment.Service, logger *zap.Logger) *PaymentHandler {
+func NewPaymentHandler(service payment.Service, access *orders.AccessPolicy, logger *zap.Logger) *PaymentHandler {
 	return &PaymentHandler{
 		service: service,
+		access:  access,
 		logger:  logger,
 	}
 }
 
 // InitiatePayment handles the payment initiation API.
 // @Summary Initiate payment for an order
-// @Description Initiates a payment flow for the specified order.
+// @Description Initiates payment of the order's quoted amount through the payment gateway adapter of the chosen method: a upi:// intent link for UPI_INTENT, a collect request to the buyer's VPA for UPI_COLLECT, or the PSP checkout page for CARD and WALLET. paymentUrl is where the buyer pays, and attempt tracks the payment attempt recorded on the order. The order becomes ACTIVE once the PSP captures the payment; initiating again with the same method returns the pending attempt.
 // @Tags Payment
 // @Accept json
 // @Produce json
-// @Param X-Transaction-Id header string true "Unique transaction identifier"
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
 // @Param X-Bpp-Id header string true "Backend provider identifier"
+// @Param X-Money-Format header string false "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent" Enums(decimal, legacy)
 // @Param order_id path string true "Order ID"
-// @Param request body object false "Payment initiation payload"
+// @Param request body model.PaymentRequest false "Payment initiation payload"
 // @Success 200 {object} model.PaymentResponse
 // @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Security BearerAuth
 // @Router /v1/orders/{order_id}/payment [post]
 func (h *PaymentHandler) InitiatePayment(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		httpx.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
-	// Required headers: X-Transaction-Id, X-Bpp-Id
-	txnID := r.Header.Get("X-Transaction-Id")
+	// Required header: X-Bpp-Id
 	bppID := r.Header.Get("X-Bpp-Id")
-	if txnID == "" || bppID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request parameters or malformed request body.")
+	if bppID == "" {
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request parameters or malformed request body."))
 		return
 	}
 
 	vars := mux.Vars(r)
 	orderID := vars["order_id"]
 	if orderID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Missing order_id in path.")
+		httpx.WriteAppError(w, apperror.BadRequest("Missing order_id in path."))
+		return
+	}
+	if !authorizeOrder(w, r, h.access, h.logger, orderID) {
 		return
 	}
 
-	var body map[string]interface{}
+	var req model.PaymentRequest
 	if r.Body != nil {
-		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err.Error() != "EOF" {
-			h.logger.Warn("failed to decode payment request body", zap.Error(err))
-			httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request body.")
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err.Error() != "EOF" {
+			transaction.Logger(r.Context(), h.logger).Warn("failed to decode payment request body", zap.Error(err))
+			httpx.WriteAppError(w, apperror.BadRequest("Invalid request body."))
 			return
 		}
 	}
 
-	resp, err := h.service.InitiatePayment(r.Context(), orderID, body)
+	resp, err := h.service.InitiatePayment(r.Context(), orderID, req)
 	if err != nil {
-		h.logger.Error("payment service failed", zap.Error(err))
-		httpx.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Server error occurred while processing the request.")
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "payment service failed", err)
 		return
 	}
 
 	// Echo back headers as per swagger
-	w.Header().Set("X-Transaction-Id", txnID)
 	w.Header().Set("X-Bpp-Id", bppID)
-	httpx.WriteJSON(w, http.StatusOK, resp)
+	httpx.WriteMoneyJSON(w, r, http.StatusOK, resp)
+}
+
+// ListRefunds handles GET /v1/orders/{order_id}/refunds.
+// @Summary List order refunds
+// @Description Returns the refunds of an order, oldest first. Cancelling a paid order refunds what the cancellation fee leaves, and stopping a session refunds the unused prepaid amount; each refund is sent through the payment gateway of the captured payment and retried with backoff when it fails. refunded sums the refunds that succeeded; the payment becomes REFUNDED once they add up to the captured amount and stays PAID after a partial refund.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
+// @Param X-Bpp-Id header string true "Backend provider identifier"
+// @Param X-Money-Format header string false "Money format of the response: decimal (strings) or legacy (numbers); MONEY_FORMAT when absent" Enums(decimal, legacy)
+// @Param order_id path string true "Order ID"
+// @Success 200 {object} model.RefundsResponse
+// @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Security BearerAuth
+// @Router /v1/orders/{order_id}/refunds [get]
+func (h *PaymentHandler) ListRefunds(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	// Required header: X-Bpp-Id
+	bppID := r.Header.Get("X-Bpp-Id")
+	if bppID == "" {
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request parameters or missing required fields."))
+		return
+	}
+
+	vars := mux.Vars(r)
+	orderID := vars["order_id"]
+	if orderID == "" {
+		httpx.WriteAppError(w, apperror.BadRequest("Missing order_id in path."))
+		return
+	}
+	if !authorizeOrder(w, r, h.access, h.logger, orderID) {
+		return
+	}
+
+	resp, err := h.service.ListRefunds(r.Context(), orderID)
+	if err != nil {
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "payment service failed", err)
+		return
+	}
+
+	w.Header().Set("X-Bpp-Id", bppID)
+	httpx.WriteMoneyJSON(w, r, http.StatusOK, resp)
 }
 
 // keep model types referenced for Swagger annotations
+var _ model.RefundsResponse
 var _ model.PaymentResponse
 var _ model.Error
@@ -4,30 +4,36 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"go.uber.org/zap"
 
+	"bff-go-mvp/internal/apperror"
 	"bff-go-mvp/internal/domain/search"
 	"bff-go-mvp/internal/httpx"
 	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/transaction"
+	"bff-go-mvp/internal/vehicles"
 )
 
 // SearchHandler handles POST /v1/search requests.
 type SearchHandler struct {
-	service search.Service
-	logger  *zap.Logger
+	service  search.Service
+	vehicles *vehicles.Registry
+	logger   *zap.Logger
 }
 
-func NewSearchHandler(service search.Service, logger *zap.Logger) *SearchHandler {
+func NewSearchHandler(service search.Service, registry *vehicles.Registry, logger *zap.Logger) *SearchHandler {
 	return &SearchHandler{
-		service: service,
-		logger:  logger,
+		service:  service,
+		vehicles: registry,
+		logger:   logger,
 	}
 }
 
 // SearchChargingConnectors handles the search API.
 // @Summary Search for EV charging connectors
-// @Description Search charging locations/connectors by EVSE ID or geo-coordinates with optional filters.
+// @Description Search charging locations/connectors by EVSE ID or geo-coordinates with optional filters, sort and availability (time_window or open_now). Unsupported connector types, vehicle types, sort keys or orders and invalid time windows return 422.
 // @Tags Search
 // @Accept json
 // @Produce json
@@ -36,11 +42,14 @@ func NewSearchHandler(service search.Service, logger *zap.Logger) *SearchHandler
 // @Param request body model.SearchRequest true "Search request payload"
 // @Success 200 {object} model.SearchResponse
 // @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 422 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Security BearerAuth
 // @Router /v1/search [post]
 func (h *SearchHandler) SearchChargingConnectors(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		httpx.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
@@ -62,8 +71,8 @@ func (h *SearchHandler) SearchChargingConnectors(w http.ResponseWriter, r *http.
 
 	var req model.SearchRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Warn("failed to decode search request", zap.Error(err))
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request body")
+		transaction.Logger(r.Context(), h.logger).Warn("failed to decode search request", zap.Error(err))
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request body"))
 		return
 	}
 
@@ -72,23 +81,23 @@ func (h *SearchHandler) SearchChargingConnectors(w http.ResponseWriter, r *http.
 	hasGeo := len(req.GeoCoordinates) == 2 && req.DistanceMeters > 0
 
 	if (hasEvse && hasGeo) || (!hasEvse && !hasGeo) {
-		httpx.WriteError(
-			w,
-			http.StatusBadRequest,
-			"BAD_REQUEST",
+		httpx.WriteAppError(w, apperror.BadRequest(
 			"Either evse_id or geo_coordinates with distance_meters is required (but not both).",
-		)
+		))
+		return
+	}
+
+	// Reject unsupported filter and sort values before calling any backend.
+	if _, err := search.NewCriteria(req, time.Now(), h.vehicles); err != nil {
+		httpx.WriteAppError(w, apperror.From(err))
 		return
 	}
 
 	resp, err := h.service.Search(r.Context(), page, perPage, req)
 	if err != nil {
-		h.logger.Error("search service failed", zap.Error(err))
-		httpx.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Server error occurred while processing the request.")
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "search service failed", err)
 		return
 	}
 
-	// Set mock transaction id header for now
-	w.Header().Set("X-Transaction-Id", "mock-transaction-id")
 	httpx.WriteJSON(w, http.StatusOK, resp)
 }
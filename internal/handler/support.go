@@ -6,20 +6,25 @@ import (
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/orders"
 	"bff-go-mvp/internal/domain/support"
 	"bff-go-mvp/internal/httpx"
 	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/transaction"
 )
 
 // SupportHandler handles GET /v1/orders/{order_id}/support.
 type SupportHandler struct {
 	service support.Service
+	access  *orders.AccessPolicy
 	logger  *zap.Logger
 }
 
-func NewSupportHandler(service support.Service, logger *zap.Logger) *SupportHandler {
+func NewSupportHandler(service support.Service, access *orders.AccessPolicy, logger *zap.Logger) *SupportHandler {
 	return &SupportHandler{
 		service: service,
+		access:  access,
 		logger:  logger,
 	}
 }
@@ -30,41 +35,44 @@ func NewSupportHandler(service support.Service, logger *zap.Logger) *SupportHand
 // @Tags Support
 // @Accept json
 // @Produce json
-// @Param X-Transaction-Id header string true "Unique transaction identifier"
+// @Param X-Transaction-Id header string false "Unique transaction identifier; generated when absent"
 // @Param X-Bpp-Id header string true "Backend provider identifier"
 // @Param order_id path string true "Order ID"
 // @Success 200 {object} model.SupportResponse
 // @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Security BearerAuth
 // @Router /v1/orders/{order_id}/support [get]
 func (h *SupportHandler) GetOrderSupport(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		httpx.WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
-	txnID := r.Header.Get("X-Transaction-Id")
 	bppID := r.Header.Get("X-Bpp-Id")
-	if txnID == "" || bppID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request parameters or missing required fields.")
+	if bppID == "" {
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request parameters or missing required fields."))
 		return
 	}
 
 	vars := mux.Vars(r)
 	orderID := vars["order_id"]
 	if orderID == "" {
-		httpx.WriteError(w, http.StatusBadRequest, "BAD_REQUEST", "Missing order_id in path.")
+		httpx.WriteAppError(w, apperror.BadRequest("Missing order_id in path."))
+		return
+	}
+	if !authorizeOrder(w, r, h.access, h.logger, orderID) {
 		return
 	}
 
 	resp, err := h.service.GetSupport(r.Context(), orderID)
 	if err != nil {
-		h.logger.Error("get support failed", zap.Error(err))
-		httpx.WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Server error occurred while processing the request.")
+		httpx.WriteServiceError(w, transaction.Logger(r.Context(), h.logger), "get support failed", err)
 		return
 	}
 
-	w.Header().Set("X-Transaction-Id", txnID)
 	w.Header().Set("X-Bpp-Id", bppID)
 	httpx.WriteJSON(w, http.StatusOK, resp)
 }
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/httpx"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/vehicles"
+)
+
+// VehiclesHandler handles GET /v1/vehicles requests.
+type VehiclesHandler struct {
+	registry *vehicles.Registry
+	logger   *zap.Logger
+}
+
+func NewVehiclesHandler(registry *vehicles.Registry, logger *zap.Logger) *VehiclesHandler {
+	return &VehiclesHandler{
+		registry: registry,
+		logger:   logger,
+	}
+}
+
+// ListVehicles handles the vehicle catalog API.
+// @Summary List vehicles
+// @Description Returns the vehicles in the registry with their usable battery capacity, supported connectors, AC and DC charge power limits and charging curve. Estimates for vehicles that are not listed use per-type defaults.
+// @Tags Vehicles
+// @Produce json
+// @Param make query string false "Only vehicles of this make (case-insensitive)"
+// @Param type query string false "Only vehicles of this type" Enums(2-wheeler, 3-wheeler, 4-wheeler)
+// @Success 200 {object} model.VehiclesResponse
+// @Failure 401 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Security BearerAuth
+// @Router /v1/vehicles [get]
+func (h *VehiclesHandler) ListVehicles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		httpx.WriteAppError(w, apperror.New(apperror.KindMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	q := r.URL.Query()
+	list := h.registry.List(q.Get("make"), q.Get("type"))
+	httpx.WriteJSON(w, http.StatusOK, model.VehiclesResponse{
+		Total:    len(list),
+		Vehicles: list,
+	})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/payment"
+	"bff-go-mvp/internal/httpx"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/transaction"
+	"bff-go-mvp/internal/webhook"
+)
+
+// maxWebhookBody bounds the webhook payloads read into memory.
+const maxWebhookBody = 1 << 20
+
+// PaymentWebhookHandler handles POST /v1/webhooks/payments/{provider}
+// requests from payment service providers.
+type PaymentWebhookHandler struct {
+	service  payment.Service
+	verifier *webhook.Verifier
+	events   *webhook.Deduper
+	logger   *zap.Logger
+}
+
+func NewPaymentWebhookHandler(service payment.Service, verifier *webhook.Verifier, events *webhook.Deduper, logger *zap.Logger) *PaymentWebhookHandler {
+	return &PaymentWebhookHandler{
+		service:  service,
+		verifier: verifier,
+		events:   events,
+		logger:   logger,
+	}
+}
+
+// HandlePaymentEvent handles a payment provider's webhook.
+// @Summary Receive a payment provider webhook
+// @Description Applies a payment event from the provider to the order's payment: payment.captured pays the order and payment.failed marks its payment FAILED so that it can be retried; refund.succeeded and refund.failed report the outcome of a refund. The request must carry an X-Webhook-Signature header of the form t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>"> made with the provider's secret from PAYMENT_WEBHOOK_SECRETS; signatures older than PAYMENT_WEBHOOK_TOLERANCE are rejected as stale. An event delivered again is acknowledged as a duplicate without changing the order. The endpoint does not take a bearer token.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Signature header string true "t=<unix seconds>,v1=<hex HMAC-SHA256 signature>"
+// @Param provider path string true "Payment provider" example(fakepsp)
+// @Param event body payment.PSPEvent true "Payment event"
+// @Success 200 {object} model.PaymentWebhookResponse
+// @Failure 400 {object} model.Error
+// @Failure 401 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /v1/webhooks/payments/{provider} [post]
+func (h *PaymentWebhookHandler) HandlePaymentEvent(w http.ResponseWriter, r *http.Request) {
+	logger := transaction.Logger(r.Context(), h.logger)
+	provider := mux.Vars(r)["provider"]
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request body."))
+		return
+	}
+	if err := h.verifier.Verify(provider, r.Header.Get(webhook.SignatureHeader), body); err != nil {
+		logger.Warn("rejected payment webhook", zap.String("provider", provider), zap.Error(err))
+		httpx.WriteAppError(w, apperror.From(err))
+		return
+	}
+
+	var event payment.PSPEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		logger.Warn("failed to decode payment webhook", zap.String("provider", provider), zap.Error(err))
+		httpx.WriteAppError(w, apperror.BadRequest("Invalid request body."))
+		return
+	}
+	if event.ID == "" {
+		httpx.WriteAppError(w, apperror.Validation("id is required").WithDetail("field", "id"))
+		return
+	}
+
+	// Providers redeliver events until acknowledged; each is applied once.
+	key := provider + ":" + event.ID
+	switch h.events.Begin(key) {
+	case webhook.Duplicate:
+		logger.Info("duplicate payment webhook", zap.String("provider", provider), zap.String("event_id", event.ID))
+		httpx.WriteJSON(w, http.StatusOK, model.PaymentWebhookResponse{EventID: event.ID, Status: "duplicate"})
+		return
+	case webhook.InProgress:
+		httpx.WriteAppError(w, apperror.Conflict("Event is already being processed.").WithDetail("event_id", event.ID))
+		return
+	}
+
+	resp, err := h.service.HandleEvent(r.Context(), provider, event)
+	h.events.Done(key, err == nil)
+	if err != nil {
+		httpx.WriteServiceError(w, logger, "payment event failed", err)
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,151 @@
+// Package health serves the liveness and readiness probes. Readiness runs
+// the checkers registered by each backend and fails while the server is
+// shutting down, so load balancers stop routing to it.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"bff-go-mvp/internal/httpx"
+)
+
+// Status values reported by the probes.
+const (
+	StatusOK           = "ok"
+	StatusFail         = "fail"
+	StatusUnavailable  = "unavailable"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Checker reports whether a dependency is usable.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function into a Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+type check struct {
+	name    string
+	checker Checker
+	timeout time.Duration
+}
+
+// Health holds the registered readiness checks.
+type Health struct {
+	mu       sync.RWMutex
+	checks   []check
+	draining atomic.Bool
+}
+
+func New() *Health {
+	return &Health{}
+}
+
+// Register adds a readiness check. Each run of the check is cancelled after
+// timeout.
+func (h *Health) Register(name string, timeout time.Duration, c Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, check{name: name, checker: c, timeout: timeout})
+}
+
+// Drain makes readiness fail from now on. It is called when shutdown begins.
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+// CheckResult is the outcome of one readiness check.
+type CheckResult struct {
+	Status     string `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Report is the readiness response body.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Ready runs every check concurrently and reports the combined status.
+func (h *Health) Ready(ctx context.Context) Report {
+	h.mu.RLock()
+	checks := append([]check(nil), h.checks...)
+	h.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			results[i] = run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checks))}
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+	if h.draining.Load() {
+		report.Status = StatusShuttingDown
+	}
+	return report
+}
+
+func run(ctx context.Context, c check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.checker.Check(ctx)
+	result := CheckResult{Status: StatusOK, DurationMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Names returns the registered check names in sorted order.
+func (h *Health) Names() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	names := make([]string, 0, len(h.checks))
+	for _, c := range h.checks {
+		names = append(names, c.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LiveHandler handles GET /livez. It only reports that the process is
+// serving.
+func (h *Health) LiveHandler(w http.ResponseWriter, _ *http.Request) {
+	httpx.WriteJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+}
+
+// ReadyHandler handles GET /readyz with 200 when every check passes and 503
+// otherwise, listing each component's status.
+func (h *Health) ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	report := h.Ready(r.Context())
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	httpx.WriteJSON(w, status, report)
+}
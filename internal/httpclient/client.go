@@ -0,0 +1,146 @@
+// Package httpclient is a small JSON client for downstream REST backends
+// that expose the same contract as the BFF (swagger.yaml).
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/httpx"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/transaction"
+)
+
+// Client sends JSON requests to a downstream base URL.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// New creates a client for baseURL with the given per-request timeout.
+func New(baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Error describes a non-2xx downstream response. Code and Message are taken
+// from the standard error body when present. Do returns it wrapped in an
+// *apperror.Error so the status is passed on to the client.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Details map[string]interface{}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("downstream returned %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// Do sends a request with an optional JSON body and decodes a JSON response
+// into out (when non-nil).
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request body: %w", err)
+		}
+		reader = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if id := transaction.FromContext(ctx); id != "" {
+		req.Header.Set(transaction.Header, id)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response from %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// Check reports whether the backend is reachable: any response below 500
+// from the base URL counts as up. It is used as the readiness check of the
+// HTTP backend.
+func (c *Client) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/", nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("backend returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func decodeError(resp *http.Response) error {
+	e := &Error{Status: resp.StatusCode, Code: "UPSTREAM_ERROR", Message: http.StatusText(resp.StatusCode)}
+
+	var body model.Error
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && body.Error.Code != "" {
+		e.Code = body.Error.Code
+		e.Message = body.Error.Message
+		e.Details = body.Error.Details
+	}
+
+	// Downstream failures are not described to clients; client errors are
+	// passed through with their code, message and details.
+	kind := httpx.KindForStatus(resp.StatusCode)
+	if kind == apperror.KindInternal {
+		return apperror.Internal(e)
+	}
+	appErr := apperror.Wrap(kind, e.Message, e)
+	if e.Code != "UPSTREAM_ERROR" {
+		appErr.Code = e.Code
+	}
+	for k, v := range e.Details {
+		appErr.WithDetail(k, v)
+	}
+	return appErr
+}
@@ -0,0 +1,76 @@
+package httpx
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+)
+
+// statusByKind maps domain error kinds to HTTP status codes.
+var statusByKind = map[apperror.Kind]int{
+	apperror.KindBadRequest:       http.StatusBadRequest,
+	apperror.KindUnauthorized:     http.StatusUnauthorized,
+	apperror.KindForbidden:        http.StatusForbidden,
+	apperror.KindNotFound:         http.StatusNotFound,
+	apperror.KindMethodNotAllowed: http.StatusMethodNotAllowed,
+	apperror.KindConflict:         http.StatusConflict,
+	apperror.KindValidation:       http.StatusUnprocessableEntity,
+	apperror.KindUnavailable:      http.StatusServiceUnavailable,
+	apperror.KindInternal:         http.StatusInternalServerError,
+}
+
+// StatusFor returns the HTTP status for a domain error kind.
+func StatusFor(kind apperror.Kind) int {
+	if status, ok := statusByKind[kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// KindForStatus maps an HTTP status returned by a downstream service back to
+// a domain error kind. Unknown 4xx statuses are treated as bad requests and
+// everything else as internal.
+func KindForStatus(status int) apperror.Kind {
+	for kind, s := range statusByKind {
+		if s == status {
+			return kind
+		}
+	}
+	if status >= 400 && status < 500 {
+		return apperror.KindBadRequest
+	}
+	return apperror.KindInternal
+}
+
+// WriteAppError writes e as the standard Error body with its mapped status.
+// A timestamp is added to the details of every error response.
+func WriteAppError(w http.ResponseWriter, e *apperror.Error) {
+	details := make(map[string]interface{}, len(e.Details)+1)
+	for k, v := range e.Details {
+		details[k] = v
+	}
+	details["timestamp"] = time.Now().UTC().Format(time.RFC3339)
+
+	WriteJSON(w, StatusFor(e.Kind), model.Error{
+		Error: model.ErrorBody{
+			Code:    e.Code,
+			Message: e.Message,
+			Details: details,
+		},
+	})
+}
+
+// WriteServiceError writes the response for an error returned by a domain
+// service. Errors that are not *apperror.Error become 500 INTERNAL_ERROR,
+// and every 5xx is logged with msg.
+func WriteServiceError(w http.ResponseWriter, logger *zap.Logger, msg string, err error) {
+	e := apperror.From(err)
+	if StatusFor(e.Kind) >= http.StatusInternalServerError {
+		logger.Error(msg, zap.Error(err))
+	}
+	WriteAppError(w, e)
+}
@@ -0,0 +1,69 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/money"
+)
+
+// MoneyFormatHeader selects the money format of a response; see
+// money.FormatDecimal and money.FormatLegacy.
+const MoneyFormatHeader = "X-Money-Format"
+
+type moneyFormatKey struct{}
+
+// MoneyFormatMiddleware records the money format of each request: the
+// X-Money-Format header when present, else defaultFormat. The format is
+// echoed in the response header. Unknown formats get 400. An empty
+// defaultFormat is money.FormatDecimal.
+func MoneyFormatMiddleware(defaultFormat string) func(http.Handler) http.Handler {
+	if defaultFormat == "" {
+		defaultFormat = money.FormatDecimal
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			format := defaultFormat
+			if v := strings.ToLower(strings.TrimSpace(r.Header.Get(MoneyFormatHeader))); v != "" {
+				if v != money.FormatDecimal && v != money.FormatLegacy {
+					WriteAppError(w, apperror.BadRequest(MoneyFormatHeader+" must be "+money.FormatDecimal+" or "+money.FormatLegacy).
+						WithDetail("header", MoneyFormatHeader))
+					return
+				}
+				format = v
+			}
+			w.Header().Set(MoneyFormatHeader, format)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), moneyFormatKey{}, format)))
+		})
+	}
+}
+
+// MoneyFormat returns the money format of the request, money.FormatDecimal
+// when no middleware recorded one.
+func MoneyFormat(ctx context.Context) string {
+	if f, ok := ctx.Value(moneyFormatKey{}).(string); ok {
+		return f
+	}
+	return money.FormatDecimal
+}
+
+// WriteMoneyJSON writes v like WriteJSON, except that in the legacy money
+// format its money values are JSON numbers. It is for the responses that
+// used numbers before decimal money; the others always used strings, which
+// the decimal format keeps.
+func WriteMoneyJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	if MoneyFormat(r.Context()) != money.FormatLegacy {
+		WriteJSON(w, status, v)
+		return
+	}
+	data, err := money.AsNumbers(v)
+	if err != nil {
+		WriteAppError(w, apperror.Internal(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(data, '\n'))
+}
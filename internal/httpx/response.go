@@ -15,6 +15,7 @@ func WriteJSON(w http.ResponseWriter, status int, v interface{}) {
 }
 
 // WriteError writes an error response matching the swagger Error schema.
+// Handlers should prefer WriteAppError and WriteServiceError.
 func WriteError(w http.ResponseWriter, status int, code, message string) {
 	errBody := model.Error{
 		Error: model.ErrorBody{
@@ -24,5 +25,3 @@ func WriteError(w http.ResponseWriter, status int, code, message string) {
 	}
 	WriteJSON(w, status, errBody)
 }
-
-
@@ -0,0 +1,195 @@
+// Package metrics exposes Prometheus metrics for HTTP requests, domain
+// backend calls and outbound gRPC calls.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"bff-go-mvp/internal/apperror"
+)
+
+const namespace = "bff"
+
+// unmatchedRoute labels requests that did not match any route, so unknown
+// paths cannot blow up label cardinality.
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels requests with a non-standard method, for the same
+// reason.
+const otherMethod = "OTHER"
+
+// Metrics holds the collectors of one registry. Each router gets its own
+// so that tests can build routers repeatedly.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInFlight *prometheus.GaugeVec
+
+	backendCalls    *prometheus.CounterVec
+	backendDuration *prometheus.HistogramVec
+
+	grpcCalls    *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+}
+
+// New creates the collectors and registers them, along with the Go runtime
+// and process collectors, on a fresh registry.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by route template, method and status.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by route template, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		httpInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests currently being served, by route template and method.",
+		}, []string{"route", "method"}),
+		backendCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "backend_calls_total",
+			Help:      "Domain service calls, by domain, backend mode, operation and outcome.",
+		}, []string{"domain", "backend", "operation", "outcome"}),
+		backendDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "backend_call_duration_seconds",
+			Help:      "Domain service call latency, by domain, backend mode and operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"domain", "backend", "operation"}),
+		grpcCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_client_calls_total",
+			Help:      "Outbound gRPC calls, by full method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_client_call_duration_seconds",
+			Help:      "Outbound gRPC call latency, by full method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration, m.httpInFlight,
+		m.backendCalls, m.backendDuration,
+		m.grpcCalls, m.grpcDuration,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware records request count, latency and in-flight requests. Requests
+// are labelled with the mux route template (e.g. /v1/orders/{order_id}),
+// never the raw path.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, method := routeTemplate(r), methodLabel(r.Method)
+		inFlight := m.httpInFlight.WithLabelValues(route, method)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+
+		code := strconv.Itoa(sw.status)
+		m.httpRequests.WithLabelValues(route, method, code).Inc()
+		m.httpDuration.WithLabelValues(route, method, code).Observe(time.Since(start).Seconds())
+	})
+}
+
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return unmatchedRoute
+}
+
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodConnect,
+		http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return otherMethod
+}
+
+// Backend records the calls of one domain service.
+type Backend struct {
+	metrics *Metrics
+	domain  string
+	backend string
+}
+
+// Backend returns the recorder for a domain wired with the given backend
+// mode (mock, http or grpc).
+func (m *Metrics) Backend(domain, backend string) *Backend {
+	return &Backend{metrics: m, domain: domain, backend: backend}
+}
+
+// Observe records one call of operation that started at start. The outcome
+// is "ok", or the apperror kind of err (internal for untyped errors).
+func (b *Backend) Observe(operation string, start time.Time, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = string(apperror.From(err).Kind)
+	}
+	b.metrics.backendCalls.WithLabelValues(b.domain, b.backend, operation, outcome).Inc()
+	b.metrics.backendDuration.WithLabelValues(b.domain, b.backend, operation).Observe(time.Since(start).Seconds())
+}
+
+// UnaryClientInterceptor records outbound unary gRPC calls.
+func (m *Metrics) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.grpcCalls.WithLabelValues(method, status.Code(err).String()).Inc()
+		m.grpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// statusWriter captures the status code written by a handler.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -1,5 +1,7 @@
 package model
 
+import "bff-go-mvp/internal/money"
+
 // This file defines request/response models for the EV Charging HTTP APIs
 // described in swagger.yaml. The struct and field names closely follow the
 // OpenAPI component schemas while keeping idiomatic Go naming.
@@ -70,8 +72,23 @@ type BuyerFinderFee struct {
 	FeeValue float64 `json:"feeValue"`
 }
 
+// IdleFee is charged for occupying a connector after charging completes:
+// Rate per Unit (MIN or HUR) for the time beyond GracePeriodMinutes, up to
+// Cap when it is positive. Rate and Cap are in Currency.
+type IdleFee struct {
+	Rate               float64 `json:"rate" example:"2"`
+	Currency           string  `json:"currency" example:"INR"`
+	Unit               string  `json:"unit" enums:"MIN,HUR" example:"MIN"`
+	GracePeriodMinutes int     `json:"gracePeriodMinutes" example:"10"`
+	Cap                float64 `json:"cap,omitempty" example:"200"`
+}
+
+// OfferAttributes carry the offer's fees. IdleFeePolicy is the legacy,
+// human-readable form of IdleFee ("₹2/min after 10 min post-charge"); when
+// both are present IdleFee wins.
 type OfferAttributes struct {
 	BuyerFinderFee *BuyerFinderFee `json:"buyerFinderFee,omitempty"`
+	IdleFee        *IdleFee        `json:"idleFee,omitempty"`
 	IdleFeePolicy  string          `json:"idleFeePolicy,omitempty"`
 }
 
@@ -81,6 +98,9 @@ type ApplicableQuantity struct {
 	UnitQuantity float64 `json:"unitQuantity"`
 }
 
+// Price is a tariff: Value is the rate per applicable quantity, which may
+// have more decimals than the currency. Amounts charged are Amount and
+// PriceComponent, which are exact decimals.
 type Price struct {
 	Currency           string              `json:"currency"`
 	Value              float64             `json:"value"`
@@ -116,6 +136,15 @@ type Catalog struct {
 	AvailablePowerType []string             `json:"availablePowerType"`
 	Connectors         []Connector          `json:"connectors"`
 	Offers             []Offer              `json:"offers"`
+	// DistanceMeters is the distance from the searched coordinates, when the
+	// search was by location.
+	DistanceMeters *float64 `json:"distanceMeters,omitempty"`
+	// Timezone is the IANA time zone of AvailabilityWindow; Asia/Kolkata when
+	// empty.
+	Timezone string `json:"timezone,omitempty"`
+	// NextOpening is when a station that is closed at search time opens next,
+	// in RFC 3339 with the station's UTC offset.
+	NextOpening string `json:"nextOpening,omitempty"`
 }
 
 // --- Search API models ---
@@ -123,20 +152,24 @@ type Catalog struct {
 type Vehicle struct {
 	Make  string `json:"make,omitempty"`
 	Model string `json:"model,omitempty"`
-	Type  string `json:"type,omitempty"`
+	Type  string `json:"type,omitempty" enums:"2-wheeler,3-wheeler,4-wheeler"`
 }
 
+// SearchFilters narrows search results. Connector filters keep only the
+// matching connectors: connector_type and vehicle compatibility (from the
+// vehicle registry) by connector type, max_power_kw as a minimum, and
+// amenities as all-of.
 type SearchFilters struct {
 	CPO           string   `json:"cpo,omitempty"`
-	ConnectorType string   `json:"connector_type,omitempty"`
+	ConnectorType string   `json:"connector_type,omitempty" enums:"TYPE_1,TYPE_2,CCS1,CCS2,CHADEMO,GBT,BHARAT_AC_001,BHARAT_DC_001"`
 	MaxPowerKW    float64  `json:"max_power_kw,omitempty"`
 	Amenities     []string `json:"amenities,omitempty"`
 	Vehicle       *Vehicle `json:"vehicle,omitempty"`
 }
 
 type SearchSort struct {
-	SortBy string `json:"sort_by,omitempty"`
-	Order  string `json:"order,omitempty"`
+	SortBy string `json:"sort_by,omitempty" enums:"distance,price,rating,power"`
+	Order  string `json:"order,omitempty" enums:"asc,desc"`
 }
 
 type TimeWindow struct {
@@ -151,6 +184,9 @@ type SearchRequest struct {
 	TimeWindow     *TimeWindow    `json:"time_window,omitempty"`
 	Filters        *SearchFilters `json:"filters,omitempty"`
 	Sort           *SearchSort    `json:"sort,omitempty"`
+	// OpenNow keeps only stations open at search time. It cannot be combined
+	// with TimeWindow.
+	OpenNow bool `json:"open_now,omitempty"`
 }
 
 type SearchResponse struct {
@@ -167,9 +203,11 @@ type Energy struct {
 	Unit  string  `json:"unit"`
 }
 
+// Amount is a sum of money. Value has the decimals of the currency's minor
+// unit and is a JSON string, e.g. "128.64"; requests may also send a number.
 type Amount struct {
-	Value    float64 `json:"value"`
-	Currency string  `json:"currency"`
+	Value    money.Decimal `json:"value" swaggertype:"string" example:"128.64"`
+	Currency string        `json:"currency"`
 }
 
 type EstimateRequest struct {
@@ -180,6 +218,9 @@ type EstimateRequest struct {
 	Energy      *Energy     `json:"energy,omitempty"`
 	OfferID     string      `json:"offer_id,omitempty"`
 	Amount      *Amount     `json:"amount,omitempty"`
+	// StateOfCharge is the battery level in percent when charging starts;
+	// 20 when absent.
+	StateOfCharge *float64 `json:"state_of_charge,omitempty"`
 }
 
 type OrderInfo struct {
@@ -205,16 +246,46 @@ type ExternalRef struct {
 	URL      string `json:"url"`
 }
 
+// CancellationPolicy publishes the cancellation terms of an order.
+// Cancelling at least FreeBeforeMinutes before the reservation starts is
+// free; later, the tier with the shortest WithinMinutes that covers the time
+// left applies, with MinimumFee as a floor. Nothing is refunded once charging
+// has started, and cancel codes in FeeWaivedFor cancel for free. Fee is the
+// highest tier percentage.
 type CancellationPolicy struct {
-	Fee         *CancellationFee `json:"fee,omitempty"`
-	ExternalRef *ExternalRef     `json:"externalRef,omitempty"`
+	Fee               *CancellationFee   `json:"fee,omitempty"`
+	FreeBeforeMinutes int                `json:"freeBeforeMinutes,omitempty"`
+	Tiers             []CancellationTier `json:"tiers,omitempty"`
+	MinimumFee        *Amount            `json:"minimumFee,omitempty"`
+	FeeWaivedFor      []string           `json:"feeWaivedFor,omitempty"`
+	ExternalRef       *ExternalRef       `json:"externalRef,omitempty"`
 }
 
+// CancellationTier charges Percentage of the amount paid for cancelling
+// WithinMinutes or less before the reservation starts; 0 means after the
+// start.
+type CancellationTier struct {
+	WithinMinutes int    `json:"withinMinutes"`
+	Percentage    string `json:"percentage"`
+}
+
+// PriceComponent is one line of a price breakdown. Like Amount, Value is a
+// decimal string. Charges are positive; discounts and payments received are
+// negative; refunds, which return part of a payment, are positive.
 type PriceComponent struct {
-	Type        string  `json:"type"`
-	Value       float64 `json:"value"`
-	Currency    string  `json:"currency"`
-	Description string  `json:"description"`
+	Type        string        `json:"type" enums:"BASE,UNIT,SURCHARGE,DISCOUNT,FEE,TAX,IDLE_FEE,PAID,REFUND"`
+	Value       money.Decimal `json:"value" swaggertype:"string" example:"128.64"`
+	Currency    string        `json:"currency"`
+	Description string        `json:"description"`
+}
+
+// PriceBreakdown sums the price components of a response. Subtotal is the
+// charges less discounts; Total is the subtotal less payments plus refunds,
+// so a positive total is due from the buyer and a negative one is owed to
+// them.
+type PriceBreakdown struct {
+	Subtotal Amount `json:"subtotal"`
+	Total    Amount `json:"total"`
 }
 
 type EstimateResponse struct {
@@ -225,9 +296,37 @@ type EstimateResponse struct {
 	Energy                     *Energy             `json:"energy,omitempty"`
 	Validity                   *Validity           `json:"validity,omitempty"`
 	PriceComponents            []PriceComponent    `json:"priceComponents,omitempty"`
+	Breakdown                  *PriceBreakdown     `json:"breakdown,omitempty"`
 	Cancellation               *CancellationPolicy `json:"cancellation,omitempty"`
 }
 
+// --- Vehicle API models ---
+
+// ChargingCurvePoint is the most power in kW a battery accepts at a state of
+// charge in percent. Power between points is interpolated linearly.
+type ChargingCurvePoint struct {
+	SoC     float64 `json:"soc"`
+	PowerKW float64 `json:"powerKW"`
+}
+
+// VehicleSpec describes how a vehicle charges.
+type VehicleSpec struct {
+	ID               string               `json:"id"`
+	Make             string               `json:"make,omitempty"`
+	Model            string               `json:"model,omitempty"`
+	Type             string               `json:"type" enums:"2-wheeler,3-wheeler,4-wheeler"`
+	UsableBatteryKWh float64              `json:"usableBatteryKWh"`
+	Connectors       []string             `json:"connectors"`
+	MaxACPowerKW     float64              `json:"maxACPowerKW"`
+	MaxDCPowerKW     float64              `json:"maxDCPowerKW"`
+	ChargingCurve    []ChargingCurvePoint `json:"chargingCurve"`
+}
+
+type VehiclesResponse struct {
+	Total    int           `json:"total"`
+	Vehicles []VehicleSpec `json:"vehicles"`
+}
+
 // --- Order and charging models ---
 
 type ChargingMetric struct {
@@ -250,35 +349,27 @@ type OrderResponse struct {
 	Vehicle           *Vehicle           `json:"vehicle,omitempty"`
 	TrackingURL       string             `json:"trackingUrl,omitempty"`
 	ChargingTelemetry *ChargingTelemetry `json:"chargingTelemetry,omitempty"`
-}
-
-type PriceComponentString struct {
-	Type        string `json:"type"`
-	Value       string `json:"value"`
-	Currency    string `json:"currency"`
-	Description string `json:"description"`
-}
-
-type PriceComponentFlexible struct {
-	Type        string      `json:"type"`
-	Value       interface{} `json:"value"`
-	Currency    string      `json:"currency"`
-	Description string      `json:"description"`
+	// PriceComponents and Breakdown are the session's bill once charging
+	// has stopped, including the idle fee accrued so far.
+	PriceComponents []PriceComponent `json:"priceComponents,omitempty"`
+	Breakdown       *PriceBreakdown  `json:"breakdown,omitempty"`
 }
 
 type CancelEstimateResponse struct {
-	Order           OrderInfo              `json:"order"`
-	Payment         *PaymentInfo           `json:"payment,omitempty"`
-	Charging        *ChargingInfo          `json:"charging,omitempty"`
-	Validity        *Validity              `json:"validity,omitempty"`
-	PriceComponents []PriceComponentString `json:"priceComponents,omitempty"`
+	Order           OrderInfo        `json:"order"`
+	Payment         *PaymentInfo     `json:"payment,omitempty"`
+	Charging        *ChargingInfo    `json:"charging,omitempty"`
+	Validity        *Validity        `json:"validity,omitempty"`
+	PriceComponents []PriceComponent `json:"priceComponents,omitempty"`
+	Breakdown       *PriceBreakdown  `json:"breakdown,omitempty"`
 }
 
 type CancelResponse struct {
-	Order           OrderInfo              `json:"order"`
-	Payment         *PaymentInfo           `json:"payment,omitempty"`
-	Charging        *ChargingInfo          `json:"charging,omitempty"`
-	PriceComponents []PriceComponentString `json:"priceComponents,omitempty"`
+	Order           OrderInfo        `json:"order"`
+	Payment         *PaymentInfo     `json:"payment,omitempty"`
+	Charging        *ChargingInfo    `json:"charging,omitempty"`
+	PriceComponents []PriceComponent `json:"priceComponents,omitempty"`
+	Breakdown       *PriceBreakdown  `json:"breakdown,omitempty"`
 }
 
 type StartChargingRequest struct{}
@@ -290,11 +381,12 @@ type StartChargingResponse struct {
 }
 
 type StopEstimateResponse struct {
-	Order           OrderInfo              `json:"order"`
-	Payment         *PaymentInfo           `json:"payment,omitempty"`
-	Charging        *ChargingInfo          `json:"charging,omitempty"`
-	Validity        *Validity              `json:"validity,omitempty"`
-	PriceComponents []PriceComponentString `json:"priceComponents,omitempty"`
+	Order           OrderInfo        `json:"order"`
+	Payment         *PaymentInfo     `json:"payment,omitempty"`
+	Charging        *ChargingInfo    `json:"charging,omitempty"`
+	Validity        *Validity        `json:"validity,omitempty"`
+	PriceComponents []PriceComponent `json:"priceComponents,omitempty"`
+	Breakdown       *PriceBreakdown  `json:"breakdown,omitempty"`
 }
 
 type StopChargingRequest struct {
@@ -303,11 +395,20 @@ type StopChargingRequest struct {
 }
 
 type StopChargingResponse struct {
-	Order           OrderInfo                `json:"order"`
-	Payment         *PaymentInfo             `json:"payment,omitempty"`
-	Charging        *ChargingInfo            `json:"charging,omitempty"`
-	Validity        *Validity                `json:"validity,omitempty"`
-	PriceComponents []PriceComponentFlexible `json:"priceComponents,omitempty"`
+	Order           OrderInfo        `json:"order"`
+	Payment         *PaymentInfo     `json:"payment,omitempty"`
+	Charging        *ChargingInfo    `json:"charging,omitempty"`
+	Validity        *Validity        `json:"validity,omitempty"`
+	PriceComponents []PriceComponent `json:"priceComponents,omitempty"`
+	Breakdown       *PriceBreakdown  `json:"breakdown,omitempty"`
+}
+
+type UnplugResponse struct {
+	Order           OrderInfo        `json:"order"`
+	Payment         *PaymentInfo     `json:"payment,omitempty"`
+	Charging        *ChargingInfo    `json:"charging,omitempty"`
+	PriceComponents []PriceComponent `json:"priceComponents,omitempty"`
+	Breakdown       *PriceBreakdown  `json:"breakdown,omitempty"`
 }
 
 // --- Feedback and support models ---
@@ -345,11 +446,70 @@ type SupportResponse struct {
 
 // --- Payments ---
 
+// PaymentRequest initiates payment of an order's quoted amount. Method
+// defaults to the first method the order accepts; VPA is required for
+// UPI_COLLECT.
+type PaymentRequest struct {
+	Method    string `json:"method,omitempty" enums:"UPI_INTENT,UPI_COLLECT,CARD,WALLET" example:"UPI_INTENT"`
+	VPA       string `json:"vpa,omitempty" example:"driver@okaxis"`
+	Wallet    string `json:"wallet,omitempty" example:"PAYTM"`
+	ReturnURL string `json:"returnUrl,omitempty" example:"https://app.bluechargenet.in/orders/ord-1"`
+}
+
+// PaymentAttempt is one attempt to collect an order's payment. Reference
+// is the payment service provider's payment ID.
+type PaymentAttempt struct {
+	ID        string `json:"id"`
+	Method    string `json:"method" enums:"UPI_INTENT,UPI_COLLECT,CARD,WALLET"`
+	Status    string `json:"status" enums:"PENDING,PAID,FAILED,VOIDED"`
+	Reference string `json:"reference,omitempty"`
+}
+
 type PaymentResponse struct {
-	Order                 OrderInfo `json:"order"`
-	Amount                Amount    `json:"amount"`
-	BeneficiaryID         string    `json:"beneficiaryId"`
-	AcceptedPaymentMethod []string  `json:"acceptedPaymentMethod"`
-	PaymentURL            string    `json:"paymentUrl"`
-	Validity              *Validity `json:"validity,omitempty"`
+	Order                 OrderInfo       `json:"order"`
+	Payment               *PaymentInfo    `json:"payment,omitempty"`
+	Amount                Amount          `json:"amount"`
+	BeneficiaryID         string          `json:"beneficiaryId"`
+	AcceptedPaymentMethod []string        `json:"acceptedPaymentMethod"`
+	PaymentURL            string          `json:"paymentUrl"`
+	Attempt               *PaymentAttempt `json:"attempt,omitempty"`
+	Validity              *Validity       `json:"validity,omitempty"`
+}
+
+// PaymentWebhookResponse acknowledges a payment provider webhook. Status is
+// "processed" the first time an event is received and "duplicate" when it
+// is delivered again; a duplicate does not change the order.
+type PaymentWebhookResponse struct {
+	EventID string          `json:"eventId"`
+	Status  string          `json:"status" enums:"processed,duplicate"`
+	Order   *OrderInfo      `json:"order,omitempty"`
+	Payment *PaymentInfo    `json:"payment,omitempty"`
+	Attempt *PaymentAttempt `json:"attempt,omitempty"`
+	Refund  *Refund         `json:"refund,omitempty"`
+}
+
+// Refund returns money from an order's payment to the buyer. Failed
+// attempts are retried at nextAttemptAt; a FAILED refund ran out of
+// attempts. Times are RFC 3339.
+type Refund struct {
+	ID               string `json:"id"`
+	Status           string `json:"status" enums:"PENDING,PROCESSING,SUCCEEDED,FAILED"`
+	Amount           Amount `json:"amount"`
+	Reason           string `json:"reason" enums:"CANCELLATION,UNUSED_PREPAID,UNAPPLIED_PAYMENT"`
+	PaymentAttemptID string `json:"paymentAttemptId,omitempty"`
+	Reference        string `json:"reference,omitempty"`
+	Attempts         int    `json:"attempts"`
+	LastError        string `json:"lastError,omitempty"`
+	NextAttemptAt    string `json:"nextAttemptAt,omitempty"`
+	CreatedAt        string `json:"createdAt"`
+	UpdatedAt        string `json:"updatedAt"`
+}
+
+// RefundsResponse lists an order's refunds. Refunded is the sum of the
+// refunds that succeeded.
+type RefundsResponse struct {
+	Order    OrderInfo    `json:"order"`
+	Payment  *PaymentInfo `json:"payment,omitempty"`
+	Refunds  []Refund     `json:"refunds"`
+	Refunded Amount       `json:"refunded"`
 }
@@ -0,0 +1,34 @@
+package money
+
+import "strings"
+
+// minorUnits is the number of decimal places of each currency (ISO 4217).
+// Currencies not listed use two.
+var minorUnits = map[string]int{
+	"BHD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"VND": 0,
+}
+
+// MinorUnits returns the number of decimal places amounts in currency are
+// rounded to.
+func MinorUnits(currency string) int {
+	if n, ok := minorUnits[strings.ToUpper(currency)]; ok {
+		return n
+	}
+	return 2
+}
+
+// Of converts a computed value to an amount of currency, rounded half away
+// from zero to its minor unit (12.345 INR is 12.35, -12.345 INR is -12.35).
+func Of(value float64, currency string) Decimal {
+	return FromFloat(value, MinorUnits(currency))
+}
+
+// In returns d rounded to the minor unit of currency.
+func In(d Decimal, currency string) Decimal {
+	return d.Round(MinorUnits(currency))
+}
@@ -0,0 +1,272 @@
+// Package money holds exact decimal amounts for prices, fees and payments.
+//
+// A Decimal is an integer number of units at a fixed scale, so 128.64 INR is
+// 12864 at scale 2 and sums of amounts never pick up binary floating point
+// error. Amounts are rounded to their currency's minor unit when they are
+// created from computed values (see Of).
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Decimal is units × 10^-scale. The zero value is 0.
+type Decimal struct {
+	units int64
+	scale int32
+}
+
+// Bounds of parsed amounts: at most maxIntDigits integer digits and
+// MaxScale decimals, so that an amount, and sums of a few of them, fit in
+// int64 at MaxScale.
+const (
+	MaxScale     = 9
+	maxIntDigits = 9
+)
+
+// ErrOutOfRange reports an amount beyond the bounds of a Decimal. Parse
+// returns it for amounts with too many digits; arithmetic that would
+// overflow panics with it.
+var ErrOutOfRange = errors.New("amount out of range")
+
+// New returns units × 10^-scale, e.g. New(12864, 2) is 128.64.
+func New(units int64, scale int) Decimal {
+	return Decimal{units: units, scale: int32(scale)}
+}
+
+// Parse reads a decimal such as "128.64", "-0.5" or "30", with at most 9
+// integer digits and 9 decimals. Exponents are accepted for JSON numbers
+// and rounded to 9 decimal places.
+func Parse(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+		if err != nil || math.Abs(f) >= 1e9 {
+			return Decimal{}, fmt.Errorf("decimal %q: %w", s, ErrOutOfRange)
+		}
+		return FromFloat(f, MaxScale).trim(), nil
+	}
+
+	neg, digits := false, s
+	if digits != "" && (digits[0] == '-' || digits[0] == '+') {
+		neg, digits = digits[0] == '-', digits[1:]
+	}
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" && frac == "" || !allDigits(whole) || !allDigits(frac) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if len(strings.TrimLeft(whole, "0")) > maxIntDigits || len(frac) > MaxScale {
+		return Decimal{}, fmt.Errorf("decimal %q: %w", s, ErrOutOfRange)
+	}
+	units, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if neg {
+		units = -units
+	}
+	return Decimal{units: units, scale: int32(len(frac))}, nil
+}
+
+func allDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FromFloat converts f to a decimal at scale, rounding half away from zero.
+// It rounds the shortest decimal form of f, so 2.675 becomes 2.68 even
+// though the nearest float64 is slightly below 2.675. It panics with
+// ErrOutOfRange when f has more than 9 integer digits or scale is above
+// MaxScale.
+func FromFloat(f float64, scale int) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{scale: int32(scale)}
+	}
+	whole, frac, _ := strings.Cut(strconv.FormatFloat(math.Abs(f), 'f', -1, 64), ".")
+	if len(strings.TrimLeft(whole, "0")) > maxIntDigits || scale < 0 || scale > MaxScale {
+		panic(fmt.Errorf("money: %v at scale %d: %w", f, scale, ErrOutOfRange))
+	}
+	frac += strings.Repeat("0", scale+1)
+	units, err := strconv.ParseInt(whole+frac[:scale], 10, 64)
+	if err != nil {
+		panic(fmt.Errorf("money: %v: %w", f, ErrOutOfRange))
+	}
+	if frac[scale] >= '5' {
+		units++
+	}
+	if f < 0 {
+		units = -units
+	}
+	return Decimal{units: units, scale: int32(scale)}
+}
+
+// Round returns d at scale, rounding half away from zero. It panics with
+// ErrOutOfRange when d does not fit at scale.
+func (d Decimal) Round(scale int) Decimal {
+	s := int32(scale)
+	switch {
+	case s == d.scale:
+		return d
+	case s > d.scale:
+		p := pow10(s - d.scale)
+		if abs(d.units) > math.MaxInt64/p {
+			panic(fmt.Errorf("money: %s at scale %d: %w", d, scale, ErrOutOfRange))
+		}
+		return Decimal{units: d.units * p, scale: s}
+	}
+	p := pow10(d.scale - s)
+	q, r := d.units/p, d.units%p
+	if abs(r) >= p-abs(r) {
+		if d.units < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Decimal{units: q, scale: s}
+}
+
+// trim drops trailing fractional zeros.
+func (d Decimal) trim() Decimal {
+	for d.scale > 0 && d.units%10 == 0 {
+		d.units /= 10
+		d.scale--
+	}
+	return d
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int { return int(d.scale) }
+
+// Add returns d + o at the larger of the two scales. It panics with
+// ErrOutOfRange when the sum overflows.
+func (d Decimal) Add(o Decimal) Decimal {
+	a, b := align(d, o)
+	if (b.units > 0 && a.units > math.MaxInt64-b.units) || (b.units < 0 && a.units < math.MinInt64-b.units) {
+		panic(fmt.Errorf("money: %s + %s: %w", d, o, ErrOutOfRange))
+	}
+	return Decimal{units: a.units + b.units, scale: a.scale}
+}
+
+// Sub returns d - o at the larger of the two scales.
+func (d Decimal) Sub(o Decimal) Decimal {
+	return d.Add(o.Neg())
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{units: -d.units, scale: d.scale}
+}
+
+// Cmp returns -1, 0 or +1 as d is less than, equal to or greater than o.
+func (d Decimal) Cmp(o Decimal) int {
+	a, b := align(d, o)
+	switch {
+	case a.units < b.units:
+		return -1
+	case a.units > b.units:
+		return 1
+	}
+	return 0
+}
+
+// Sign returns -1, 0 or +1.
+func (d Decimal) Sign() int {
+	switch {
+	case d.units < 0:
+		return -1
+	case d.units > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool { return d.units == 0 }
+
+// Float64 returns d as the nearest float64, for computations such as
+// percentages whose results are rounded back with FromFloat.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d with exactly its scale of decimals, e.g. "30.00".
+func (d Decimal) String() string {
+	s := strconv.FormatInt(abs(d.units), 10)
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(s); pad > 0 {
+			s = strings.Repeat("0", pad) + s
+		}
+		s = s[:len(s)-int(d.scale)] + "." + s[len(s)-int(d.scale):]
+	}
+	if d.units < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// MarshalJSON encodes d as a string with exactly its scale of decimals, so
+// clients never parse money into binary floating point.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON accepts a JSON string or number; null leaves d unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+func align(a, b Decimal) (Decimal, Decimal) {
+	if a.scale < b.scale {
+		return a.Round(int(b.scale)), b
+	}
+	return a, b.Round(int(a.scale))
+}
+
+// pow10 returns 10^n; it panics with ErrOutOfRange beyond int64.
+func pow10(n int32) int64 {
+	if n > 18 {
+		panic(fmt.Errorf("money: 10^%d: %w", n, ErrOutOfRange))
+	}
+	p := int64(1)
+	for ; n > 0; n-- {
+		p *= 10
+	}
+	return p
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
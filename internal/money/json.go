@@ -0,0 +1,101 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// Formats of money values in JSON responses.
+const (
+	// FormatDecimal writes every money value as a string with the decimals
+	// of its currency, e.g. "128.64".
+	FormatDecimal = "decimal"
+	// FormatLegacy keeps the JSON numbers that the estimate, payment and
+	// stop responses used before decimal money, for existing clients.
+	FormatLegacy = "legacy"
+)
+
+// AsNumbers encodes v as JSON with money values written as numbers. A money
+// value is the string "value" of an object that also has a "currency". Key
+// order is preserved.
+func AsNumbers(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := numbers(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func numbers(buf *bytes.Buffer, data json.RawMessage) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || (data[0] != '{' && data[0] != '[') {
+		buf.Write(data)
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	open, _ := dec.Token()
+	if open == json.Delim('[') {
+		buf.WriteByte('[')
+		for i := 0; dec.More(); i++ {
+			var elem json.RawMessage
+			if err := dec.Decode(&elem); err != nil {
+				return err
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := numbers(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+
+	var keys []string
+	fields := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		var val json.RawMessage
+		if err := dec.Decode(&val); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		fields[key] = val
+	}
+	_, isMoney := fields["currency"]
+
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+
+		val := fields[key]
+		if isMoney && key == "value" {
+			var d Decimal
+			if len(val) > 0 && val[0] == '"' && d.UnmarshalJSON(val) == nil {
+				buf.WriteString(strconv.FormatFloat(d.Float64(), 'f', -1, 64))
+				continue
+			}
+		}
+		if err := numbers(buf, val); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
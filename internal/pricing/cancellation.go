@@ -0,0 +1,157 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+)
+
+// CancellationPolicy prices cancelling a reservation from how long before its
+// start the buyer cancels. Rules are evaluated in order:
+//
+//  1. A waiver code (an operator fault) given by the operator makes
+//     cancelling free.
+//  2. Cancelling at least FreeBefore ahead of the start is free.
+//  3. Otherwise the applicable tier's percentage of the amount paid is
+//     charged, but no less than MinimumFee.
+//
+// The fee never exceeds the amount paid, so an unpaid order is always
+// cancelled for free. Orders cannot be cancelled once charging has started.
+type CancellationPolicy struct {
+	FreeBefore time.Duration
+	// Tiers apply to cancelling Within or less before the start; the tier
+	// with the shortest Within applies. A tier with Within 0 covers
+	// cancelling after the start (no-shows).
+	Tiers []CancellationTier
+	// MinimumFee is in the currency of the order.
+	MinimumFee float64
+	// WaiverCodes are cancel codes of operator faults, matched
+	// case-insensitively.
+	WaiverCodes []string
+}
+
+// CancellationTier charges Percent of the amount paid.
+type CancellationTier struct {
+	Within  time.Duration
+	Percent float64
+}
+
+// Cancellation is an order being cancelled.
+type Cancellation struct {
+	// Paid is the amount paid for the order; zero when unpaid.
+	Paid  model.Amount
+	Start time.Time
+	At    time.Time
+	// Code and Reason are the cancel_code and cancel_reason. They waive the
+	// fee only when Operator, the caller holding the operator role, gave
+	// them; buyers cannot claim an operator fault.
+	Code     string
+	Reason   string
+	Operator bool
+}
+
+// CancellationQuote is the outcome of cancelling. Fee plus Refund is the
+// amount paid.
+type CancellationQuote struct {
+	Fee    money.Decimal
+	Refund money.Decimal
+	Waived bool
+	// Rule describes the rule applied, e.g. "10% within 60 min of the start".
+	Rule string
+}
+
+// Evaluate prices c under the policy.
+func (p CancellationPolicy) Evaluate(c Cancellation) CancellationQuote {
+	cur := c.Paid.Currency
+	paid := money.In(c.Paid.Value, cur)
+	free := CancellationQuote{Fee: money.In(money.Decimal{}, cur), Refund: paid}
+
+	switch {
+	case c.Operator && (p.waives(c.Code) || p.waives(c.Reason)):
+		free.Waived = true
+		free.Rule = "waived: operator fault"
+		return free
+	case paid.Sign() <= 0:
+		free.Rule = "nothing paid"
+		return free
+	}
+
+	lead := c.Start.Sub(c.At)
+	if p.FreeBefore > 0 && lead >= p.FreeBefore {
+		free.Rule = fmt.Sprintf("free until %s before the start", minutes(p.FreeBefore))
+		return free
+	}
+
+	fee := money.In(money.Decimal{}, cur)
+	rule := "minimum fee"
+	if t, ok := p.tier(lead); ok {
+		fee = money.Of(paid.Float64()*t.Percent/100, cur)
+		rule = fmt.Sprintf("%s%% %s", formatNumber(t.Percent), within(t.Within))
+	}
+	if minFee := money.Of(p.MinimumFee, cur); fee.Cmp(minFee) < 0 {
+		fee, rule = minFee, fmt.Sprintf("minimum fee %s %s", minFee, cur)
+	}
+	if fee.Cmp(paid) > 0 {
+		fee = paid
+	}
+	return CancellationQuote{Fee: fee, Refund: paid.Sub(fee), Rule: rule}
+}
+
+// tier returns the tier with the shortest Within of at least lead.
+func (p CancellationPolicy) tier(lead time.Duration) (CancellationTier, bool) {
+	var best CancellationTier
+	found := false
+	for _, t := range p.Tiers {
+		if t.Within >= lead && (!found || t.Within < best.Within) {
+			best, found = t, true
+		}
+	}
+	return best, found
+}
+
+func (p CancellationPolicy) waives(code string) bool {
+	code = strings.TrimSpace(code)
+	for _, w := range p.WaiverCodes {
+		if code != "" && strings.EqualFold(code, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe returns the policy as published with an offer. Fee.Percentage is
+// the highest tier percentage.
+func (p CancellationPolicy) Describe(currency string) *model.CancellationPolicy {
+	out := &model.CancellationPolicy{
+		FreeBeforeMinutes: int(p.FreeBefore / time.Minute),
+		FeeWaivedFor:      p.WaiverCodes,
+	}
+	highest := 0.0
+	for _, t := range p.Tiers {
+		out.Tiers = append(out.Tiers, model.CancellationTier{
+			WithinMinutes: int(t.Within / time.Minute),
+			Percentage:    formatNumber(t.Percent),
+		})
+		highest = math.Max(highest, t.Percent)
+	}
+	out.Fee = &model.CancellationFee{Percentage: formatNumber(highest)}
+	if p.MinimumFee > 0 {
+		out.MinimumFee = &model.Amount{Value: money.Of(p.MinimumFee, currency), Currency: currency}
+	}
+	return out
+}
+
+func minutes(d time.Duration) string {
+	return formatNumber(d.Minutes()) + " min"
+}
+
+func within(d time.Duration) string {
+	if d <= 0 {
+		return "after the start"
+	}
+	return "within " + minutes(d) + " of the start"
+}
@@ -0,0 +1,89 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+)
+
+// Price component types of model.PriceComponent. Charges are positive and
+// make up the subtotal with discounts, which are negative. Payments and
+// refunds settle the subtotal: a payment is negative, a refund of part of it
+// positive, so the total is what the buyer still owes.
+const (
+	ComponentBase      = "BASE"      // flat session price
+	ComponentUnit      = "UNIT"      // charging cost of the billed quantity
+	ComponentSurcharge = "SURCHARGE" // e.g. surge pricing
+	ComponentDiscount  = "DISCOUNT"
+	ComponentFee       = "FEE" // service, buyer finder and cancellation fees
+	ComponentTax       = "TAX"
+	ComponentIdleFee   = "IDLE_FEE" // occupying the connector after charging
+	ComponentPaid      = "PAID"     // payment received
+	ComponentRefund    = "REFUND"   // payment returned to the buyer
+)
+
+// ComponentTypes lists the price component types.
+var ComponentTypes = []string{
+	ComponentBase, ComponentUnit, ComponentSurcharge, ComponentDiscount, ComponentFee,
+	ComponentTax, ComponentIdleFee, ComponentPaid, ComponentRefund,
+}
+
+// componentSign is the sign of each component type's value: +1 for values
+// that are zero or more, -1 for values that are zero or less.
+var componentSign = map[string]int{
+	ComponentBase:      1,
+	ComponentUnit:      1,
+	ComponentSurcharge: 1,
+	ComponentDiscount:  -1,
+	ComponentFee:       1,
+	ComponentTax:       1,
+	ComponentIdleFee:   1,
+	ComponentPaid:      -1,
+	ComponentRefund:    1,
+}
+
+// isSettlement reports whether a component type settles the subtotal rather
+// than being part of it.
+func isSettlement(t string) bool {
+	return t == ComponentPaid || t == ComponentRefund
+}
+
+// Component returns a price component of value in currency, rounded to the
+// currency's minor unit.
+func Component(kind string, value money.Decimal, currency, description string) model.PriceComponent {
+	return model.PriceComponent{
+		Type:        kind,
+		Value:       money.In(value, currency),
+		Currency:    currency,
+		Description: description,
+	}
+}
+
+// Breakdown validates components and sums them: Subtotal is the charges less
+// discounts, Total the subtotal less payments plus refunds. Components must
+// have a known type, a value whose sign matches it and the given currency.
+func Breakdown(currency string, components []model.PriceComponent) (*model.PriceBreakdown, error) {
+	subtotal := money.In(money.Decimal{}, currency)
+	total := subtotal
+	for i, c := range components {
+		sign, ok := componentSign[c.Type]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("price component %d: unknown type %q", i, c.Type)
+		case c.Value.Sign() == -sign:
+			return nil, fmt.Errorf("price component %d: %s of %s has the wrong sign", i, c.Type, c.Value)
+		case !strings.EqualFold(c.Currency, currency):
+			return nil, fmt.Errorf("price component %d: currency %s, want %s", i, c.Currency, currency)
+		}
+		if !isSettlement(c.Type) {
+			subtotal = subtotal.Add(c.Value)
+		}
+		total = total.Add(c.Value)
+	}
+	return &model.PriceBreakdown{
+		Subtotal: model.Amount{Value: subtotal, Currency: currency},
+		Total:    model.Amount{Value: total, Currency: currency},
+	}, nil
+}
@@ -0,0 +1,175 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+)
+
+// Idle fee policies in the legacy string form of model.OfferAttributes,
+// matched case-insensitively:
+//
+//	₹2/min after 10 min post-charge
+//	INR 120 per hour after 15 minutes, max ₹500
+//	free
+//
+// The rate and its unit come first; the grace period ("after N min") and the
+// cap ("max", "capped at", "up to") are optional. Other words are ignored.
+var (
+	idleFeeFree  = regexp.MustCompile(`^(?:free|none|no idle fees?)$`)
+	idleFeeRate  = regexp.MustCompile(`^(₹|rs\.?|inr|\$|usd|€|eur|£|gbp)?\s*(\d+(?:\.\d+)?)\s*(inr|rs\.?|usd|eur|gbp)?\s*(?:/|per\s+)\s*(minutes?|mins?|hours?|hrs?|h)\b`)
+	idleFeeGrace = regexp.MustCompile(`\bafter\s+(\d+)\s*(minutes?|mins?|hours?|hrs?|h)\b`)
+	idleFeeCap   = regexp.MustCompile(`\b(?:maximum|max|capped\s+at|cap|up\s+to)\.?\s*:?\s*(₹|rs\.?|inr|\$|usd|€|eur|£|gbp)?\s*(\d+(?:\.\d+)?)\s*(inr|usd|eur|gbp)?`)
+)
+
+// idleFeeCurrencies maps currency symbols and codes of idle fee policies to
+// ISO 4217 codes.
+var idleFeeCurrencies = map[string]string{
+	"₹": "INR", "rs": "INR", "rs.": "INR", "inr": "INR",
+	"$": "USD", "usd": "USD",
+	"€": "EUR", "eur": "EUR",
+	"£": "GBP", "gbp": "GBP",
+}
+
+// ParseIdleFee parses a legacy idle fee policy string. Amounts without a
+// currency are in currency, normally the offer's.
+func ParseIdleFee(s, currency string) (model.IdleFee, error) {
+	text := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	fee := model.IdleFee{Currency: strings.ToUpper(currency), Unit: UnitMinute}
+
+	if idleFeeFree.MatchString(text) {
+		return fee, ValidateIdleFee(fee)
+	}
+	m := idleFeeRate.FindStringSubmatch(text)
+	if m == nil {
+		return model.IdleFee{}, fmt.Errorf("idle fee policy %q does not start with a rate such as \"₹2/min\"", s)
+	}
+	fee.Rate, _ = strconv.ParseFloat(m[2], 64)
+	fee.Unit = idleFeeUnit(m[4])
+	var currencies []string
+	currencies = appendCurrency(currencies, m[1], m[3])
+
+	rest := text[len(m[0]):]
+	if g := idleFeeGrace.FindStringSubmatch(rest); g != nil {
+		n, err := strconv.Atoi(g[1])
+		if err != nil {
+			return model.IdleFee{}, fmt.Errorf("idle fee policy %q has an invalid grace period", s)
+		}
+		if idleFeeUnit(g[2]) == UnitHour {
+			n *= 60
+		}
+		fee.GracePeriodMinutes = n
+	}
+	if c := idleFeeCap.FindStringSubmatch(rest); c != nil {
+		fee.Cap, _ = strconv.ParseFloat(c[2], 64)
+		currencies = appendCurrency(currencies, c[1], c[3])
+	}
+
+	for _, c := range currencies {
+		if fee.Currency == "" {
+			fee.Currency = c
+		}
+		if c != fee.Currency {
+			return model.IdleFee{}, fmt.Errorf("idle fee policy %q is in %s, not %s", s, c, fee.Currency)
+		}
+	}
+	return fee, ValidateIdleFee(fee)
+}
+
+func appendCurrency(currencies []string, symbols ...string) []string {
+	for _, s := range symbols {
+		if s != "" {
+			currencies = append(currencies, idleFeeCurrencies[s])
+		}
+	}
+	return currencies
+}
+
+func idleFeeUnit(s string) string {
+	if strings.HasPrefix(s, "h") {
+		return UnitHour
+	}
+	return UnitMinute
+}
+
+// ValidateIdleFee checks a structured idle fee. An empty unit is per minute.
+func ValidateIdleFee(fee model.IdleFee) error {
+	switch {
+	case fee.Currency == "":
+		return fmt.Errorf("idle fee has no currency")
+	case fee.Rate < 0 || math.IsNaN(fee.Rate) || math.IsInf(fee.Rate, 0):
+		return fmt.Errorf("idle fee rate must not be negative")
+	case fee.Unit != "" && !strings.EqualFold(fee.Unit, UnitMinute) && !strings.EqualFold(fee.Unit, UnitHour):
+		return fmt.Errorf("idle fee unit %q is not supported; use %s or %s", fee.Unit, UnitMinute, UnitHour)
+	case fee.GracePeriodMinutes < 0:
+		return fmt.Errorf("idle fee grace period must not be negative")
+	case fee.Cap < 0 || math.IsNaN(fee.Cap) || math.IsInf(fee.Cap, 0):
+		return fmt.Errorf("idle fee cap must not be negative")
+	}
+	return nil
+}
+
+// IdleFeeOf returns the offer's idle fee: the structured IdleFee, or else
+// the parsed IdleFeePolicy. It is nil when the offer has neither, and an
+// error when the fee is invalid or not in the offer currency.
+func IdleFeeOf(offer model.Offer) (*model.IdleFee, error) {
+	a := offer.OfferAttributes
+	if a == nil || (a.IdleFee == nil && strings.TrimSpace(a.IdleFeePolicy) == "") {
+		return nil, nil
+	}
+	cur := strings.ToUpper(offer.Price.Currency)
+
+	var fee model.IdleFee
+	if a.IdleFee != nil {
+		fee = *a.IdleFee
+		fee.Currency = strings.ToUpper(fee.Currency)
+		fee.Unit = strings.ToUpper(fee.Unit)
+		if fee.Unit == "" {
+			fee.Unit = UnitMinute
+		}
+		if err := ValidateIdleFee(fee); err != nil {
+			return nil, invalidOffer(offer, "has an invalid idle fee: "+err.Error())
+		}
+	} else {
+		var err error
+		if fee, err = ParseIdleFee(a.IdleFeePolicy, cur); err != nil {
+			return nil, invalidOffer(offer, "has an invalid idle fee: "+err.Error())
+		}
+	}
+	if fee.Currency != cur {
+		return nil, invalidOffer(offer, fmt.Sprintf("has an idle fee in %s, not the offer currency %s", fee.Currency, cur))
+	}
+	return &fee, nil
+}
+
+// IdleCharge prices occupying the connector for idle after charging
+// completed: every started minute beyond the grace period at the fee's rate,
+// up to its cap. It reports false when nothing is due.
+func IdleCharge(fee model.IdleFee, idle time.Duration) (model.PriceComponent, bool) {
+	minutes := ceil(idle.Minutes()) - fee.GracePeriodMinutes
+	if minutes <= 0 || fee.Rate <= 0 {
+		return model.PriceComponent{}, false
+	}
+
+	cur := strings.ToUpper(fee.Currency)
+	value, per := fee.Rate*float64(minutes), "min"
+	if strings.EqualFold(fee.Unit, UnitHour) {
+		value, per = value/60, "h"
+	}
+	amount := money.Of(value, cur)
+	description := fmt.Sprintf("Idle fee (%d min at %s %s/%s", minutes, formatNumber(fee.Rate), cur, per)
+	if fee.GracePeriodMinutes > 0 {
+		description += fmt.Sprintf(" after %d min grace", fee.GracePeriodMinutes)
+	}
+	if limit := money.Of(fee.Cap, cur); fee.Cap > 0 && amount.Cmp(limit) > 0 {
+		amount = limit
+		description += ", capped at " + limit.String() + " " + cur
+	}
+	return Component(ComponentIdleFee, amount, cur, description+")"), true
+}
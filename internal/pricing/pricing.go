@@ -0,0 +1,460 @@
+// Package pricing prices charging sessions from an offer's tariff.
+//
+// A quote is built from rounded components: the charging cost of the billed
+// quantity (UNIT), percentage or fixed surcharges, discounts and service fees,
+// and the offer's buyer finder fee. Every component is rounded on its own to
+// the minor unit of the offer currency (see money.Of), and the total is the sum
+// of the rounded components, so the components always add up to the amount
+// shown to the user.
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+)
+
+// Unit codes of model.ApplicableQuantity that can be priced. An offer without
+// an applicable quantity is priced per kWh.
+const (
+	UnitKWh    = "KWH"
+	UnitMinute = "MIN"
+	UnitHour   = "HUR"
+)
+
+// Buyer finder fee types of model.BuyerFinderFee.
+const (
+	FeeTypePercentage = "PERCENTAGE"
+	FeeTypeAmount     = "AMOUNT"
+)
+
+// energyStep is the resolution of priced energy, in kWh.
+const energyStep = 0.001
+
+// Adjustment is a surcharge, discount or service fee. Percent applies to the
+// charging cost before any adjustment; Fixed is an amount in the offer
+// currency. Both may be set.
+type Adjustment struct {
+	Description string
+	Percent     float64
+	Fixed       float64
+}
+
+// Policy lists the adjustments applied on top of the charging cost, and the
+// cancellation terms booked with the session.
+type Policy struct {
+	Surcharges   []Adjustment
+	Discounts    []Adjustment
+	ServiceFees  []Adjustment
+	Cancellation CancellationPolicy
+}
+
+// Profile is how a session charges over time. It converts between the
+// energy delivered and the charging time.
+type Profile interface {
+	// Minutes returns the charging time needed to deliver kwh.
+	Minutes(kwh float64) float64
+	// Energy returns the kWh delivered in minutes of charging.
+	Energy(minutes float64) float64
+	// MaxEnergy returns the most kWh the session can deliver, or 0 when it
+	// is unbounded.
+	MaxEnergy() float64
+}
+
+// ConstantPower is a Profile that charges at a fixed power in kW without
+// limit.
+type ConstantPower float64
+
+func (p ConstantPower) Minutes(kwh float64) float64    { return kwh / float64(p) * 60 }
+func (p ConstantPower) Energy(minutes float64) float64 { return minutes / 60 * float64(p) }
+func (p ConstantPower) MaxEnergy() float64             { return 0 }
+
+// Request is a session to price. Exactly one of Energy and Amount is set:
+// Energy prices a fixed amount of energy, Amount buys as much charging as the
+// budget covers, fees included.
+type Request struct {
+	Offer   model.Offer
+	Profile Profile
+	Energy  *model.Energy
+	Amount  *model.Amount
+	Policy  Policy
+}
+
+// Quote is a priced session.
+type Quote struct {
+	// Energy is in kWh, to the watt-hour.
+	Energy            model.Energy
+	DurationInMinutes int
+	Amount            model.Amount
+	Components        []model.PriceComponent
+}
+
+// session is the quantity being priced.
+type session struct {
+	kwh     float64
+	minutes int
+}
+
+// tariff is an offer's price per unit.
+type tariff struct {
+	price    float64
+	currency string
+	unit     string
+	quantity float64
+}
+
+// Price quotes req. Problems with the request or the offer are validation
+// errors naming the offending field.
+func Price(req Request) (Quote, error) {
+	t, err := tariffOf(req.Offer)
+	if err != nil {
+		return Quote{}, err
+	}
+	if req.Profile == nil || !(req.Profile.Minutes(1) > 0) || math.IsInf(req.Profile.Minutes(1), 0) {
+		return Quote{}, apperror.Validation("connector has no rated power to estimate the session").
+			WithDetail("field", "connector_id")
+	}
+	bff, err := buyerFinderFee(req.Offer)
+	if err != nil {
+		return Quote{}, err
+	}
+	// The idle fee is billed after charging, but is checked up front so that
+	// every booked offer can be settled.
+	if _, err := IdleFeeOf(req.Offer); err != nil {
+		return Quote{}, err
+	}
+	p := pricer{tariff: t, profile: req.Profile, adjustments: append(adjustments(req.Policy), bff...)}
+
+	switch {
+	case req.Energy != nil && req.Amount != nil:
+		return Quote{}, apperror.Validation("energy and amount cannot both be set").
+			WithDetail("field", "amount")
+	case req.Energy != nil:
+		kwh, err := energyKWh(*req.Energy)
+		if err != nil {
+			return Quote{}, err
+		}
+		if maxKWh := req.Profile.MaxEnergy(); maxKWh > 0 && kwh > maxKWh {
+			return Quote{}, apperror.Validation(fmt.Sprintf("energy.value exceeds the %s kWh the battery can take", formatNumber(roundEnergy(maxKWh)))).
+				WithDetail("field", "energy.value")
+		}
+		s := p.forEnergy(kwh)
+		if s.kwh == 0 {
+			return Quote{}, apperror.Validation("energy.value is below the 1 Wh resolution of estimates").
+				WithDetail("field", "energy.value")
+		}
+		return p.quote(s), nil
+	case req.Amount != nil:
+		return p.forAmount(*req.Amount)
+	}
+	return Quote{}, apperror.Validation("energy or amount is required").WithDetail("field", "energy")
+}
+
+// Usage is what a session that took place delivered.
+type Usage struct {
+	// Energy is in kWh.
+	Energy  float64
+	Minutes float64
+}
+
+// PriceUsage bills a session that took place under offer and policy: the
+// energy delivered, to the watt-hour, and the duration, by the started
+// minute. It prices with the same components and rounding as Price.
+func PriceUsage(offer model.Offer, policy Policy, u Usage) (Quote, error) {
+	t, err := tariffOf(offer)
+	if err != nil {
+		return Quote{}, err
+	}
+	bff, err := buyerFinderFee(offer)
+	if err != nil {
+		return Quote{}, err
+	}
+	if u.Energy < 0 || u.Minutes < 0 || math.IsNaN(u.Energy+u.Minutes) || math.IsInf(u.Energy+u.Minutes, 0) {
+		return Quote{}, fmt.Errorf("invalid usage: %v kWh in %v min", u.Energy, u.Minutes)
+	}
+	p := pricer{tariff: t, adjustments: append(adjustments(policy), bff...)}
+	return p.quote(session{kwh: roundEnergy(u.Energy), minutes: ceil(u.Minutes)}), nil
+}
+
+func tariffOf(offer model.Offer) (tariff, error) {
+	t := tariff{
+		price:    offer.Price.Value,
+		currency: strings.ToUpper(offer.Price.Currency),
+		unit:     UnitKWh,
+		quantity: 1,
+	}
+	if q := offer.Price.ApplicableQuantity; q != nil {
+		if q.UnitCode != "" {
+			t.unit = strings.ToUpper(q.UnitCode)
+		}
+		if q.UnitQuantity > 0 {
+			t.quantity = q.UnitQuantity
+		}
+	}
+
+	switch {
+	case t.currency == "":
+		return tariff{}, invalidOffer(offer, "has no currency")
+	case t.price < 0 || math.IsNaN(t.price):
+		return tariff{}, invalidOffer(offer, "has a negative price")
+	case t.unit != UnitKWh && t.unit != UnitMinute && t.unit != UnitHour:
+		return tariff{}, invalidOffer(offer, fmt.Sprintf("is priced per unsupported unit %q", t.unit))
+	}
+	return t, nil
+}
+
+func invalidOffer(offer model.Offer, problem string) *apperror.Error {
+	return apperror.Validation(fmt.Sprintf("offer %q %s", offer.ID, problem)).
+		WithDetail("field", "offer_id")
+}
+
+// buyerFinderFee returns the offer's buyer finder fee as a FEE adjustment.
+func buyerFinderFee(offer model.Offer) ([]adjustment, error) {
+	if offer.OfferAttributes == nil || offer.OfferAttributes.BuyerFinderFee == nil {
+		return nil, nil
+	}
+	fee := offer.OfferAttributes.BuyerFinderFee
+	a := adjustment{kind: ComponentFee, sign: 1, Adjustment: Adjustment{Description: "Buyer finder fee"}}
+	switch strings.ToUpper(fee.FeeType) {
+	case FeeTypePercentage:
+		a.Percent = fee.FeeValue
+	case FeeTypeAmount:
+		a.Fixed = fee.FeeValue
+	default:
+		return nil, invalidOffer(offer, fmt.Sprintf("has unsupported buyer finder fee type %q", fee.FeeType))
+	}
+	return []adjustment{a}, nil
+}
+
+// energyKWh converts a requested energy to kWh.
+func energyKWh(e model.Energy) (float64, error) {
+	var factor float64
+	switch strings.ToLower(e.Unit) {
+	case "kwh", "":
+		factor = 1
+	case "wh":
+		factor = 0.001
+	default:
+		return 0, apperror.Validation(fmt.Sprintf("energy.unit %q is not supported; use kWh or Wh", e.Unit)).
+			WithDetail("field", "energy.unit")
+	}
+	if !(e.Value > 0) || math.IsInf(e.Value, 0) {
+		return 0, apperror.Validation("energy.value must be greater than zero").
+			WithDetail("field", "energy.value")
+	}
+	return e.Value * factor, nil
+}
+
+// adjustment is a policy adjustment with its component type and sign.
+type adjustment struct {
+	Adjustment
+	kind string
+	sign float64
+}
+
+func adjustments(p Policy) []adjustment {
+	var out []adjustment
+	for _, a := range p.Surcharges {
+		out = append(out, adjustment{Adjustment: a, kind: ComponentSurcharge, sign: 1})
+	}
+	for _, a := range p.Discounts {
+		out = append(out, adjustment{Adjustment: a, kind: ComponentDiscount, sign: -1})
+	}
+	for _, a := range p.ServiceFees {
+		out = append(out, adjustment{Adjustment: a, kind: ComponentFee, sign: 1})
+	}
+	return out
+}
+
+type pricer struct {
+	tariff      tariff
+	profile     Profile
+	adjustments []adjustment
+}
+
+// forEnergy is a session delivering kwh, billed by the started minute.
+func (p pricer) forEnergy(kwh float64) session {
+	kwh = roundEnergy(kwh)
+	return session{kwh: kwh, minutes: ceil(p.profile.Minutes(kwh))}
+}
+
+// forMinutes is a session charging for the given minutes.
+func (p pricer) forMinutes(minutes int) session {
+	kwh := roundEnergy(p.profile.Energy(float64(minutes)))
+	return session{kwh: kwh, minutes: minutes}
+}
+
+// units returns the number of tariff units s is billed for.
+func (p pricer) units(s session) float64 {
+	switch p.tariff.unit {
+	case UnitMinute:
+		return float64(s.minutes) / p.tariff.quantity
+	case UnitHour:
+		return float64(s.minutes) / 60 / p.tariff.quantity
+	}
+	return s.kwh / p.tariff.quantity
+}
+
+func (p pricer) quote(s session) Quote {
+	cur := p.tariff.currency
+	base := p.tariff.price * p.units(s)
+
+	components := []model.PriceComponent{{
+		Type:        ComponentUnit,
+		Value:       money.Of(base, cur),
+		Currency:    cur,
+		Description: p.describe(s),
+	}}
+	for _, a := range p.adjustments {
+		v := money.Of(base*a.Percent/100+a.Fixed, cur)
+		if v.IsZero() {
+			continue
+		}
+		if a.sign < 0 {
+			v = v.Neg()
+		}
+		components = append(components, model.PriceComponent{
+			Type:        a.kind,
+			Value:       v,
+			Currency:    cur,
+			Description: label(a.Adjustment),
+		})
+	}
+
+	total := money.In(money.Decimal{}, cur)
+	for _, c := range components {
+		total = total.Add(c.Value)
+	}
+	return Quote{
+		Energy:            model.Energy{Value: s.kwh, Unit: "kWh"},
+		DurationInMinutes: s.minutes,
+		Amount:            model.Amount{Value: total, Currency: cur},
+		Components:        components,
+	}
+}
+
+// forAmount finds the largest session whose total fits the budget, capped at
+// the profile's MaxEnergy. The
+// adjustments are linear in the charging cost, so the budget is inverted to
+// a charging cost first; rounding of the components can move the total
+// either way, so the quantity is then stepped up or down to the largest one
+// within budget.
+func (p pricer) forAmount(budget model.Amount) (Quote, error) {
+	if !strings.EqualFold(budget.Currency, p.tariff.currency) {
+		return Quote{}, apperror.Validation(fmt.Sprintf("amount.currency must be %s, the currency of the offer", p.tariff.currency)).
+			WithDetail("field", "amount.currency")
+	}
+	if budget.Value.Sign() <= 0 {
+		return Quote{}, apperror.Validation("amount.value must be greater than zero").
+			WithDetail("field", "amount.value")
+	}
+	if p.tariff.price == 0 {
+		return Quote{}, apperror.Validation("the offer is free; request energy instead of an amount").
+			WithDetail("field", "amount")
+	}
+
+	rate, fixed := 1.0, 0.0
+	for _, a := range p.adjustments {
+		rate += a.sign * a.Percent / 100
+		fixed += a.sign * a.Fixed
+	}
+	base := (budget.Value.Float64() - fixed) / rate
+	if rate <= 0 || base <= 0 {
+		return Quote{}, tooSmall(budget)
+	}
+
+	// Quantity in steps of energyStep kWh or whole minutes, up to limit.
+	n := base / p.tariff.price * p.tariff.quantity
+	limit := math.MaxInt32
+	maxKWh := p.profile.MaxEnergy()
+	at := func(i int) session { return p.forEnergy(float64(i) * energyStep) }
+	switch p.tariff.unit {
+	case UnitKWh:
+		n /= energyStep
+		if maxKWh > 0 {
+			limit = int(math.Floor(maxKWh/energyStep + 1e-9))
+		}
+	default:
+		if p.tariff.unit == UnitHour {
+			n *= 60
+		}
+		if maxKWh > 0 {
+			limit = ceil(p.profile.Minutes(maxKWh))
+		}
+		at = p.forMinutes
+	}
+
+	fits := func(i int) (Quote, bool) {
+		q := p.quote(at(i))
+		return q, q.Amount.Value.Cmp(budget.Value) <= 0
+	}
+	i := int(math.Min(math.Floor(n+1e-9), float64(limit)))
+	if q, ok := fits(i); ok && i > 0 {
+		for i < limit {
+			next, ok := fits(i + 1)
+			if !ok {
+				return q, nil
+			}
+			q, i = next, i+1
+		}
+		return q, nil
+	}
+	for i--; i > 0; i-- {
+		if q, ok := fits(i); ok {
+			return q, nil
+		}
+	}
+	return Quote{}, tooSmall(budget)
+}
+
+func tooSmall(budget model.Amount) *apperror.Error {
+	return apperror.Validation(fmt.Sprintf("amount %s %s does not cover any charging after fees",
+		budget.Value, budget.Currency)).
+		WithDetail("field", "amount.value")
+}
+
+// describe labels the charging cost, e.g. "Charging cost (30 kWh at 18 INR/kWh)".
+func (p pricer) describe(s session) string {
+	t := p.tariff
+	var used, per string
+	switch t.unit {
+	case UnitMinute:
+		used, per = strconv.Itoa(s.minutes)+" min", "min"
+	case UnitHour:
+		used, per = formatNumber(float64(s.minutes)/60)+" h", "h"
+	default:
+		used, per = formatNumber(s.kwh)+" kWh", "kWh"
+	}
+	if t.quantity != 1 {
+		per = formatNumber(t.quantity) + " " + per
+	}
+	return fmt.Sprintf("Charging cost (%s at %s %s/%s)", used, formatNumber(t.price), t.currency, per)
+}
+
+// label is an adjustment's description with its percentage, e.g.
+// "Surge price (20%)".
+func label(a Adjustment) string {
+	if a.Percent == 0 {
+		return a.Description
+	}
+	return fmt.Sprintf("%s (%s%%)", a.Description, formatNumber(a.Percent))
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// roundEnergy rounds kwh to energyStep.
+func roundEnergy(kwh float64) float64 {
+	return math.Round(kwh/energyStep) / (1 / energyStep)
+}
+
+// ceil rounds up, ignoring floating point noise below a millionth.
+func ceil(v float64) int {
+	return int(math.Ceil(v - 1e-6))
+}
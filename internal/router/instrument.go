@@ -0,0 +1,174 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"bff-go-mvp/internal/domain/estimate"
+	"bff-go-mvp/internal/domain/feedback"
+	"bff-go-mvp/internal/domain/orders"
+	"bff-go-mvp/internal/domain/payment"
+	"bff-go-mvp/internal/domain/search"
+	"bff-go-mvp/internal/domain/support"
+	"bff-go-mvp/internal/metrics"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/tracing"
+)
+
+// observer records domain service calls as child spans and in the backend
+// call metrics.
+type observer struct {
+	domain  string
+	metrics *metrics.Backend
+}
+
+// start begins a span named "<domain>.<operation>". The returned function
+// ends it and records the call.
+func (o observer) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	begin := time.Now()
+	ctx, span := tracing.Tracer().Start(ctx, o.domain+"."+operation,
+		trace.WithAttributes(attribute.String("bff.domain", o.domain)),
+	)
+	return ctx, func(err error) {
+		tracing.EndSpan(span, err)
+		o.metrics.Observe(operation, begin, err)
+	}
+}
+
+// The types below wrap each domain service so that every call is observed.
+
+type instrumentedSearch struct {
+	next search.Service
+	obs  observer
+}
+
+func (s instrumentedSearch) Search(ctx context.Context, page, perPage int, req model.SearchRequest) (model.SearchResponse, error) {
+	ctx, end := s.obs.start(ctx, "search")
+	resp, err := s.next.Search(ctx, page, perPage, req)
+	end(err)
+	return resp, err
+}
+
+type instrumentedEstimate struct {
+	next estimate.Service
+	obs  observer
+}
+
+func (s instrumentedEstimate) Estimate(ctx context.Context, req model.EstimateRequest) (model.EstimateResponse, error) {
+	ctx, end := s.obs.start(ctx, "estimate")
+	resp, err := s.next.Estimate(ctx, req)
+	end(err)
+	return resp, err
+}
+
+type instrumentedPayment struct {
+	next payment.Service
+	obs  observer
+}
+
+func (s instrumentedPayment) InitiatePayment(ctx context.Context, orderID string, req model.PaymentRequest) (model.PaymentResponse, error) {
+	ctx, end := s.obs.start(ctx, "initiate_payment")
+	resp, err := s.next.InitiatePayment(ctx, orderID, req)
+	end(err)
+	return resp, err
+}
+
+func (s instrumentedPayment) HandleEvent(ctx context.Context, provider string, event payment.PSPEvent) (model.PaymentWebhookResponse, error) {
+	ctx, end := s.obs.start(ctx, "payment_event")
+	resp, err := s.next.HandleEvent(ctx, provider, event)
+	end(err)
+	return resp, err
+}
+
+func (s instrumentedPayment) ListRefunds(ctx context.Context, orderID string) (model.RefundsResponse, error) {
+	ctx, end := s.obs.start(ctx, "list_refunds")
+	resp, err := s.next.ListRefunds(ctx, orderID)
+	end(err)
+	return resp, err
+}
+
+type instrumentedOrders struct {
+	next orders.Service
+	obs  observer
+}
+
+func (s instrumentedOrders) GetOrder(ctx context.Context, orderID string) (model.OrderResponse, error) {
+	ctx, end := s.obs.start(ctx, "get_order")
+	resp, err := s.next.GetOrder(ctx, orderID)
+	end(err)
+	return resp, err
+}
+
+type instrumentedLifecycle struct {
+	next orders.LifecycleService
+	obs  observer
+}
+
+func (s instrumentedLifecycle) EstimateCancel(ctx context.Context, orderID, activity, cancelReason, cancelCode string) (model.CancelEstimateResponse, error) {
+	ctx, end := s.obs.start(ctx, "estimate_cancel")
+	resp, err := s.next.EstimateCancel(ctx, orderID, activity, cancelReason, cancelCode)
+	end(err)
+	return resp, err
+}
+
+func (s instrumentedLifecycle) Cancel(ctx context.Context, orderID string, body map[string]interface{}) (model.CancelResponse, error) {
+	ctx, end := s.obs.start(ctx, "cancel")
+	resp, err := s.next.Cancel(ctx, orderID, body)
+	end(err)
+	return resp, err
+}
+
+func (s instrumentedLifecycle) EstimateStop(ctx context.Context, orderID, activity string) (model.StopEstimateResponse, error) {
+	ctx, end := s.obs.start(ctx, "estimate_stop")
+	resp, err := s.next.EstimateStop(ctx, orderID, activity)
+	end(err)
+	return resp, err
+}
+
+func (s instrumentedLifecycle) Stop(ctx context.Context, orderID string, req model.StopChargingRequest) (model.StopChargingResponse, error) {
+	ctx, end := s.obs.start(ctx, "stop")
+	resp, err := s.next.Stop(ctx, orderID, req)
+	end(err)
+	return resp, err
+}
+
+func (s instrumentedLifecycle) Start(ctx context.Context, orderID string, req model.StartChargingRequest) (model.StartChargingResponse, error) {
+	ctx, end := s.obs.start(ctx, "start")
+	resp, err := s.next.Start(ctx, orderID, req)
+	end(err)
+	return resp, err
+}
+
+func (s instrumentedLifecycle) Unplug(ctx context.Context, orderID string) (model.UnplugResponse, error) {
+	ctx, end := s.obs.start(ctx, "unplug")
+	resp, err := s.next.Unplug(ctx, orderID)
+	end(err)
+	return resp, err
+}
+
+type instrumentedFeedback struct {
+	next feedback.Service
+	obs  observer
+}
+
+func (s instrumentedFeedback) SetRating(ctx context.Context, orderID string, req model.RatingRequest) (model.RatingResponse, error) {
+	ctx, end := s.obs.start(ctx, "set_rating")
+	resp, err := s.next.SetRating(ctx, orderID, req)
+	end(err)
+	return resp, err
+}
+
+type instrumentedSupport struct {
+	next support.Service
+	obs  observer
+}
+
+func (s instrumentedSupport) GetSupport(ctx context.Context, orderID string) (model.SupportResponse, error) {
+	ctx, end := s.obs.start(ctx, "get_support")
+	resp, err := s.next.GetSupport(ctx, orderID)
+	end(err)
+	return resp, err
+}
@@ -1,13 +1,20 @@
 package router
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 
+	"bff-go-mvp/internal/auth"
 	"bff-go-mvp/internal/config"
 	"bff-go-mvp/internal/domain/estimate"
 	"bff-go-mvp/internal/domain/feedback"
@@ -15,103 +22,441 @@ import (
 	"bff-go-mvp/internal/domain/payment"
 	"bff-go-mvp/internal/domain/search"
 	"bff-go-mvp/internal/domain/support"
+	"bff-go-mvp/internal/fakepsp"
+	grpcclient "bff-go-mvp/internal/grpc"
 	"bff-go-mvp/internal/handler"
+	"bff-go-mvp/internal/health"
+	"bff-go-mvp/internal/httpclient"
+	"bff-go-mvp/internal/httpx"
+	"bff-go-mvp/internal/metrics"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/tracing"
+	"bff-go-mvp/internal/transaction"
+	"bff-go-mvp/internal/vehicles"
+	"bff-go-mvp/internal/webhook"
 )
 
 // New constructs the main HTTP router, wiring all handlers and middleware.
-func New(cfg *config.Config, logger *zap.Logger) *mux.Router {
+// The readiness checks of the selected backends are registered on probes.
+// Background work of the backends, such as the refund sweep, runs until ctx
+// is done.
+func New(ctx context.Context, cfg *config.Config, logger *zap.Logger, probes *health.Health) *mux.Router {
 	r := mux.NewRouter()
+	m := metrics.New()
 
-	// Middleware
+	// Middleware. The transaction ID and server span come first so that log
+	// lines and every response, including errors, carry them.
+	r.Use(transaction.Middleware)
+	r.Use(tracing.Middleware)
 	r.Use(loggingMiddleware(logger))
+	r.Use(m.Middleware)
 	r.Use(recoveryMiddleware(logger))
+	r.Use(httpx.MoneyFormatMiddleware(cfg.API.MoneyFormat))
+	if cfg.Auth.Enabled {
+		verifier, err := auth.NewVerifier(ctx, cfg.Auth)
+		if err != nil {
+			logger.Fatal("Failed to initialise token verification", zap.Error(err))
+		}
+		r.Use(auth.Middleware(verifier, logger, publicRoutes(cfg.Payment.FakePSP)))
+	} else {
+		logger.Warn("Authentication is disabled; every caller can act on every order")
+	}
 
 	// Services
-	searchService := chooseSearchService(cfg, logger)
-	estimateService := chooseEstimateService(cfg, logger)
-	paymentService := choosePaymentService(cfg, logger)
-	ordersService := chooseOrdersService(cfg, logger)
-	lifecycleService := chooseOrdersLifecycleService(cfg, logger)
-	feedbackService := chooseFeedbackService(cfg, logger)
-	supportService := chooseSupportService(cfg, logger)
+	b := newBackends(ctx, cfg, logger, m)
+	searchService := chooseSearchService(cfg, b)
+	estimateService := chooseEstimateService(cfg, b)
+	paymentService := choosePaymentService(cfg, b)
+	ordersService := chooseOrdersService(cfg, b)
+	lifecycleService := chooseOrdersLifecycleService(cfg, b)
+	feedbackService := chooseFeedbackService(cfg, b)
+	supportService := chooseSupportService(cfg, b)
+	access := orders.NewAccessPolicy(b.ownership(), cfg.Auth.OperatorRole, cfg.Auth.Enabled)
+	b.registerChecks(probes)
 
 	// Handlers
-	searchHandler := handler.NewSearchHandler(searchService, logger)
-	estimateHandler := handler.NewEstimateHandler(estimateService, logger)
-	paymentHandler := handler.NewPaymentHandler(paymentService, logger)
-	ordersHandler := handler.NewOrdersHandler(ordersService, logger)
-	ordersLifecycleHandler := handler.NewOrdersLifecycleHandler(lifecycleService, logger)
-	feedbackHandler := handler.NewFeedbackHandler(feedbackService, logger)
-	supportHandler := handler.NewSupportHandler(supportService, logger)
+	searchHandler := handler.NewSearchHandler(searchService, b.vehicles(), logger)
+	estimateHandler := handler.NewEstimateHandler(estimateService, access, logger)
+	paymentHandler := handler.NewPaymentHandler(paymentService, access, logger)
+	webhookHandler := handler.NewPaymentWebhookHandler(paymentService,
+		webhook.NewVerifier(b.webhookSecrets(), cfg.Payment.WebhookTolerance),
+		webhook.NewDeduper(webhook.Retention(cfg.Payment.WebhookTolerance)), logger)
+	ordersHandler := handler.NewOrdersHandler(ordersService, access, logger)
+	ordersLifecycleHandler := handler.NewOrdersLifecycleHandler(lifecycleService, access, logger)
+	feedbackHandler := handler.NewFeedbackHandler(feedbackService, access, logger)
+	supportHandler := handler.NewSupportHandler(supportService, access, logger)
+	vehiclesHandler := handler.NewVehiclesHandler(b.vehicles(), logger)
 
 	// Routes from swagger.yaml
 	r.HandleFunc("/v1/search", searchHandler.SearchChargingConnectors).Methods(http.MethodPost)
 	r.HandleFunc("/v1/estimate", estimateHandler.GetEstimates).Methods(http.MethodPost)
+	r.HandleFunc("/v1/vehicles", vehiclesHandler.ListVehicles).Methods(http.MethodGet)
 	r.HandleFunc("/v1/orders/{order_id}/payment", paymentHandler.InitiatePayment).Methods(http.MethodPost)
+	r.HandleFunc("/v1/orders/{order_id}/refunds", paymentHandler.ListRefunds).Methods(http.MethodGet)
 	r.HandleFunc("/v1/orders/{order_id}", ordersHandler.GetOrder).Methods(http.MethodGet)
 	r.HandleFunc("/v1/orders/{order_id}/cancel", ordersLifecycleHandler.EstimateCancel).Methods(http.MethodGet)
 	r.HandleFunc("/v1/orders/{order_id}/cancel", ordersLifecycleHandler.Cancel).Methods(http.MethodPost)
 	r.HandleFunc("/v1/orders/{order_id}/stop", ordersLifecycleHandler.EstimateStop).Methods(http.MethodGet)
 	r.HandleFunc("/v1/orders/{order_id}/stop", ordersLifecycleHandler.StopCharging).Methods(http.MethodPut)
 	r.HandleFunc("/v1/orders/{order_id}/start", ordersLifecycleHandler.StartCharging).Methods(http.MethodPut)
+	r.HandleFunc("/v1/orders/{order_id}/unplug", ordersLifecycleHandler.Unplug).Methods(http.MethodPut)
 	r.HandleFunc("/v1/orders/{order_id}/rating", feedbackHandler.SetOrderRating).Methods(http.MethodPost)
 	r.HandleFunc("/v1/orders/{order_id}/support", supportHandler.GetOrderSupport).Methods(http.MethodGet)
+	r.HandleFunc("/v1/webhooks/payments/{provider}", webhookHandler.HandlePaymentEvent).Methods(http.MethodPost)
+
+	// Probes. /health is kept as an alias of /livez for existing callers.
+	r.HandleFunc("/livez", probes.LiveHandler).Methods(http.MethodGet)
+	r.HandleFunc("/readyz", probes.ReadyHandler).Methods(http.MethodGet)
+	r.HandleFunc("/health", probes.LiveHandler).Methods(http.MethodGet)
 
-	// Health
-	r.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("OK"))
-	}).Methods(http.MethodGet)
+	// The in-process fake PSP, when payments use it, serves its API and
+	// checkout pages under /psp/.
+	if b.fakePSP != nil {
+		r.PathPrefix(fakePSPPrefix + "/").Handler(http.StripPrefix(fakePSPPrefix, b.fakePSP.Handler()))
+	}
+
+	// Prometheus metrics
+	r.Handle("/metrics", m.Handler()).Methods(http.MethodGet)
 
 	// Swagger UI (served by swaggo/http-swagger).
 	// The docs are registered via the blank import of `internal/docs` in cmd/api/main.go.
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
+	// Unmatched requests bypass r.Use middleware; wrap them so they still
+	// get a transaction ID, a span and are counted in the metrics.
+	r.NotFoundHandler = transaction.Middleware(tracing.Middleware(m.Middleware(http.NotFoundHandler())))
+	r.MethodNotAllowedHandler = transaction.Middleware(tracing.Middleware(m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))))
+
 	return r
 }
 
-func chooseSearchService(cfg *config.Config, logger *zap.Logger) search.Service {
-	// For now we only support the mock service. Later we can switch on cfg.Backend.Mode
-	// to return a gRPC-backed implementation.
-	_ = logger
-	_ = cfg
-	return search.NewMockService()
+// fakePSPPrefix is where the in-process fake PSP is mounted, and
+// fakePSPProvider the provider name of its webhooks.
+const (
+	fakePSPPrefix   = "/psp"
+	fakePSPProvider = "fakepsp"
+)
+
+// publicRoutes reports whether a request may skip authentication. Payment
+// webhooks are authenticated by their signature instead. The fake PSP's
+// checkout pages, opened by the buyer's browser, are public only when the
+// fake PSP is mounted.
+func publicRoutes(fakePSP bool) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		switch r.URL.Path {
+		case "/health", "/livez", "/readyz", "/metrics":
+			return true
+		}
+		return strings.HasPrefix(r.URL.Path, "/swagger/") ||
+			(fakePSP && strings.HasPrefix(r.URL.Path, fakePSPPrefix+"/")) ||
+			strings.HasPrefix(r.URL.Path, "/v1/webhooks/")
+	}
+}
+
+// backends lazily builds the downstream clients shared by the services of
+// every domain that is not in mock mode, and the order repository and
+// station fixture shared by the mocks, the vehicle registry and the payment
+// service provider.
+type backends struct {
+	// ctx bounds the background work started by the backends.
+	ctx         context.Context
+	cfg         *config.Config
+	logger      *zap.Logger
+	grpcClient  *grpcclient.Client
+	httpClient  *httpclient.Client
+	orderRepo   *orders.MemoryRepository
+	stationList []model.Catalog
+	registry    *vehicles.Registry
+	paymentPSP  payment.PSP
+	fakePSP     *fakepsp.Server
+	secrets     map[string]string
+	refunder    *payment.RefundService
+	metrics     *metrics.Metrics
 }
 
-func chooseEstimateService(cfg *config.Config, logger *zap.Logger) estimate.Service {
-	_ = logger
-	_ = cfg
-	return estimate.NewMockService()
+func newBackends(ctx context.Context, cfg *config.Config, logger *zap.Logger, m *metrics.Metrics) *backends {
+	return &backends{ctx: ctx, cfg: cfg, logger: logger, metrics: m}
 }
 
-func choosePaymentService(cfg *config.Config, logger *zap.Logger) payment.Service {
-	_ = logger
-	_ = cfg
-	return payment.NewMockService()
+func (b *backends) grpc() *grpcclient.Client {
+	if b.grpcClient == nil {
+		client, err := grpcclient.NewClient(b.cfg.GRPC.ServiceAddress,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithUnaryInterceptor(b.metrics.UnaryClientInterceptor()),
+		)
+		if err != nil {
+			b.logger.Fatal("Failed to create gRPC client",
+				zap.String("address", b.cfg.GRPC.ServiceAddress),
+				zap.Error(err),
+			)
+		}
+		b.grpcClient = client
+	}
+	return b.grpcClient
 }
 
-func chooseOrdersService(cfg *config.Config, logger *zap.Logger) orders.Service {
-	_ = logger
-	_ = cfg
-	return orders.NewMockService()
+func (b *backends) http() *httpclient.Client {
+	if b.httpClient == nil {
+		b.httpClient = httpclient.New(b.cfg.Backend.HTTPBaseURL, b.cfg.Backend.HTTPTimeout)
+	}
+	return b.httpClient
 }
 
-func chooseOrdersLifecycleService(cfg *config.Config, logger *zap.Logger) orders.LifecycleService {
-	_ = logger
-	_ = cfg
-	return orders.NewMockLifecycleService()
+func (b *backends) orders() *orders.MemoryRepository {
+	if b.orderRepo == nil {
+		b.orderRepo = orders.NewMemoryRepository()
+	}
+	return b.orderRepo
 }
 
-func chooseFeedbackService(cfg *config.Config, logger *zap.Logger) feedback.Service {
-	_ = logger
-	_ = cfg
-	return feedback.NewMockService()
+// stations loads the station fixture used by search in index mode.
+func (b *backends) stations() []model.Catalog {
+	if b.stationList == nil {
+		path := b.cfg.Backend.StationsFile
+		stations, err := search.LoadStations(path)
+		if err != nil {
+			b.logger.Fatal("Failed to load stations", zap.String("path", path), zap.Error(err))
+		}
+		b.stationList = stations
+	}
+	return b.stationList
+}
+
+// stationIndex indexes the stations for search in index mode.
+func (b *backends) stationIndex() *search.IndexService {
+	path := b.cfg.Backend.StationsFile
+	index, err := search.NewIndexService(b.stations(), b.vehicles())
+	if err != nil {
+		b.logger.Fatal("Failed to index stations", zap.String("path", path), zap.Error(err))
+	}
+	b.logger.Info("Stations indexed", zap.String("path", path), zap.Int("stations", index.Len()))
+	return index
 }
 
-func chooseSupportService(cfg *config.Config, logger *zap.Logger) support.Service {
-	_ = logger
-	_ = cfg
-	return support.NewMockService()
+// vehicles returns the vehicle registry: VEHICLES_FILE when set, else the
+// built-in one.
+func (b *backends) vehicles() *vehicles.Registry {
+	if b.registry == nil {
+		path := b.cfg.Backend.VehiclesFile
+		if path == "" {
+			b.registry = vehicles.Default()
+			return b.registry
+		}
+		registry, err := vehicles.Load(path)
+		if err != nil {
+			b.logger.Fatal("Failed to load vehicles", zap.String("path", path), zap.Error(err))
+		}
+		b.registry = registry
+	}
+	return b.registry
+}
+
+// psp returns the payment service provider: the PSP at PAYMENT_PSP_URL, or
+// with PAYMENT_FAKE_PSP the in-process fake PSP mounted at /psp/.
+func (b *backends) psp() payment.PSP {
+	if b.paymentPSP == nil {
+		cfg := b.cfg.Payment
+		if !cfg.FakePSP {
+			if cfg.PSPURL == "" {
+				b.logger.Fatal("PAYMENT_PSP_URL is required when payment uses mock mode")
+			}
+			b.paymentPSP = payment.NewPSPClient(httpclient.New(cfg.PSPURL, b.cfg.Backend.HTTPTimeout))
+			return b.paymentPSP
+		}
+		callback := cfg.CallbackURL
+		if callback == "" {
+			callback = strings.TrimRight(cfg.PublicURL, "/") + "/v1/webhooks/payments/" + fakePSPProvider
+		}
+		secrets := b.webhookSecrets()
+		if secrets[fakePSPProvider] == "" {
+			key := make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				b.logger.Fatal("Failed to generate the fake PSP webhook secret", zap.Error(err))
+			}
+			secrets[fakePSPProvider] = hex.EncodeToString(key)
+		}
+		b.fakePSP = fakepsp.New(fakepsp.Options{
+			BaseURL:       strings.TrimRight(cfg.PublicURL, "/") + fakePSPPrefix,
+			AutoCapture:   cfg.AutoCapture,
+			CallbackURL:   callback,
+			WebhookSecret: secrets[fakePSPProvider],
+			Logger:        b.logger,
+		})
+		b.paymentPSP = b.fakePSP
+	}
+	return b.paymentPSP
+}
+
+// webhookSecrets returns the payment webhook secrets per provider. The
+// in-process fake PSP adds a random one for its own webhooks unless
+// PAYMENT_WEBHOOK_SECRETS sets it.
+func (b *backends) webhookSecrets() map[string]string {
+	if b.secrets == nil {
+		b.secrets = make(map[string]string, len(b.cfg.Payment.WebhookSecrets)+1)
+		for provider, secret := range b.cfg.Payment.WebhookSecrets {
+			b.secrets[provider] = secret
+		}
+	}
+	return b.secrets
+}
+
+// refunds returns the refund service that sends the refunds of cancelled
+// and stopped orders through the payment gateways, and starts its sweep of
+// due refunds.
+func (b *backends) refunds() *payment.RefundService {
+	if b.refunder == nil {
+		cfg := b.cfg.Payment
+		merchant := payment.Merchant{VPA: cfg.MerchantVPA, Name: cfg.MerchantName}
+		b.refunder = payment.NewRefundService(b.orders(), payment.NewGateways(b.psp(), merchant),
+			payment.RetryPolicy{MaxAttempts: cfg.RefundMaxAttempts, Backoff: cfg.RefundRetryBackoff}, b.logger)
+		go b.refunder.Run(b.ctx, cfg.RefundSweepInterval)
+	}
+	return b.refunder
+}
+
+// offerBook returns the offers the mock estimate service prices: those of the
+// mock search catalog and, when search runs in index mode, of the indexed
+// stations, so every offer a search returns can be estimated.
+func (b *backends) offerBook() *estimate.OfferBook {
+	if b.cfg.Backend.Search == config.BackendModeIndex {
+		return estimate.NewOfferBook(search.MockCatalogs(), b.stations())
+	}
+	return estimate.NewOfferBook(search.MockCatalogs())
+}
+
+// ownership returns where order owners are recorded: on the order, in the
+// shared repository for orders created by the mock estimate service and by
+// the downstream backend for the orders it creates.
+func (b *backends) ownership() orders.Ownership {
+	if b.cfg.Backend.Estimate == config.BackendModeMock {
+		return b.orders()
+	}
+	return orders.NewHTTPOwnership(b.http())
+}
+
+// registerChecks adds a readiness check for each backend that was built.
+func (b *backends) registerChecks(probes *health.Health) {
+	timeout := b.cfg.API.HealthCheckTimeout
+	if b.grpcClient != nil {
+		probes.Register("grpc_discovery", timeout, b.grpcClient)
+	}
+	if b.httpClient != nil {
+		probes.Register("http_backend", timeout, b.httpClient)
+	}
+	if b.orderRepo != nil {
+		probes.Register("order_store", timeout, b.orderRepo)
+	}
+	if b.paymentPSP != nil {
+		probes.Register("payment_gateway", timeout, b.paymentPSP)
+	}
+	b.logger.Info("Readiness checks registered", zap.Strings("checks", probes.Names()))
+}
+
+// selected logs which implementation a domain was wired with and returns the
+// observer for its calls.
+func (b *backends) selected(domain string, mode config.BackendMode) observer {
+	fields := []zap.Field{
+		zap.String("domain", domain),
+		zap.String("mode", string(mode)),
+	}
+	switch mode {
+	case config.BackendModeGRPC:
+		fields = append(fields, zap.String("target", b.cfg.GRPC.ServiceAddress))
+	case config.BackendModeHTTP:
+		fields = append(fields, zap.String("target", b.cfg.Backend.HTTPBaseURL))
+	case config.BackendModeIndex:
+		fields = append(fields, zap.String("target", b.cfg.Backend.StationsFile))
+	}
+	b.logger.Info("Backend selected", fields...)
+	return observer{domain: domain, metrics: b.metrics.Backend(domain, string(mode))}
+}
+
+// The choose functions below fall back to the mock for any mode without an
+// implementation; config.Validate rejects those combinations at startup.
+
+func chooseSearchService(cfg *config.Config, b *backends) search.Service {
+	mode := cfg.Backend.Search
+	switch mode {
+	case config.BackendModeGRPC:
+		obs := b.selected(config.DomainSearch, mode)
+		return instrumentedSearch{next: search.NewGRPCService(b.grpc(), b.vehicles(), b.logger), obs: obs}
+	case config.BackendModeHTTP:
+		obs := b.selected(config.DomainSearch, mode)
+		return instrumentedSearch{next: search.NewHTTPService(b.http()), obs: obs}
+	case config.BackendModeIndex:
+		obs := b.selected(config.DomainSearch, mode)
+		return instrumentedSearch{next: b.stationIndex(), obs: obs}
+	}
+	obs := b.selected(config.DomainSearch, config.BackendModeMock)
+	return instrumentedSearch{next: search.NewMockService(b.vehicles()), obs: obs}
+}
+
+func chooseEstimateService(cfg *config.Config, b *backends) estimate.Service {
+	if cfg.Backend.Estimate == config.BackendModeHTTP {
+		obs := b.selected(config.DomainEstimate, config.BackendModeHTTP)
+		return instrumentedEstimate{next: estimate.NewHTTPService(b.http()), obs: obs}
+	}
+	obs := b.selected(config.DomainEstimate, config.BackendModeMock)
+	return instrumentedEstimate{next: estimate.NewMockService(b.orders(), b.offerBook(), b.vehicles(), estimate.MockPolicy), obs: obs}
+}
+
+func choosePaymentService(cfg *config.Config, b *backends) payment.Service {
+	if cfg.Backend.Payment == config.BackendModeHTTP {
+		obs := b.selected(config.DomainPayment, config.BackendModeHTTP)
+		return instrumentedPayment{next: payment.NewHTTPService(b.http()), obs: obs}
+	}
+	obs := b.selected(config.DomainPayment, config.BackendModeMock)
+	merchant := payment.Merchant{VPA: cfg.Payment.MerchantVPA, Name: cfg.Payment.MerchantName}
+	gateways := payment.NewGateways(b.psp(), merchant)
+	provider := cfg.Payment.PSPProvider
+	if cfg.Payment.FakePSP {
+		provider = fakePSPProvider
+	}
+	return instrumentedPayment{next: payment.NewMockService(b.orders(), gateways, merchant, provider, b.refunds()), obs: obs}
+}
+
+func chooseOrdersService(cfg *config.Config, b *backends) orders.Service {
+	if cfg.Backend.Orders == config.BackendModeHTTP {
+		obs := b.selected(config.DomainOrders, config.BackendModeHTTP)
+		return instrumentedOrders{next: orders.NewHTTPService(b.http()), obs: obs}
+	}
+	obs := b.selected(config.DomainOrders, config.BackendModeMock)
+	return instrumentedOrders{next: orders.NewMockService(b.orders()), obs: obs}
+}
+
+func chooseOrdersLifecycleService(cfg *config.Config, b *backends) orders.LifecycleService {
+	if cfg.Backend.Lifecycle == config.BackendModeHTTP {
+		obs := b.selected(config.DomainLifecycle, config.BackendModeHTTP)
+		return instrumentedLifecycle{next: orders.NewHTTPLifecycleService(b.http()), obs: obs}
+	}
+	obs := b.selected(config.DomainLifecycle, config.BackendModeMock)
+	// Only orders paid through the payment mock have captured payments in
+	// the repository to refund.
+	var refunds orders.Refunder
+	if cfg.Backend.Payment == config.BackendModeMock {
+		refunds = b.refunds()
+	}
+	return instrumentedLifecycle{next: orders.NewMockLifecycleService(b.orders(), refunds, cfg.Auth.OperatorRole), obs: obs}
+}
+
+func chooseFeedbackService(cfg *config.Config, b *backends) feedback.Service {
+	if cfg.Backend.Feedback == config.BackendModeHTTP {
+		obs := b.selected(config.DomainFeedback, config.BackendModeHTTP)
+		return instrumentedFeedback{next: feedback.NewHTTPService(b.http()), obs: obs}
+	}
+	obs := b.selected(config.DomainFeedback, config.BackendModeMock)
+	return instrumentedFeedback{next: feedback.NewMockService(b.orders()), obs: obs}
+}
+
+func chooseSupportService(cfg *config.Config, b *backends) support.Service {
+	if cfg.Backend.Support == config.BackendModeHTTP {
+		obs := b.selected(config.DomainSupport, config.BackendModeHTTP)
+		return instrumentedSupport{next: support.NewHTTPService(b.http()), obs: obs}
+	}
+	obs := b.selected(config.DomainSupport, config.BackendModeMock)
+	return instrumentedSupport{next: support.NewMockService(b.orders()), obs: obs}
 }
 
 // loggingMiddleware logs HTTP requests.
@@ -125,7 +470,7 @@ func loggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 			next.ServeHTTP(wrapped, r)
 
 			duration := time.Since(start)
-			logger.Info("HTTP request",
+			transaction.Logger(r.Context(), logger).Info("HTTP request",
 				zap.String("method", r.Method),
 				zap.String("path", r.URL.Path),
 				zap.String("remote_addr", r.RemoteAddr),
@@ -142,7 +487,7 @@ func recoveryMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					logger.Error("Panic recovered",
+					transaction.Logger(r.Context(), logger).Error("Panic recovered",
 						zap.Any("error", err),
 						zap.String("method", r.Method),
 						zap.String("path", r.URL.Path),
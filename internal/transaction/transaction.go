@@ -0,0 +1,90 @@
+// Package transaction assigns every request a transaction ID and carries it
+// through the request context, log lines, outbound calls and responses.
+package transaction
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// Header is the HTTP header that carries the transaction ID.
+	Header = "X-Transaction-Id"
+	// MetadataKey is the gRPC metadata key that carries the transaction ID.
+	MetadataKey = "x-transaction-id"
+	// LogField is the zap field name used for the transaction ID.
+	LogField = "transaction_id"
+
+	// maxLength bounds client-supplied IDs so they are safe to log and echo.
+	maxLength = 128
+)
+
+type idKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// FromContext returns the transaction ID stored in ctx, or "" when there is
+// none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+// New returns a fresh transaction ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// Logger returns base with the correlation IDs of ctx attached: the
+// transaction ID and, when ctx carries a span, the trace and span IDs.
+func Logger(ctx context.Context, base *zap.Logger) *zap.Logger {
+	var fields []zap.Field
+	if id := FromContext(ctx); id != "" {
+		fields = append(fields, zap.String(LogField, id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields,
+			zap.String("trace_id", sc.TraceID().String()),
+			zap.String("span_id", sc.SpanID().String()),
+		)
+	}
+	if len(fields) == 0 {
+		return base
+	}
+	return base.With(fields...)
+}
+
+// Middleware takes the transaction ID from the X-Transaction-Id request
+// header, generating a UUIDv4 when it is missing or malformed, stores it in
+// the request context and sets it on the response before the next handler
+// runs, so error responses carry it too.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !valid(id) {
+			id = New()
+		}
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// valid accepts non-empty IDs of printable ASCII without spaces.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,494 @@
+// Package translator maps Beckn-shaped discovery payloads (pkg/models) onto
+// the flat catalog model returned by the BFF (internal/model).
+//
+// Beckn items carry most connector data in free-form attribute maps, so the
+// translation is lenient: anything that cannot be mapped is recorded in a
+// Report instead of failing the whole response.
+package translator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/pricing"
+	"bff-go-mvp/pkg/models"
+)
+
+// IssueReason classifies why a field could not be translated.
+type IssueReason string
+
+const (
+	// ReasonMissing means a field the BFF contract requires was absent.
+	ReasonMissing IssueReason = "missing"
+	// ReasonInvalid means a field was present but had an unusable type or value.
+	ReasonInvalid IssueReason = "invalid"
+	// ReasonUnmapped means an attribute has no counterpart in the BFF model.
+	ReasonUnmapped IssueReason = "unmapped"
+)
+
+// Issue describes a single field that was not translated as-is.
+type Issue struct {
+	Path   string      `json:"path"`
+	Reason IssueReason `json:"reason"`
+	Detail string      `json:"detail,omitempty"`
+}
+
+func (i Issue) String() string {
+	if i.Detail == "" {
+		return fmt.Sprintf("%s: %s", i.Path, i.Reason)
+	}
+	return fmt.Sprintf("%s: %s (%s)", i.Path, i.Reason, i.Detail)
+}
+
+// Report collects the issues found during a translation.
+type Report struct {
+	Issues []Issue `json:"issues,omitempty"`
+}
+
+// HasIssues reports whether anything was dropped, defaulted or rejected.
+func (r *Report) HasIssues() bool {
+	return len(r.Issues) > 0
+}
+
+// Count returns the number of issues with the given reason.
+func (r *Report) Count(reason IssueReason) int {
+	n := 0
+	for _, i := range r.Issues {
+		if i.Reason == reason {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Report) add(path string, reason IssueReason, detail string) {
+	r.Issues = append(r.Issues, Issue{Path: path, Reason: reason, Detail: detail})
+}
+
+// DiscoveryToCatalogs translates every catalog of a discovery response.
+// A nil response yields no catalogs and an empty report.
+func DiscoveryToCatalogs(resp *models.DiscoveryResponse) ([]model.Catalog, *Report) {
+	report := &Report{}
+	if resp == nil {
+		return []model.Catalog{}, report
+	}
+
+	catalogs := make([]model.Catalog, 0, len(resp.Message.Catalogs))
+	for i, c := range resp.Message.Catalogs {
+		catalogs = append(catalogs, translateCatalog(c, fmt.Sprintf("catalogs[%d]", i), report))
+	}
+	return catalogs, report
+}
+
+// CatalogToCatalog translates a single Beckn catalog.
+func CatalogToCatalog(c models.Catalog) (model.Catalog, *Report) {
+	report := &Report{}
+	return translateCatalog(c, "catalog", report), report
+}
+
+func translateCatalog(c models.Catalog, path string, report *Report) model.Catalog {
+	out := model.Catalog{
+		ID: c.ID,
+		Provider: model.Provider{
+			ID:         c.Provider.ID,
+			Descriptor: model.ProviderDescriptor{Name: c.Provider.Descriptor.Name},
+		},
+		AvailablePowerType: []string{},
+		Connectors:         []model.Connector{},
+		Offers:             []model.Offer{},
+	}
+
+	if c.Provider.ID == "" {
+		report.add(path+".beckn:provider.beckn:id", ReasonMissing, "")
+	}
+	if out.ID == "" {
+		out.ID = c.Provider.ID
+		report.add(path+".beckn:id", ReasonMissing, "defaulted to provider id")
+	}
+	if out.Provider.Descriptor.Name == "" {
+		out.Provider.Descriptor.Name = c.Descriptor.Name
+	}
+
+	var ratingSum float64
+	var ratingCount int
+	powerTypes := map[string]bool{}
+
+	for i, item := range c.Items {
+		itemPath := fmt.Sprintf("%s.beckn:items[%d]", path, i)
+
+		if out.Address.GeoCoordinates == nil {
+			if addr, ok := itemAddress(item); ok {
+				out.Address = addr
+			}
+		}
+
+		if item.Rating != nil && item.Rating.Count > 0 {
+			ratingSum += item.Rating.Value * float64(item.Rating.Count)
+			ratingCount += item.Rating.Count
+		}
+
+		connector, windows := translateConnector(item, itemPath, report)
+		out.Connectors = append(out.Connectors, connector)
+		if len(out.AvailabilityWindow) == 0 {
+			out.AvailabilityWindow = windows
+		}
+
+		if pt := connector.ConnectorAttributes.PowerType; pt != "" && !powerTypes[pt] {
+			powerTypes[pt] = true
+			out.AvailablePowerType = append(out.AvailablePowerType, pt)
+		}
+
+		for j, o := range item.Offers {
+			offerPath := fmt.Sprintf("%s.beckn:offers[%d]", itemPath, j)
+			out.Offers = append(out.Offers, translateOffer(o, item.ID, c.Provider.ID, offerPath, report))
+		}
+	}
+
+	if out.Address.GeoCoordinates == nil {
+		out.Address.Name = c.Provider.Address.Full
+		report.add(path+".beckn:items[*].beckn:availableAt.geo", ReasonMissing, "no item carries a GeoJSON point")
+	}
+	if out.Address.Name == "" {
+		out.Address.Name = c.Provider.Address.Full
+	}
+
+	if ratingCount > 0 {
+		out.Rating = &model.Rating{Value: round(ratingSum/float64(ratingCount), 1), Count: ratingCount}
+	}
+
+	return out
+}
+
+// itemAddress returns the first GeoJSON point of an item as [lat, lon].
+func itemAddress(item models.Item) (model.Address, bool) {
+	for _, loc := range item.AvailableAt {
+		if len(loc.Geo.Coordinates) != 2 {
+			continue
+		}
+		return model.Address{
+			Name:           loc.Address.Full,
+			GeoCoordinates: []float64{loc.Geo.Coordinates[1], loc.Geo.Coordinates[0]},
+		}, true
+	}
+	return model.Address{}, false
+}
+
+// requiredConnectorAttributes are mandatory in the BFF search contract.
+var requiredConnectorAttributes = []string{"connectorType", "maxPowerKW", "powerType", "status"}
+
+func translateConnector(item models.Item, path string, report *Report) (model.Connector, []model.AvailabilityWindow) {
+	attrsPath := path + ".beckn:itemAttributes"
+	a := newAttrReader(item.ItemAttributes, attrsPath, report)
+
+	if item.ID == "" {
+		report.add(path+".beckn:id", ReasonMissing, "")
+	}
+	if item.ItemAttributes == nil {
+		report.add(attrsPath, ReasonMissing, "")
+	}
+	for _, key := range requiredConnectorAttributes {
+		if !a.has(key) {
+			report.add(attrsPath+"."+key, ReasonMissing, "")
+		}
+	}
+
+	attrs := model.ConnectorAttributes{
+		ConnectorType:        a.string("connectorType"),
+		MaxPowerKW:           a.float("maxPowerKW"),
+		MinPowerKW:           a.float("minPowerKW"),
+		SocketCount:          a.int("socketCount"),
+		ReservationSupported: a.bool("reservationSupported"),
+		OcppID:               a.string("ocppId"),
+		EvseID:               a.string("evseId"),
+		ParkingType:          a.string("parkingType"),
+		ConnectorID:          a.string("connectorId"),
+		PowerType:            a.string("powerType"),
+		ConnectorFormat:      a.string("connectorFormat"),
+		ChargingSpeed:        a.string("chargingSpeed"),
+		Status:               a.string("status"),
+		AmenityFeature:       a.strings("amenityFeature"),
+		RoamingNetwork:       a.string("roamingNetwork"),
+	}
+
+	isActive := attrs.Status != "OutOfOrder"
+	if a.has("isActive") {
+		isActive = a.bool("isActive")
+	}
+
+	windows := a.windows("availabilityWindow")
+	a.reportUnmapped()
+
+	return model.Connector{
+		ID:                  item.ID,
+		IsActive:            isActive,
+		ConnectorAttributes: attrs,
+	}, windows
+}
+
+func translateOffer(o models.Offer, itemID, providerID, path string, report *Report) model.Offer {
+	if o.ID == "" {
+		report.add(path+".beckn:id", ReasonMissing, "")
+	}
+	if o.Price.Currency == "" {
+		report.add(path+".beckn:price.currency", ReasonMissing, "")
+	}
+
+	out := model.Offer{
+		ID:         o.ID,
+		Descriptor: model.OfferDescriptor{Name: o.Descriptor.Name},
+		Items:      []string{itemID},
+		Price: model.Price{
+			Currency: o.Price.Currency,
+			Value:    o.Price.Value,
+		},
+		AcceptedPaymentMethod: o.AcceptedPaymentMethod,
+		Provider:              providerID,
+	}
+
+	if q := o.Price.ApplicableQuantity; q.UnitCode != "" || q.UnitText != "" || q.UnitQuantity != 0 {
+		out.Price.ApplicableQuantity = &model.ApplicableQuantity{
+			UnitText:     q.UnitText,
+			UnitCode:     q.UnitCode,
+			UnitQuantity: float64(q.UnitQuantity),
+		}
+	}
+
+	if o.Validity.StartDate != "" || o.Validity.EndDate != "" {
+		out.Validity = &model.Validity{StartDate: o.Validity.StartDate, EndDate: o.Validity.EndDate}
+	}
+
+	if len(o.OfferAttributes) > 0 {
+		a := newAttrReader(o.OfferAttributes, path+".beckn:offerAttributes", report)
+		attrs := &model.OfferAttributes{
+			BuyerFinderFee: a.buyerFinderFee("buyerFinderFee"),
+			IdleFeePolicy:  a.string("idleFeePolicy"),
+		}
+		attrs.IdleFee = a.idleFee("idleFeePolicy", attrs.IdleFeePolicy, o.Price.Currency)
+		a.reportUnmapped()
+		if attrs.BuyerFinderFee != nil || attrs.IdleFeePolicy != "" {
+			out.OfferAttributes = attrs
+		}
+	}
+
+	return out
+}
+
+// attrReader reads typed values out of a free-form attribute map, recording
+// which keys were consumed so leftovers can be reported as unmapped.
+type attrReader struct {
+	attrs  map[string]interface{}
+	path   string
+	report *Report
+	used   map[string]bool
+}
+
+func newAttrReader(attrs map[string]interface{}, path string, report *Report) *attrReader {
+	return &attrReader{attrs: attrs, path: path, report: report, used: map[string]bool{}}
+}
+
+func (a *attrReader) has(key string) bool {
+	v, ok := a.attrs[key]
+	return ok && v != nil
+}
+
+func (a *attrReader) lookup(key string) (interface{}, bool) {
+	a.used[key] = true
+	v, ok := a.attrs[key]
+	if !ok || v == nil {
+		return nil, false
+	}
+	return v, true
+}
+
+func (a *attrReader) invalid(key string, v interface{}, want string) {
+	a.report.add(a.path+"."+key, ReasonInvalid, fmt.Sprintf("expected %s, got %T", want, v))
+}
+
+func (a *attrReader) string(key string) string {
+	v, ok := a.lookup(key)
+	if !ok {
+		return ""
+	}
+	switch s := v.(type) {
+	case string:
+		return s
+	case float64, int, bool:
+		return fmt.Sprint(s)
+	}
+	a.invalid(key, v, "string")
+	return ""
+}
+
+func (a *attrReader) float(key string) float64 {
+	v, ok := a.lookup(key)
+	if !ok {
+		return 0
+	}
+	if f, ok := toFloat(v); ok {
+		return f
+	}
+	a.invalid(key, v, "number")
+	return 0
+}
+
+func (a *attrReader) int(key string) int {
+	v, ok := a.lookup(key)
+	if !ok {
+		return 0
+	}
+	if f, ok := toFloat(v); ok && f == float64(int(f)) {
+		return int(f)
+	}
+	a.invalid(key, v, "integer")
+	return 0
+}
+
+func (a *attrReader) bool(key string) bool {
+	v, ok := a.lookup(key)
+	if !ok {
+		return false
+	}
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		switch strings.ToLower(b) {
+		case "true", "yes":
+			return true
+		case "false", "no":
+			return false
+		}
+	}
+	a.invalid(key, v, "boolean")
+	return false
+}
+
+func (a *attrReader) strings(key string) []string {
+	v, ok := a.lookup(key)
+	if !ok {
+		return nil
+	}
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		out := make([]string, 0, len(list))
+		for _, e := range list {
+			s, ok := e.(string)
+			if !ok {
+				a.invalid(key, e, "string element")
+				continue
+			}
+			out = append(out, s)
+		}
+		return out
+	case string:
+		return []string{list}
+	}
+	a.invalid(key, v, "string list")
+	return nil
+}
+
+func (a *attrReader) windows(key string) []model.AvailabilityWindow {
+	v, ok := a.lookup(key)
+	if !ok {
+		return nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		list = []interface{}{v}
+	}
+	var out []model.AvailabilityWindow
+	for _, e := range list {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			a.invalid(key, e, "object with startTime/endTime")
+			continue
+		}
+		start, _ := m["startTime"].(string)
+		end, _ := m["endTime"].(string)
+		if start == "" || end == "" {
+			a.invalid(key, e, "object with startTime/endTime")
+			continue
+		}
+		out = append(out, model.AvailabilityWindow{StartTime: start, EndTime: end})
+	}
+	return out
+}
+
+func (a *attrReader) buyerFinderFee(key string) *model.BuyerFinderFee {
+	v, ok := a.lookup(key)
+	if !ok {
+		return nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		a.invalid(key, v, "object")
+		return nil
+	}
+	feeType, _ := m["feeType"].(string)
+	feeValue, ok := toFloat(m["feeValue"])
+	if feeType == "" || !ok {
+		a.invalid(key, v, "object with feeType/feeValue")
+		return nil
+	}
+	return &model.BuyerFinderFee{FeeType: feeType, FeeValue: feeValue}
+}
+
+// idleFee parses the idle fee policy read from key, reporting policies that
+// cannot be parsed. The legacy string is kept either way.
+func (a *attrReader) idleFee(key, policy, currency string) *model.IdleFee {
+	if policy == "" {
+		return nil
+	}
+	fee, err := pricing.ParseIdleFee(policy, currency)
+	if err != nil {
+		a.report.add(a.path+"."+key, ReasonInvalid, err.Error())
+		return nil
+	}
+	return &fee
+}
+
+// reportUnmapped records every attribute that was never read, skipping
+// JSON-LD keywords such as @context and @type.
+func (a *attrReader) reportUnmapped() {
+	keys := make([]string, 0, len(a.attrs))
+	for k := range a.attrs {
+		if !a.used[k] && !strings.HasPrefix(k, "@") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		a.report.add(a.path+"."+k, ReasonUnmapped, "")
+	}
+}
+
+// toFloat reads a number or numeric string. ParseFloat also accepts "NaN"
+// and "Inf", which no attribute can mean, so non-finite values are invalid.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,119 @@
+package vehicles
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+)
+
+// DefaultStateOfCharge is the battery level in percent a session is assumed
+// to start from when the request does not give one.
+const DefaultStateOfCharge = 20
+
+// socStep is the state of charge resolution of a Charge, in percent.
+const socStep = 0.1
+
+// Charge models charging one vehicle on one connector from a starting state
+// of charge. At each state of charge the power is the lowest of the
+// vehicle's charging curve, its AC or DC limit and the connector's rated
+// power. Charging losses are ignored. Charge implements pricing.Profile.
+type Charge struct {
+	spec     model.VehicleSpec
+	startSoC float64
+	powerKW  float64
+	// elapsed[i] is the minutes taken to charge i socSteps from startSoC.
+	elapsed []float64
+}
+
+// NewCharge models charging spec from startSoC percent on a connector of
+// connectorKW. dc selects the vehicle's DC power limit instead of AC.
+func NewCharge(spec model.VehicleSpec, connectorKW float64, dc bool, startSoC float64) (*Charge, error) {
+	if startSoC < 0 || startSoC >= 100 || math.IsNaN(startSoC) {
+		return nil, apperror.Validation("state_of_charge must be at least 0 and below 100").
+			WithDetail("field", "state_of_charge")
+	}
+	limit, current := spec.MaxACPowerKW, "AC"
+	if dc {
+		limit, current = spec.MaxDCPowerKW, "DC"
+	}
+	if limit <= 0 {
+		return nil, apperror.Validation(fmt.Sprintf("vehicle %q cannot charge on %s connectors", spec.ID, current)).
+			WithDetail("field", "connector_id")
+	}
+	if !(connectorKW > 0) {
+		return nil, apperror.Validation("connector has no rated power to estimate the session").
+			WithDetail("field", "connector_id")
+	}
+
+	c := &Charge{spec: spec, startSoC: startSoC, powerKW: math.Min(limit, connectorKW)}
+	steps := int(math.Ceil((100 - startSoC) / socStep))
+	c.elapsed = make([]float64, steps+1)
+	for i := 1; i <= steps; i++ {
+		from := startSoC + float64(i-1)*socStep
+		to := math.Min(from+socStep, 100)
+		kwh := (to - from) / 100 * spec.UsableBatteryKWh
+		c.elapsed[i] = c.elapsed[i-1] + kwh/c.power((from+to)/2)*60
+	}
+	return c, nil
+}
+
+// power returns the charging power at soc percent.
+func (c *Charge) power(soc float64) float64 {
+	return math.Min(c.powerKW, curvePower(c.spec.ChargingCurve, soc))
+}
+
+// curvePower interpolates the charging curve at soc, holding the end points
+// flat beyond the curve.
+func curvePower(curve []model.ChargingCurvePoint, soc float64) float64 {
+	i := sort.Search(len(curve), func(i int) bool { return curve[i].SoC >= soc })
+	switch {
+	case i == 0:
+		return curve[0].PowerKW
+	case i == len(curve):
+		return curve[len(curve)-1].PowerKW
+	}
+	a, b := curve[i-1], curve[i]
+	return a.PowerKW + (b.PowerKW-a.PowerKW)*(soc-a.SoC)/(b.SoC-a.SoC)
+}
+
+// Minutes returns the time to deliver kwh; energy beyond a full battery is
+// not counted.
+func (c *Charge) Minutes(kwh float64) float64 {
+	x := kwh / c.spec.UsableBatteryKWh * 100 / socStep
+	i := int(math.Floor(x))
+	if i >= len(c.elapsed)-1 {
+		return c.elapsed[len(c.elapsed)-1]
+	}
+	if i < 0 {
+		return 0
+	}
+	return c.elapsed[i] + (c.elapsed[i+1]-c.elapsed[i])*(x-float64(i))
+}
+
+// Energy returns the kWh delivered in minutes, up to a full battery.
+func (c *Charge) Energy(minutes float64) float64 {
+	last := len(c.elapsed) - 1
+	if minutes >= c.elapsed[last] {
+		return c.MaxEnergy()
+	}
+	if minutes <= 0 {
+		return 0
+	}
+	i := sort.SearchFloat64s(c.elapsed, minutes) - 1
+	x := float64(i) + (minutes-c.elapsed[i])/(c.elapsed[i+1]-c.elapsed[i])
+	return x * socStep / 100 * c.spec.UsableBatteryKWh
+}
+
+// MaxEnergy returns the kWh that fill the battery from the starting state of
+// charge.
+func (c *Charge) MaxEnergy() float64 {
+	return (100 - c.startSoC) / 100 * c.spec.UsableBatteryKWh
+}
+
+// SoCGain returns how many percentage points kwh adds to the battery.
+func (c *Charge) SoCGain(kwh float64) float64 {
+	return math.Min(kwh/c.spec.UsableBatteryKWh*100, 100-c.startSoC)
+}
@@ -0,0 +1,183 @@
+// Package vehicles holds the vehicle registry: battery capacity, supported
+// connectors, charge power limits and charging curves of known vehicles,
+// with per-type defaults for vehicles that are not listed.
+package vehicles
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+)
+
+// Vehicle types. A vehicle without a type is a 4-wheeler.
+const (
+	TypeTwoWheeler   = "2-wheeler"
+	TypeThreeWheeler = "3-wheeler"
+	TypeFourWheeler  = "4-wheeler"
+)
+
+var types = []string{TypeTwoWheeler, TypeThreeWheeler, TypeFourWheeler}
+
+//go:embed vehicles.json
+var builtin []byte
+
+// file is the registry file format.
+type file struct {
+	// Defaults has one spec per vehicle type.
+	Defaults []model.VehicleSpec `json:"defaults"`
+	Vehicles []model.VehicleSpec `json:"vehicles"`
+}
+
+// Registry looks up vehicle specs. It is immutable and safe for concurrent
+// use.
+type Registry struct {
+	vehicles []model.VehicleSpec
+	defaults map[string]model.VehicleSpec
+}
+
+// Default returns the registry built into the binary.
+func Default() *Registry {
+	r, err := Parse(builtin)
+	if err != nil {
+		panic(fmt.Sprintf("vehicles: built-in registry: %v", err))
+	}
+	return r
+}
+
+// Load reads a registry file; see Parse.
+func Load(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read vehicles: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes a registry: a JSON object with "vehicles", the known
+// vehicles, and "defaults", one spec for each vehicle type.
+func Parse(data []byte) (*Registry, error) {
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decode vehicles: %w", err)
+	}
+
+	r := &Registry{defaults: make(map[string]model.VehicleSpec)}
+	ids := make(map[string]bool)
+	for _, spec := range append(f.Defaults, f.Vehicles...) {
+		if err := validate(spec); err != nil {
+			return nil, fmt.Errorf("vehicle %q: %w", spec.ID, err)
+		}
+		if ids[spec.ID] {
+			return nil, fmt.Errorf("vehicle %q: duplicate id", spec.ID)
+		}
+		ids[spec.ID] = true
+	}
+	for _, spec := range f.Defaults {
+		r.defaults[spec.Type] = spec
+	}
+	for _, t := range types {
+		if _, ok := r.defaults[t]; !ok {
+			return nil, fmt.Errorf("decode vehicles: no default for %s", t)
+		}
+	}
+	r.vehicles = f.Vehicles
+	return r, nil
+}
+
+func validate(spec model.VehicleSpec) error {
+	switch {
+	case spec.ID == "":
+		return fmt.Errorf("id is required")
+	case !isType(spec.Type):
+		return fmt.Errorf("unknown type %q", spec.Type)
+	case !(spec.UsableBatteryKWh > 0):
+		return fmt.Errorf("usableBatteryKWh must be positive")
+	case spec.MaxACPowerKW < 0 || spec.MaxDCPowerKW < 0 || spec.MaxACPowerKW+spec.MaxDCPowerKW == 0:
+		return fmt.Errorf("needs a positive maxACPowerKW or maxDCPowerKW")
+	case len(spec.Connectors) == 0:
+		return fmt.Errorf("connectors are required")
+	case len(spec.ChargingCurve) == 0:
+		return fmt.Errorf("chargingCurve is required")
+	}
+	for i, p := range spec.ChargingCurve {
+		if p.SoC < 0 || p.SoC > 100 || (i > 0 && p.SoC <= spec.ChargingCurve[i-1].SoC) {
+			return fmt.Errorf("chargingCurve must have increasing soc between 0 and 100")
+		}
+		if !(p.PowerKW > 0) {
+			return fmt.Errorf("chargingCurve power must be positive")
+		}
+	}
+	return nil
+}
+
+// Types returns the vehicle types.
+func Types() []string {
+	return append([]string(nil), types...)
+}
+
+func isType(t string) bool {
+	for _, v := range types {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the known vehicles, optionally narrowed to a make and a type
+// (both case-insensitive), ordered by make and model.
+func (r *Registry) List(vehicleMake, vehicleType string) []model.VehicleSpec {
+	out := []model.VehicleSpec{}
+	for _, v := range r.vehicles {
+		if vehicleMake != "" && !strings.EqualFold(v.Make, vehicleMake) {
+			continue
+		}
+		if vehicleType != "" && !strings.EqualFold(v.Type, vehicleType) {
+			continue
+		}
+		out = append(out, v)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !strings.EqualFold(out[i].Make, out[j].Make) {
+			return strings.ToLower(out[i].Make) < strings.ToLower(out[j].Make)
+		}
+		return strings.ToLower(out[i].Model) < strings.ToLower(out[j].Model)
+	})
+	return out
+}
+
+// Lookup returns the spec of a known vehicle, matched by make and model
+// case-insensitively, or else the default for its type. An unknown type is
+// a validation error.
+func (r *Registry) Lookup(v model.Vehicle) (model.VehicleSpec, error) {
+	if v.Make != "" && v.Model != "" {
+		for _, spec := range r.vehicles {
+			if sameName(spec.Make, v.Make) && sameName(spec.Model, v.Model) {
+				return spec, nil
+			}
+		}
+	}
+
+	t := strings.ToLower(strings.TrimSpace(v.Type))
+	if t == "" {
+		t = TypeFourWheeler
+	}
+	spec, ok := r.defaults[t]
+	if !ok {
+		return model.VehicleSpec{}, apperror.Validation(fmt.Sprintf("Unsupported vehicle.type %q", v.Type)).
+			WithDetail("field", "vehicle.type").
+			WithDetail("allowed", types)
+	}
+	return spec, nil
+}
+
+// sameName compares names ignoring case and repeated spaces.
+func sameName(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
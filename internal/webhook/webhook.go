@@ -0,0 +1,190 @@
+// Package webhook signs and verifies provider webhooks and deduplicates
+// their events.
+//
+// A webhook is signed with the provider's shared secret: the
+// X-Webhook-Signature header is "t=<unix seconds>,v1=<hex HMAC-SHA256>", the
+// HMAC being of "<t>.<body>". The timestamp is part of the signed payload,
+// so a captured request cannot be replayed once it falls outside the
+// tolerance, and replays within it are caught by event ID.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"bff-go-mvp/internal/apperror"
+)
+
+// SignatureHeader carries a webhook's signature.
+const SignatureHeader = "X-Webhook-Signature"
+
+// Error codes of rejected webhooks.
+const (
+	CodeInvalidSignature = "INVALID_SIGNATURE"
+	CodeStaleEvent       = "STALE_EVENT"
+)
+
+// Sign returns the signature header value of body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + mac(secret, ts, body)
+}
+
+func mac(secret, ts string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verifier checks webhook signatures against per-provider secrets.
+type Verifier struct {
+	secrets   map[string]string
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewVerifier returns a verifier for the providers in secrets, accepting
+// signatures made up to tolerance before or after now.
+func NewVerifier(secrets map[string]string, tolerance time.Duration) *Verifier {
+	return &Verifier{secrets: secrets, tolerance: tolerance, now: time.Now}
+}
+
+// Verify checks that body was signed by provider within the tolerance.
+// Unknown providers are NOT_FOUND; bad and stale signatures UNAUTHORIZED.
+func (v *Verifier) Verify(provider, header string, body []byte) error {
+	secret, ok := v.secrets[provider]
+	if !ok || secret == "" {
+		return apperror.NotFound("Unknown payment provider.").WithDetail("provider", provider)
+	}
+
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = val
+		case "v1":
+			sigs = append(sigs, val)
+		}
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return invalidSignature("Missing or malformed " + SignatureHeader + " header.")
+	}
+
+	want := mac(secret, ts, body)
+	valid := false
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(want)) {
+			valid = true
+		}
+	}
+	if !valid {
+		return invalidSignature("Webhook signature does not match.")
+	}
+
+	age := v.now().Sub(time.Unix(sec, 0))
+	if age > v.tolerance || age < -v.tolerance {
+		return apperror.Unauthorized("Webhook timestamp is outside the allowed tolerance.").
+			WithCode(CodeStaleEvent).
+			WithDetail("tolerance_seconds", int(v.tolerance.Seconds()))
+	}
+	return nil
+}
+
+func invalidSignature(message string) *apperror.Error {
+	return apperror.Unauthorized(message).WithCode(CodeInvalidSignature)
+}
+
+// Deduper remembers processed event IDs for a retention period, so that
+// redelivered and replayed events are processed once.
+type Deduper struct {
+	retention time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	done     map[string]time.Time
+	inFlight map[string]bool
+	// expiries lists processed events oldest first. Every event is kept for
+	// the same retention, so they expire in this order and Begin only looks
+	// at the front.
+	expiries []expiry
+}
+
+type expiry struct {
+	key string
+	at  time.Time
+}
+
+// MinRetention is how long processed events are remembered at least, to
+// catch redeliveries of an event signed afresh.
+const MinRetention = 24 * time.Hour
+
+// Retention returns how long a deduper must remember events accepted by a
+// verifier with tolerance: one signature stays valid for twice the tolerance,
+// and an event forgotten earlier could be replayed and processed again.
+func Retention(tolerance time.Duration) time.Duration {
+	return max(MinRetention, 2*tolerance)
+}
+
+func NewDeduper(retention time.Duration) *Deduper {
+	return &Deduper{
+		retention: retention,
+		now:       time.Now,
+		done:      map[string]time.Time{},
+		inFlight:  map[string]bool{},
+	}
+}
+
+// Event states reported by Begin.
+const (
+	New        = "new"
+	Duplicate  = "duplicate"
+	InProgress = "in_progress"
+)
+
+// Begin claims key for processing. It reports New when the caller should
+// process the event and then call Done, Duplicate when it was processed
+// already, and InProgress when another delivery is processing it.
+func (d *Deduper) Begin(key string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	for len(d.expiries) > 0 && now.Sub(d.expiries[0].at) > d.retention {
+		e := d.expiries[0]
+		if d.done[e.key].Equal(e.at) {
+			delete(d.done, e.key)
+		}
+		d.expiries = d.expiries[1:]
+	}
+	switch {
+	case d.inFlight[key]:
+		return InProgress
+	case !d.done[key].IsZero():
+		return Duplicate
+	}
+	d.inFlight[key] = true
+	return New
+}
+
+// Done releases key. A processed event is remembered; one that failed may
+// be delivered again.
+func (d *Deduper) Done(key string, processed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.inFlight, key)
+	if processed {
+		at := d.now()
+		d.done[key] = at
+		d.expiries = append(d.expiries, expiry{key: key, at: at})
+	}
+}
@@ -41,13 +41,51 @@ type City struct {
 
 // Message represents the message payload
 type Message struct {
+	Intent   *Intent   `json:"intent,omitempty"`
 	Catalogs []Catalog `json:"catalogs"`
 }
 
+// Intent represents the buyer's search criteria on a discover request
+type Intent struct {
+	EvseID     string         `json:"evse_id,omitempty"`
+	Circle     *Circle        `json:"circle,omitempty"`
+	TimeWindow *TimeWindow    `json:"time_window,omitempty"`
+	Filters    *IntentFilters `json:"filters,omitempty"`
+}
+
+// Circle represents a search radius around a GeoJSON point
+type Circle struct {
+	Gps          Geo     `json:"gps"`
+	RadiusMeters float64 `json:"radius_meters"`
+}
+
+// TimeWindow represents a requested availability window
+type TimeWindow struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// IntentFilters represents optional connector filters on a discover request
+type IntentFilters struct {
+	CPO           string         `json:"cpo,omitempty"`
+	ConnectorType string         `json:"connector_type,omitempty"`
+	MinPowerKW    float64        `json:"min_power_kw,omitempty"`
+	Amenities     []string       `json:"amenities,omitempty"`
+	Vehicle       *IntentVehicle `json:"vehicle,omitempty"`
+}
+
+// IntentVehicle represents the buyer's vehicle on a discover request
+type IntentVehicle struct {
+	Make  string `json:"make,omitempty"`
+	Model string `json:"model,omitempty"`
+	Type  string `json:"type,omitempty"`
+}
+
 // Catalog represents a catalog of items
 type Catalog struct {
 	Context    string     `json:"@context"`
 	Type       string     `json:"@type"`
+	ID         string     `json:"beckn:id,omitempty"`
 	Descriptor Descriptor `json:"beckn:descriptor"`
 	Provider   Provider   `json:"beckn:provider"`
 	Items      []Item     `json:"beckn:items"`
@@ -166,4 +204,3 @@ type DiscoveryResponse struct {
 	Context Context `json:"context"`
 	Message Message `json:"message"`
 }
-
@@ -0,0 +1,2037 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.28.3
+// source: discovery.proto
+
+package discoverypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DiscoveryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       *Context               `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Message       *Message               `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoveryRequest) Reset() {
+	*x = DiscoveryRequest{}
+	mi := &file_discovery_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoveryRequest) ProtoMessage() {}
+
+func (x *DiscoveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoveryRequest.ProtoReflect.Descriptor instead.
+func (*DiscoveryRequest) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DiscoveryRequest) GetContext() *Context {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *DiscoveryRequest) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type DiscoveryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       *Context               `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Message       *Message               `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoveryResponse) Reset() {
+	*x = DiscoveryResponse{}
+	mi := &file_discovery_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoveryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoveryResponse) ProtoMessage() {}
+
+func (x *DiscoveryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoveryResponse.ProtoReflect.Descriptor instead.
+func (*DiscoveryResponse) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DiscoveryResponse) GetContext() *Context {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *DiscoveryResponse) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type Context struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	Location      *Location              `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	BapId         string                 `protobuf:"bytes,5,opt,name=bap_id,json=bapId,proto3" json:"bap_id,omitempty"`
+	BapUri        string                 `protobuf:"bytes,6,opt,name=bap_uri,json=bapUri,proto3" json:"bap_uri,omitempty"`
+	BppId         string                 `protobuf:"bytes,7,opt,name=bpp_id,json=bppId,proto3" json:"bpp_id,omitempty"`
+	BppUri        string                 `protobuf:"bytes,8,opt,name=bpp_uri,json=bppUri,proto3" json:"bpp_uri,omitempty"`
+	TransactionId string                 `protobuf:"bytes,9,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,10,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,11,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Ttl           string                 `protobuf:"bytes,12,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	SchemaContext []string               `protobuf:"bytes,13,rep,name=schema_context,json=schemaContext,proto3" json:"schema_context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Context) Reset() {
+	*x = Context{}
+	mi := &file_discovery_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Context) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Context) ProtoMessage() {}
+
+func (x *Context) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Context.ProtoReflect.Descriptor instead.
+func (*Context) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Context) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Context) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *Context) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Context) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Context) GetBapId() string {
+	if x != nil {
+		return x.BapId
+	}
+	return ""
+}
+
+func (x *Context) GetBapUri() string {
+	if x != nil {
+		return x.BapUri
+	}
+	return ""
+}
+
+func (x *Context) GetBppId() string {
+	if x != nil {
+		return x.BppId
+	}
+	return ""
+}
+
+func (x *Context) GetBppUri() string {
+	if x != nil {
+		return x.BppUri
+	}
+	return ""
+}
+
+func (x *Context) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *Context) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *Context) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *Context) GetTtl() string {
+	if x != nil {
+		return x.Ttl
+	}
+	return ""
+}
+
+func (x *Context) GetSchemaContext() []string {
+	if x != nil {
+		return x.SchemaContext
+	}
+	return nil
+}
+
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Country       *Country               `protobuf:"bytes,1,opt,name=country,proto3" json:"country,omitempty"`
+	City          *City                  `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_discovery_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Location) GetCountry() *Country {
+	if x != nil {
+		return x.Country
+	}
+	return nil
+}
+
+func (x *Location) GetCity() *City {
+	if x != nil {
+		return x.City
+	}
+	return nil
+}
+
+type Country struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Country) Reset() {
+	*x = Country{}
+	mi := &file_discovery_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Country) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Country) ProtoMessage() {}
+
+func (x *Country) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Country.ProtoReflect.Descriptor instead.
+func (*Country) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Country) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type City struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *City) Reset() {
+	*x = City{}
+	mi := &file_discovery_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *City) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*City) ProtoMessage() {}
+
+func (x *City) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use City.ProtoReflect.Descriptor instead.
+func (*City) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *City) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type State struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *State) Reset() {
+	*x = State{}
+	mi := &file_discovery_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *State) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*State) ProtoMessage() {}
+
+func (x *State) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use State.ProtoReflect.Descriptor instead.
+func (*State) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *State) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type Message struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Intent        *Intent                `protobuf:"bytes,1,opt,name=intent,proto3" json:"intent,omitempty"`
+	Catalogs      []*Catalog             `protobuf:"bytes,2,rep,name=catalogs,proto3" json:"catalogs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_discovery_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Message) GetIntent() *Intent {
+	if x != nil {
+		return x.Intent
+	}
+	return nil
+}
+
+func (x *Message) GetCatalogs() []*Catalog {
+	if x != nil {
+		return x.Catalogs
+	}
+	return nil
+}
+
+// Intent carries the buyer's search criteria on a discover request.
+type Intent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EvseId        string                 `protobuf:"bytes,1,opt,name=evse_id,json=evseId,proto3" json:"evse_id,omitempty"`
+	Circle        *Circle                `protobuf:"bytes,2,opt,name=circle,proto3" json:"circle,omitempty"`
+	TimeWindow    *TimeWindow            `protobuf:"bytes,3,opt,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
+	Filters       *IntentFilters         `protobuf:"bytes,4,opt,name=filters,proto3" json:"filters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Intent) Reset() {
+	*x = Intent{}
+	mi := &file_discovery_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Intent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Intent) ProtoMessage() {}
+
+func (x *Intent) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Intent.ProtoReflect.Descriptor instead.
+func (*Intent) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Intent) GetEvseId() string {
+	if x != nil {
+		return x.EvseId
+	}
+	return ""
+}
+
+func (x *Intent) GetCircle() *Circle {
+	if x != nil {
+		return x.Circle
+	}
+	return nil
+}
+
+func (x *Intent) GetTimeWindow() *TimeWindow {
+	if x != nil {
+		return x.TimeWindow
+	}
+	return nil
+}
+
+func (x *Intent) GetFilters() *IntentFilters {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+type Circle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gps           *Geo                   `protobuf:"bytes,1,opt,name=gps,proto3" json:"gps,omitempty"`
+	RadiusMeters  float64                `protobuf:"fixed64,2,opt,name=radius_meters,json=radiusMeters,proto3" json:"radius_meters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Circle) Reset() {
+	*x = Circle{}
+	mi := &file_discovery_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Circle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Circle) ProtoMessage() {}
+
+func (x *Circle) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Circle.ProtoReflect.Descriptor instead.
+func (*Circle) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Circle) GetGps() *Geo {
+	if x != nil {
+		return x.Gps
+	}
+	return nil
+}
+
+func (x *Circle) GetRadiusMeters() float64 {
+	if x != nil {
+		return x.RadiusMeters
+	}
+	return 0
+}
+
+type TimeWindow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         string                 `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeWindow) Reset() {
+	*x = TimeWindow{}
+	mi := &file_discovery_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeWindow) ProtoMessage() {}
+
+func (x *TimeWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeWindow.ProtoReflect.Descriptor instead.
+func (*TimeWindow) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TimeWindow) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *TimeWindow) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type IntentFilters struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cpo           string                 `protobuf:"bytes,1,opt,name=cpo,proto3" json:"cpo,omitempty"`
+	ConnectorType string                 `protobuf:"bytes,2,opt,name=connector_type,json=connectorType,proto3" json:"connector_type,omitempty"`
+	MinPowerKw    float64                `protobuf:"fixed64,3,opt,name=min_power_kw,json=minPowerKw,proto3" json:"min_power_kw,omitempty"`
+	Amenities     []string               `protobuf:"bytes,4,rep,name=amenities,proto3" json:"amenities,omitempty"`
+	Vehicle       *Vehicle               `protobuf:"bytes,5,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntentFilters) Reset() {
+	*x = IntentFilters{}
+	mi := &file_discovery_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntentFilters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntentFilters) ProtoMessage() {}
+
+func (x *IntentFilters) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntentFilters.ProtoReflect.Descriptor instead.
+func (*IntentFilters) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *IntentFilters) GetCpo() string {
+	if x != nil {
+		return x.Cpo
+	}
+	return ""
+}
+
+func (x *IntentFilters) GetConnectorType() string {
+	if x != nil {
+		return x.ConnectorType
+	}
+	return ""
+}
+
+func (x *IntentFilters) GetMinPowerKw() float64 {
+	if x != nil {
+		return x.MinPowerKw
+	}
+	return 0
+}
+
+func (x *IntentFilters) GetAmenities() []string {
+	if x != nil {
+		return x.Amenities
+	}
+	return nil
+}
+
+func (x *IntentFilters) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+type Vehicle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Make          string                 `protobuf:"bytes,1,opt,name=make,proto3" json:"make,omitempty"`
+	Model         string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vehicle) Reset() {
+	*x = Vehicle{}
+	mi := &file_discovery_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vehicle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vehicle) ProtoMessage() {}
+
+func (x *Vehicle) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vehicle.ProtoReflect.Descriptor instead.
+func (*Vehicle) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Vehicle) GetMake() string {
+	if x != nil {
+		return x.Make
+	}
+	return ""
+}
+
+func (x *Vehicle) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Vehicle) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type Catalog struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Descriptor_   *Descriptor            `protobuf:"bytes,4,opt,name=descriptor,proto3" json:"descriptor,omitempty"`
+	Provider      *Provider              `protobuf:"bytes,5,opt,name=provider,proto3" json:"provider,omitempty"`
+	Items         []*Item                `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Catalog) Reset() {
+	*x = Catalog{}
+	mi := &file_discovery_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Catalog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Catalog) ProtoMessage() {}
+
+func (x *Catalog) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Catalog.ProtoReflect.Descriptor instead.
+func (*Catalog) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Catalog) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *Catalog) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Catalog) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Catalog) GetDescriptor_() *Descriptor {
+	if x != nil {
+		return x.Descriptor_
+	}
+	return nil
+}
+
+func (x *Catalog) GetProvider() *Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return nil
+}
+
+func (x *Catalog) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type Descriptor struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ShortDesc     string                 `protobuf:"bytes,2,opt,name=short_desc,json=shortDesc,proto3" json:"short_desc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Descriptor) Reset() {
+	*x = Descriptor{}
+	mi := &file_discovery_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Descriptor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Descriptor) ProtoMessage() {}
+
+func (x *Descriptor) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Descriptor.ProtoReflect.Descriptor instead.
+func (*Descriptor) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Descriptor) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Descriptor) GetShortDesc() string {
+	if x != nil {
+		return x.ShortDesc
+	}
+	return ""
+}
+
+type Provider struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Descriptor_   *Descriptor            `protobuf:"bytes,4,opt,name=descriptor,proto3" json:"descriptor,omitempty"`
+	Address       *Address               `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	Contact       []*Contact             `protobuf:"bytes,6,rep,name=contact,proto3" json:"contact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Provider) Reset() {
+	*x = Provider{}
+	mi := &file_discovery_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Provider) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Provider) ProtoMessage() {}
+
+func (x *Provider) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Provider.ProtoReflect.Descriptor instead.
+func (*Provider) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Provider) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *Provider) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Provider) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Provider) GetDescriptor_() *Descriptor {
+	if x != nil {
+		return x.Descriptor_
+	}
+	return nil
+}
+
+func (x *Provider) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *Provider) GetContact() []*Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AreaCode      string                 `protobuf:"bytes,1,opt,name=area_code,json=areaCode,proto3" json:"area_code,omitempty"`
+	City          *City                  `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	State         *State                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Country       *Country               `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
+	Full          string                 `protobuf:"bytes,5,opt,name=full,proto3" json:"full,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_discovery_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Address) GetAreaCode() string {
+	if x != nil {
+		return x.AreaCode
+	}
+	return ""
+}
+
+func (x *Address) GetCity() *City {
+	if x != nil {
+		return x.City
+	}
+	return nil
+}
+
+func (x *Address) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *Address) GetCountry() *Country {
+	if x != nil {
+		return x.Country
+	}
+	return nil
+}
+
+func (x *Address) GetFull() string {
+	if x != nil {
+		return x.Full
+	}
+	return ""
+}
+
+type Contact struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Phone         string                 `protobuf:"bytes,1,opt,name=phone,proto3" json:"phone,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Contact) Reset() {
+	*x = Contact{}
+	mi := &file_discovery_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Contact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contact) ProtoMessage() {}
+
+func (x *Contact) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contact.ProtoReflect.Descriptor instead.
+func (*Contact) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Contact) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Contact) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type Item struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Context        string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Type           string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Id             string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Descriptor_    *Descriptor            `protobuf:"bytes,4,opt,name=descriptor,proto3" json:"descriptor,omitempty"`
+	Category       *Category              `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
+	ItemAttributes *structpb.Struct       `protobuf:"bytes,6,opt,name=item_attributes,json=itemAttributes,proto3" json:"item_attributes,omitempty"`
+	AvailableAt    []*LocationItem        `protobuf:"bytes,7,rep,name=available_at,json=availableAt,proto3" json:"available_at,omitempty"`
+	Offers         []*Offer               `protobuf:"bytes,8,rep,name=offers,proto3" json:"offers,omitempty"`
+	Rating         *Rating                `protobuf:"bytes,9,opt,name=rating,proto3" json:"rating,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Item) Reset() {
+	*x = Item{}
+	mi := &file_discovery_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Item) ProtoMessage() {}
+
+func (x *Item) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Item.ProtoReflect.Descriptor instead.
+func (*Item) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Item) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *Item) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Item) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Item) GetDescriptor_() *Descriptor {
+	if x != nil {
+		return x.Descriptor_
+	}
+	return nil
+}
+
+func (x *Item) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+func (x *Item) GetItemAttributes() *structpb.Struct {
+	if x != nil {
+		return x.ItemAttributes
+	}
+	return nil
+}
+
+func (x *Item) GetAvailableAt() []*LocationItem {
+	if x != nil {
+		return x.AvailableAt
+	}
+	return nil
+}
+
+func (x *Item) GetOffers() []*Offer {
+	if x != nil {
+		return x.Offers
+	}
+	return nil
+}
+
+func (x *Item) GetRating() *Rating {
+	if x != nil {
+		return x.Rating
+	}
+	return nil
+}
+
+type Category struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Descriptor_   *Descriptor            `protobuf:"bytes,3,opt,name=descriptor,proto3" json:"descriptor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Category) Reset() {
+	*x = Category{}
+	mi := &file_discovery_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Category) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Category) ProtoMessage() {}
+
+func (x *Category) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Category.ProtoReflect.Descriptor instead.
+func (*Category) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Category) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Category) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Category) GetDescriptor_() *Descriptor {
+	if x != nil {
+		return x.Descriptor_
+	}
+	return nil
+}
+
+type LocationItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Geo           *Geo                   `protobuf:"bytes,2,opt,name=geo,proto3" json:"geo,omitempty"`
+	Address       *Address               `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationItem) Reset() {
+	*x = LocationItem{}
+	mi := &file_discovery_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationItem) ProtoMessage() {}
+
+func (x *LocationItem) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationItem.ProtoReflect.Descriptor instead.
+func (*LocationItem) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *LocationItem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *LocationItem) GetGeo() *Geo {
+	if x != nil {
+		return x.Geo
+	}
+	return nil
+}
+
+func (x *LocationItem) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+// Geo is a GeoJSON geometry; coordinates are [longitude, latitude].
+type Geo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Coordinates   []float64              `protobuf:"fixed64,2,rep,packed,name=coordinates,proto3" json:"coordinates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Geo) Reset() {
+	*x = Geo{}
+	mi := &file_discovery_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Geo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Geo) ProtoMessage() {}
+
+func (x *Geo) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Geo.ProtoReflect.Descriptor instead.
+func (*Geo) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Geo) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Geo) GetCoordinates() []float64 {
+	if x != nil {
+		return x.Coordinates
+	}
+	return nil
+}
+
+type Offer struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Context               string                 `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Type                  string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Id                    string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Descriptor_           *Descriptor            `protobuf:"bytes,4,opt,name=descriptor,proto3" json:"descriptor,omitempty"`
+	Price                 *Price                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	Validity              *Validity              `protobuf:"bytes,6,opt,name=validity,proto3" json:"validity,omitempty"`
+	AcceptedPaymentMethod []string               `protobuf:"bytes,7,rep,name=accepted_payment_method,json=acceptedPaymentMethod,proto3" json:"accepted_payment_method,omitempty"`
+	OfferAttributes       *structpb.Struct       `protobuf:"bytes,8,opt,name=offer_attributes,json=offerAttributes,proto3" json:"offer_attributes,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Offer) Reset() {
+	*x = Offer{}
+	mi := &file_discovery_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Offer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Offer) ProtoMessage() {}
+
+func (x *Offer) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Offer.ProtoReflect.Descriptor instead.
+func (*Offer) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Offer) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *Offer) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Offer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Offer) GetDescriptor_() *Descriptor {
+	if x != nil {
+		return x.Descriptor_
+	}
+	return nil
+}
+
+func (x *Offer) GetPrice() *Price {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *Offer) GetValidity() *Validity {
+	if x != nil {
+		return x.Validity
+	}
+	return nil
+}
+
+func (x *Offer) GetAcceptedPaymentMethod() []string {
+	if x != nil {
+		return x.AcceptedPaymentMethod
+	}
+	return nil
+}
+
+func (x *Offer) GetOfferAttributes() *structpb.Struct {
+	if x != nil {
+		return x.OfferAttributes
+	}
+	return nil
+}
+
+type Price struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Currency           string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	Value              float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	ApplicableQuantity *ApplicableQuantity    `protobuf:"bytes,3,opt,name=applicable_quantity,json=applicableQuantity,proto3" json:"applicable_quantity,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Price) Reset() {
+	*x = Price{}
+	mi := &file_discovery_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Price) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Price) ProtoMessage() {}
+
+func (x *Price) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Price.ProtoReflect.Descriptor instead.
+func (*Price) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Price) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Price) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Price) GetApplicableQuantity() *ApplicableQuantity {
+	if x != nil {
+		return x.ApplicableQuantity
+	}
+	return nil
+}
+
+type ApplicableQuantity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UnitText      string                 `protobuf:"bytes,1,opt,name=unit_text,json=unitText,proto3" json:"unit_text,omitempty"`
+	UnitCode      string                 `protobuf:"bytes,2,opt,name=unit_code,json=unitCode,proto3" json:"unit_code,omitempty"`
+	UnitQuantity  int32                  `protobuf:"varint,3,opt,name=unit_quantity,json=unitQuantity,proto3" json:"unit_quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplicableQuantity) Reset() {
+	*x = ApplicableQuantity{}
+	mi := &file_discovery_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplicableQuantity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplicableQuantity) ProtoMessage() {}
+
+func (x *ApplicableQuantity) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplicableQuantity.ProtoReflect.Descriptor instead.
+func (*ApplicableQuantity) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ApplicableQuantity) GetUnitText() string {
+	if x != nil {
+		return x.UnitText
+	}
+	return ""
+}
+
+func (x *ApplicableQuantity) GetUnitCode() string {
+	if x != nil {
+		return x.UnitCode
+	}
+	return ""
+}
+
+func (x *ApplicableQuantity) GetUnitQuantity() int32 {
+	if x != nil {
+		return x.UnitQuantity
+	}
+	return 0
+}
+
+type Validity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	StartDate     string                 `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Validity) Reset() {
+	*x = Validity{}
+	mi := &file_discovery_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Validity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Validity) ProtoMessage() {}
+
+func (x *Validity) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Validity.ProtoReflect.Descriptor instead.
+func (*Validity) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Validity) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Validity) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *Validity) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+type Rating struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         float64                `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rating) Reset() {
+	*x = Rating{}
+	mi := &file_discovery_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rating) ProtoMessage() {}
+
+func (x *Rating) ProtoReflect() protoreflect.Message {
+	mi := &file_discovery_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rating.ProtoReflect.Descriptor instead.
+func (*Rating) Descriptor() ([]byte, []int) {
+	return file_discovery_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Rating) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Rating) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+var File_discovery_proto protoreflect.FileDescriptor
+
+const file_discovery_proto_rawDesc = "" +
+	"\n" +
+	"\x0fdiscovery.proto\x12\tdiscovery\x1a\x1cgoogle/protobuf/struct.proto\"n\n" +
+	"\x10DiscoveryRequest\x12,\n" +
+	"\acontext\x18\x01 \x01(\v2\x12.discovery.ContextR\acontext\x12,\n" +
+	"\amessage\x18\x02 \x01(\v2\x12.discovery.MessageR\amessage\"o\n" +
+	"\x11DiscoveryResponse\x12,\n" +
+	"\acontext\x18\x01 \x01(\v2\x12.discovery.ContextR\acontext\x12,\n" +
+	"\amessage\x18\x02 \x01(\v2\x12.discovery.MessageR\amessage\"\x81\x03\n" +
+	"\aContext\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12/\n" +
+	"\blocation\x18\x04 \x01(\v2\x13.discovery.LocationR\blocation\x12\x15\n" +
+	"\x06bap_id\x18\x05 \x01(\tR\x05bapId\x12\x17\n" +
+	"\abap_uri\x18\x06 \x01(\tR\x06bapUri\x12\x15\n" +
+	"\x06bpp_id\x18\a \x01(\tR\x05bppId\x12\x17\n" +
+	"\abpp_uri\x18\b \x01(\tR\x06bppUri\x12%\n" +
+	"\x0etransaction_id\x18\t \x01(\tR\rtransactionId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\n" +
+	" \x01(\tR\tmessageId\x12\x1c\n" +
+	"\ttimestamp\x18\v \x01(\tR\ttimestamp\x12\x10\n" +
+	"\x03ttl\x18\f \x01(\tR\x03ttl\x12%\n" +
+	"\x0eschema_context\x18\r \x03(\tR\rschemaContext\"]\n" +
+	"\bLocation\x12,\n" +
+	"\acountry\x18\x01 \x01(\v2\x12.discovery.CountryR\acountry\x12#\n" +
+	"\x04city\x18\x02 \x01(\v2\x0f.discovery.CityR\x04city\"\x1d\n" +
+	"\aCountry\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\x1a\n" +
+	"\x04City\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\x1b\n" +
+	"\x05State\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"d\n" +
+	"\aMessage\x12)\n" +
+	"\x06intent\x18\x01 \x01(\v2\x11.discovery.IntentR\x06intent\x12.\n" +
+	"\bcatalogs\x18\x02 \x03(\v2\x12.discovery.CatalogR\bcatalogs\"\xb8\x01\n" +
+	"\x06Intent\x12\x17\n" +
+	"\aevse_id\x18\x01 \x01(\tR\x06evseId\x12)\n" +
+	"\x06circle\x18\x02 \x01(\v2\x11.discovery.CircleR\x06circle\x126\n" +
+	"\vtime_window\x18\x03 \x01(\v2\x15.discovery.TimeWindowR\n" +
+	"timeWindow\x122\n" +
+	"\afilters\x18\x04 \x01(\v2\x18.discovery.IntentFiltersR\afilters\"O\n" +
+	"\x06Circle\x12 \n" +
+	"\x03gps\x18\x01 \x01(\v2\x0e.discovery.GeoR\x03gps\x12#\n" +
+	"\rradius_meters\x18\x02 \x01(\x01R\fradiusMeters\"4\n" +
+	"\n" +
+	"TimeWindow\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\tR\x03end\"\xb6\x01\n" +
+	"\rIntentFilters\x12\x10\n" +
+	"\x03cpo\x18\x01 \x01(\tR\x03cpo\x12%\n" +
+	"\x0econnector_type\x18\x02 \x01(\tR\rconnectorType\x12 \n" +
+	"\fmin_power_kw\x18\x03 \x01(\x01R\n" +
+	"minPowerKw\x12\x1c\n" +
+	"\tamenities\x18\x04 \x03(\tR\tamenities\x12,\n" +
+	"\avehicle\x18\x05 \x01(\v2\x12.discovery.VehicleR\avehicle\"G\n" +
+	"\aVehicle\x12\x12\n" +
+	"\x04make\x18\x01 \x01(\tR\x04make\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\"\xd6\x01\n" +
+	"\aCatalog\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x125\n" +
+	"\n" +
+	"descriptor\x18\x04 \x01(\v2\x15.discovery.DescriptorR\n" +
+	"descriptor\x12/\n" +
+	"\bprovider\x18\x05 \x01(\v2\x13.discovery.ProviderR\bprovider\x12%\n" +
+	"\x05items\x18\x06 \x03(\v2\x0f.discovery.ItemR\x05items\"?\n" +
+	"\n" +
+	"Descriptor\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"short_desc\x18\x02 \x01(\tR\tshortDesc\"\xdb\x01\n" +
+	"\bProvider\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x125\n" +
+	"\n" +
+	"descriptor\x18\x04 \x01(\v2\x15.discovery.DescriptorR\n" +
+	"descriptor\x12,\n" +
+	"\aaddress\x18\x05 \x01(\v2\x12.discovery.AddressR\aaddress\x12,\n" +
+	"\acontact\x18\x06 \x03(\v2\x12.discovery.ContactR\acontact\"\xb5\x01\n" +
+	"\aAddress\x12\x1b\n" +
+	"\tarea_code\x18\x01 \x01(\tR\bareaCode\x12#\n" +
+	"\x04city\x18\x02 \x01(\v2\x0f.discovery.CityR\x04city\x12&\n" +
+	"\x05state\x18\x03 \x01(\v2\x10.discovery.StateR\x05state\x12,\n" +
+	"\acountry\x18\x04 \x01(\v2\x12.discovery.CountryR\acountry\x12\x12\n" +
+	"\x04full\x18\x05 \x01(\tR\x04full\"5\n" +
+	"\aContact\x12\x14\n" +
+	"\x05phone\x18\x01 \x01(\tR\x05phone\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\"\xff\x02\n" +
+	"\x04Item\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x125\n" +
+	"\n" +
+	"descriptor\x18\x04 \x01(\v2\x15.discovery.DescriptorR\n" +
+	"descriptor\x12/\n" +
+	"\bcategory\x18\x05 \x01(\v2\x13.discovery.CategoryR\bcategory\x12@\n" +
+	"\x0fitem_attributes\x18\x06 \x01(\v2\x17.google.protobuf.StructR\x0eitemAttributes\x12:\n" +
+	"\favailable_at\x18\a \x03(\v2\x17.discovery.LocationItemR\vavailableAt\x12(\n" +
+	"\x06offers\x18\b \x03(\v2\x10.discovery.OfferR\x06offers\x12)\n" +
+	"\x06rating\x18\t \x01(\v2\x11.discovery.RatingR\x06rating\"e\n" +
+	"\bCategory\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x125\n" +
+	"\n" +
+	"descriptor\x18\x03 \x01(\v2\x15.discovery.DescriptorR\n" +
+	"descriptor\"r\n" +
+	"\fLocationItem\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12 \n" +
+	"\x03geo\x18\x02 \x01(\v2\x0e.discovery.GeoR\x03geo\x12,\n" +
+	"\aaddress\x18\x03 \x01(\v2\x12.discovery.AddressR\aaddress\";\n" +
+	"\x03Geo\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12 \n" +
+	"\vcoordinates\x18\x02 \x03(\x01R\vcoordinates\"\xd1\x02\n" +
+	"\x05Offer\x12\x18\n" +
+	"\acontext\x18\x01 \x01(\tR\acontext\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x125\n" +
+	"\n" +
+	"descriptor\x18\x04 \x01(\v2\x15.discovery.DescriptorR\n" +
+	"descriptor\x12&\n" +
+	"\x05price\x18\x05 \x01(\v2\x10.discovery.PriceR\x05price\x12/\n" +
+	"\bvalidity\x18\x06 \x01(\v2\x13.discovery.ValidityR\bvalidity\x126\n" +
+	"\x17accepted_payment_method\x18\a \x03(\tR\x15acceptedPaymentMethod\x12B\n" +
+	"\x10offer_attributes\x18\b \x01(\v2\x17.google.protobuf.StructR\x0fofferAttributes\"\x89\x01\n" +
+	"\x05Price\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\x12N\n" +
+	"\x13applicable_quantity\x18\x03 \x01(\v2\x1d.discovery.ApplicableQuantityR\x12applicableQuantity\"s\n" +
+	"\x12ApplicableQuantity\x12\x1b\n" +
+	"\tunit_text\x18\x01 \x01(\tR\bunitText\x12\x1b\n" +
+	"\tunit_code\x18\x02 \x01(\tR\bunitCode\x12#\n" +
+	"\runit_quantity\x18\x03 \x01(\x05R\funitQuantity\"X\n" +
+	"\bValidity\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x03 \x01(\tR\aendDate\"4\n" +
+	"\x06Rating\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x01R\x05value\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count2Y\n" +
+	"\x10DiscoveryService\x12E\n" +
+	"\bDiscover\x12\x1b.discovery.DiscoveryRequest\x1a\x1c.discovery.DiscoveryResponseB,Z*bff-go-mvp/proto/discovery/gen;discoverypbb\x06proto3"
+
+var (
+	file_discovery_proto_rawDescOnce sync.Once
+	file_discovery_proto_rawDescData []byte
+)
+
+func file_discovery_proto_rawDescGZIP() []byte {
+	file_discovery_proto_rawDescOnce.Do(func() {
+		file_discovery_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_discovery_proto_rawDesc), len(file_discovery_proto_rawDesc)))
+	})
+	return file_discovery_proto_rawDescData
+}
+
+var file_discovery_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_discovery_proto_goTypes = []any{
+	(*DiscoveryRequest)(nil),   // 0: discovery.DiscoveryRequest
+	(*DiscoveryResponse)(nil),  // 1: discovery.DiscoveryResponse
+	(*Context)(nil),            // 2: discovery.Context
+	(*Location)(nil),           // 3: discovery.Location
+	(*Country)(nil),            // 4: discovery.Country
+	(*City)(nil),               // 5: discovery.City
+	(*State)(nil),              // 6: discovery.State
+	(*Message)(nil),            // 7: discovery.Message
+	(*Intent)(nil),             // 8: discovery.Intent
+	(*Circle)(nil),             // 9: discovery.Circle
+	(*TimeWindow)(nil),         // 10: discovery.TimeWindow
+	(*IntentFilters)(nil),      // 11: discovery.IntentFilters
+	(*Vehicle)(nil),            // 12: discovery.Vehicle
+	(*Catalog)(nil),            // 13: discovery.Catalog
+	(*Descriptor)(nil),         // 14: discovery.Descriptor
+	(*Provider)(nil),           // 15: discovery.Provider
+	(*Address)(nil),            // 16: discovery.Address
+	(*Contact)(nil),            // 17: discovery.Contact
+	(*Item)(nil),               // 18: discovery.Item
+	(*Category)(nil),           // 19: discovery.Category
+	(*LocationItem)(nil),       // 20: discovery.LocationItem
+	(*Geo)(nil),                // 21: discovery.Geo
+	(*Offer)(nil),              // 22: discovery.Offer
+	(*Price)(nil),              // 23: discovery.Price
+	(*ApplicableQuantity)(nil), // 24: discovery.ApplicableQuantity
+	(*Validity)(nil),           // 25: discovery.Validity
+	(*Rating)(nil),             // 26: discovery.Rating
+	(*structpb.Struct)(nil),    // 27: google.protobuf.Struct
+}
+var file_discovery_proto_depIdxs = []int32{
+	2,  // 0: discovery.DiscoveryRequest.context:type_name -> discovery.Context
+	7,  // 1: discovery.DiscoveryRequest.message:type_name -> discovery.Message
+	2,  // 2: discovery.DiscoveryResponse.context:type_name -> discovery.Context
+	7,  // 3: discovery.DiscoveryResponse.message:type_name -> discovery.Message
+	3,  // 4: discovery.Context.location:type_name -> discovery.Location
+	4,  // 5: discovery.Location.country:type_name -> discovery.Country
+	5,  // 6: discovery.Location.city:type_name -> discovery.City
+	8,  // 7: discovery.Message.intent:type_name -> discovery.Intent
+	13, // 8: discovery.Message.catalogs:type_name -> discovery.Catalog
+	9,  // 9: discovery.Intent.circle:type_name -> discovery.Circle
+	10, // 10: discovery.Intent.time_window:type_name -> discovery.TimeWindow
+	11, // 11: discovery.Intent.filters:type_name -> discovery.IntentFilters
+	21, // 12: discovery.Circle.gps:type_name -> discovery.Geo
+	12, // 13: discovery.IntentFilters.vehicle:type_name -> discovery.Vehicle
+	14, // 14: discovery.Catalog.descriptor:type_name -> discovery.Descriptor
+	15, // 15: discovery.Catalog.provider:type_name -> discovery.Provider
+	18, // 16: discovery.Catalog.items:type_name -> discovery.Item
+	14, // 17: discovery.Provider.descriptor:type_name -> discovery.Descriptor
+	16, // 18: discovery.Provider.address:type_name -> discovery.Address
+	17, // 19: discovery.Provider.contact:type_name -> discovery.Contact
+	5,  // 20: discovery.Address.city:type_name -> discovery.City
+	6,  // 21: discovery.Address.state:type_name -> discovery.State
+	4,  // 22: discovery.Address.country:type_name -> discovery.Country
+	14, // 23: discovery.Item.descriptor:type_name -> discovery.Descriptor
+	19, // 24: discovery.Item.category:type_name -> discovery.Category
+	27, // 25: discovery.Item.item_attributes:type_name -> google.protobuf.Struct
+	20, // 26: discovery.Item.available_at:type_name -> discovery.LocationItem
+	22, // 27: discovery.Item.offers:type_name -> discovery.Offer
+	26, // 28: discovery.Item.rating:type_name -> discovery.Rating
+	14, // 29: discovery.Category.descriptor:type_name -> discovery.Descriptor
+	21, // 30: discovery.LocationItem.geo:type_name -> discovery.Geo
+	16, // 31: discovery.LocationItem.address:type_name -> discovery.Address
+	14, // 32: discovery.Offer.descriptor:type_name -> discovery.Descriptor
+	23, // 33: discovery.Offer.price:type_name -> discovery.Price
+	25, // 34: discovery.Offer.validity:type_name -> discovery.Validity
+	27, // 35: discovery.Offer.offer_attributes:type_name -> google.protobuf.Struct
+	24, // 36: discovery.Price.applicable_quantity:type_name -> discovery.ApplicableQuantity
+	0,  // 37: discovery.DiscoveryService.Discover:input_type -> discovery.DiscoveryRequest
+	1,  // 38: discovery.DiscoveryService.Discover:output_type -> discovery.DiscoveryResponse
+	38, // [38:39] is the sub-list for method output_type
+	37, // [37:38] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
+}
+
+func init() { file_discovery_proto_init() }
+func file_discovery_proto_init() {
+	if File_discovery_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_discovery_proto_rawDesc), len(file_discovery_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   27,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_discovery_proto_goTypes,
+		DependencyIndexes: file_discovery_proto_depIdxs,
+		MessageInfos:      file_discovery_proto_msgTypes,
+	}.Build()
+	File_discovery_proto = out.File
+	file_discovery_proto_goTypes = nil
+	file_discovery_proto_depIdxs = nil
+}
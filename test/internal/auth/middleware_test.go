@@ -0,0 +1,71 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/auth"
+	"bff-go-mvp/internal/config"
+	"bff-go-mvp/internal/model"
+)
+
+func TestMiddleware(t *testing.T) {
+	v, err := auth.NewVerifier(context.Background(), config.AuthConfig{HS256Secret: testSecret})
+	require.NoError(t, err)
+
+	var subject string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := auth.PrincipalFrom(r.Context())
+		subject = p.Subject
+		w.WriteHeader(http.StatusOK)
+	})
+	public := func(r *http.Request) bool { return r.URL.Path == "/health" }
+	h := auth.Middleware(v, zap.NewNop(), public)(next)
+
+	t.Run("valid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/orders/1", nil)
+		req.Header.Set("Authorization", "Bearer "+sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), validClaims()))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "user-42", subject)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/orders/1", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+		var body model.Error
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "UNAUTHORIZED", body.Error.Code)
+	})
+
+	t.Run("wrong scheme", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/orders/1", nil)
+		req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("public path", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
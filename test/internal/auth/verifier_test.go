@@ -0,0 +1,244 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/auth"
+	"bff-go-mvp/internal/config"
+)
+
+const testSecret = "dev-secret-that-is-at-least-32-bytes!"
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+		"n": b64(key.N.Bytes()),
+		"e": b64(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": b64(key.X.FillBytes(make([]byte, 32))),
+		"y": b64(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func writeJWKS(t *testing.T, keys ...map[string]string) string {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		tok.Header["kid"] = kid
+	}
+	s, err := tok.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"sub": "user-42",
+		"iss": "https://issuer.example.com",
+		"aud": "bff",
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifier_JWKSFile(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	v, err := auth.NewVerifier(context.Background(), config.AuthConfig{
+		JWKSFile: writeJWKS(t, rsaJWK("rsa-1", &rsaKey.PublicKey), ecJWK("ec-1", &ecKey.PublicKey)),
+		Issuer:   "https://issuer.example.com",
+		Audience: "bff",
+	})
+	require.NoError(t, err)
+
+	p, err := v.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "user-42", p.Subject)
+
+	p, err = v.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "user-42", p.Subject)
+
+	// HS256 is rejected when no secret is configured.
+	_, err = v.Verify(context.Background(), sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), validClaims()))
+	assert.Error(t, err)
+
+	// Unknown key IDs are rejected.
+	_, err = v.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "other", rsaKey, validClaims()))
+	assert.Error(t, err)
+}
+
+func TestVerifier_RejectsInvalidClaims(t *testing.T) {
+	v, err := auth.NewVerifier(context.Background(), config.AuthConfig{
+		HS256Secret: testSecret,
+		Issuer:      "https://issuer.example.com",
+		Audience:    "bff",
+	})
+	require.NoError(t, err)
+
+	cases := map[string]func(jwt.MapClaims){
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"no expiry":      func(c jwt.MapClaims) { delete(c, "exp") },
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "someone-else" },
+		"no subject":     func(c jwt.MapClaims) { delete(c, "sub") },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			claims := validClaims()
+			mutate(claims)
+			_, err := v.Verify(context.Background(), sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err = v.Verify(context.Background(), sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), validClaims()))
+	assert.NoError(t, err)
+
+	// Unsigned tokens are never accepted.
+	unsigned := sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, validClaims())
+	_, err = v.Verify(context.Background(), unsigned)
+	assert.Error(t, err)
+}
+
+func TestVerifier_JWKSURLRefreshesOnUnknownKey(t *testing.T) {
+	first, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	second, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys := []map[string]string{rsaJWK("k1", &first.PublicKey)}
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer srv.Close()
+
+	v, err := auth.NewVerifier(context.Background(), config.AuthConfig{JWKSURL: srv.URL})
+	require.NoError(t, err)
+	assert.Equal(t, 1, fetches)
+
+	// The provider rotates its keys.
+	keys = append(keys, rsaJWK("k2", &second.PublicKey))
+	p, err := v.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "k2", second, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "user-42", p.Subject)
+	assert.Equal(t, 2, fetches)
+}
+
+func TestVerifier_JWKSURLRateLimitsFailedFetches(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	fetches, down := 0, false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{rsaJWK("k1", &key.PublicKey)}})
+	}))
+	defer srv.Close()
+
+	v, err := auth.NewVerifier(context.Background(), config.AuthConfig{JWKSURL: srv.URL, JWKSRefresh: 100 * time.Millisecond})
+	require.NoError(t, err)
+
+	// Once the refresh interval has passed the endpoint goes down. Unknown
+	// key IDs then do not trigger a fetch each: the interval applies to
+	// failed fetches too.
+	time.Sleep(150 * time.Millisecond)
+	mu.Lock()
+	down = true
+	mu.Unlock()
+	for _, kid := range []string{"k2", "k3", "k4"} {
+		_, err := v.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, kid, key, validClaims()))
+		assert.Error(t, err, kid)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, fetches)
+}
+
+func TestNewVerifier_RequiresKeys(t *testing.T) {
+	_, err := auth.NewVerifier(context.Background(), config.AuthConfig{})
+	assert.Error(t, err)
+
+	_, err = auth.NewVerifier(context.Background(), config.AuthConfig{JWKSFile: "/does/not/exist.json"})
+	assert.Error(t, err)
+}
+
+func TestVerifier_ReadsRolesAndBAP(t *testing.T) {
+	v, err := auth.NewVerifier(context.Background(), config.AuthConfig{HS256Secret: testSecret})
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims["bap_id"] = "bap-1"
+	claims["roles"] = []string{"operator", "support"}
+	p, err := v.Verify(context.Background(), sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+	require.NoError(t, err)
+	assert.Equal(t, "bap-1", p.BAPID)
+	assert.True(t, p.HasRole("operator"))
+
+	// OAuth scopes are not roles.
+	claims = validClaims()
+	claims["scope"] = "orders:read operator"
+	p, err = v.Verify(context.Background(), sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+	require.NoError(t, err)
+	assert.Empty(t, p.Roles)
+	assert.False(t, p.HasRole("operator"))
+}
+
+func TestVerifier_ReadsConfiguredRolesClaim(t *testing.T) {
+	v, err := auth.NewVerifier(context.Background(), config.AuthConfig{HS256Secret: testSecret, RolesClaim: "https://bff.example.com/roles"})
+	require.NoError(t, err)
+
+	claims := validClaims()
+	claims["https://bff.example.com/roles"] = []string{"operator"}
+	claims["roles"] = []string{"support"}
+	p, err := v.Verify(context.Background(), sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claims))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"operator"}, p.Roles)
+}
@@ -2,11 +2,23 @@ package config_test
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"bff-go-mvp/internal/config"
 )
 
+// TestMain points payments at a PSP and turns on authentication, which
+// every environment but development requires, so that the default
+// configuration validates.
+func TestMain(m *testing.M) {
+	os.Setenv("PAYMENT_PSP_URL", "http://localhost:8090")
+	os.Setenv("AUTH_ENABLED", "true")
+	os.Setenv("AUTH_JWKS_URL", "http://localhost:8091/.well-known/jwks.json")
+	os.Exit(m.Run())
+}
+
 func TestLoad(t *testing.T) {
 	// Save original env values
 	originalGRPCAddr := os.Getenv("GRPC_SERVICE_ADDRESS")
@@ -47,3 +59,328 @@ func TestLoad(t *testing.T) {
 		t.Errorf("Expected API port '9090', got '%s'", cfg.API.Port)
 	}
 }
+
+func setEnv(t *testing.T, key, value string) {
+	t.Helper()
+	original, existed := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if existed {
+			os.Setenv(key, original)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestLoad_BackendDefaults(t *testing.T) {
+	cfg := config.Load()
+
+	for _, d := range cfg.Backend.Domains() {
+		if d.Mode != config.BackendModeMock {
+			t.Errorf("Expected default mode 'mock' for %s, got '%s'", d.Domain, d.Mode)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected default configuration to be valid, got %v", err)
+	}
+}
+
+func TestLoad_BackendPerDomainOverride(t *testing.T) {
+	setEnv(t, "BACKEND_MODE", "http")
+	setEnv(t, "SEARCH_BACKEND_MODE", "GRPC")
+	setEnv(t, "SUPPORT_BACKEND_MODE", "mock")
+	setEnv(t, "BACKEND_HTTP_BASE_URL", "http://backend:9000")
+
+	cfg := config.Load()
+	if cfg.Backend.Search != config.BackendModeGRPC {
+		t.Errorf("Expected search mode 'grpc', got '%s'", cfg.Backend.Search)
+	}
+	if cfg.Backend.Estimate != config.BackendModeHTTP {
+		t.Errorf("Expected estimate to inherit 'http', got '%s'", cfg.Backend.Estimate)
+	}
+	if cfg.Backend.Support != config.BackendModeMock {
+		t.Errorf("Expected support mode 'mock', got '%s'", cfg.Backend.Support)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected configuration to be valid, got %v", err)
+	}
+}
+
+func TestValidate_RejectsBadBackendModes(t *testing.T) {
+	setEnv(t, "ESTIMATE_BACKEND_MODE", "grpc")
+	setEnv(t, "PAYMENT_BACKEND_MODE", "carrier-pigeon")
+	setEnv(t, "ORDERS_BACKEND_MODE", "http")
+
+	err := config.Load().Validate()
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	for _, want := range []string{
+		`estimate: unsupported backend mode "grpc"`,
+		`payment: unsupported backend mode "carrier-pigeon"`,
+		"BACKEND_HTTP_BASE_URL is required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %q, got %v", want, err)
+		}
+	}
+}
+
+func TestLoad_Auth(t *testing.T) {
+	setEnv(t, "AUTH_ENABLED", "true")
+	setEnv(t, "AUTH_JWKS_URL", "https://issuer.example.com/.well-known/jwks.json")
+	setEnv(t, "AUTH_AUDIENCE", "bff")
+	setEnv(t, "AUTH_CLOCK_SKEW", "1m")
+
+	cfg := config.Load()
+	if !cfg.Auth.Enabled {
+		t.Error("Expected auth to be enabled")
+	}
+	if cfg.Auth.Audience != "bff" {
+		t.Errorf("Expected audience bff, got %s", cfg.Auth.Audience)
+	}
+	if cfg.Auth.ClockSkew != time.Minute {
+		t.Errorf("Expected clock skew 1m, got %s", cfg.Auth.ClockSkew)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected configuration to be valid, got %v", err)
+	}
+}
+
+func TestValidate_AuthRequiresKeys(t *testing.T) {
+	setEnv(t, "AUTH_ENABLED", "true")
+	setEnv(t, "AUTH_JWKS_URL", "")
+
+	err := config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "AUTH_JWKS_FILE, AUTH_JWKS_URL or AUTH_HS256_SECRET is required") {
+		t.Errorf("Expected missing key error, got %v", err)
+	}
+
+	setEnv(t, "AUTH_HS256_SECRET", "short")
+	err = config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "at least 32 bytes") {
+		t.Errorf("Expected short secret error, got %v", err)
+	}
+}
+
+func TestValidate_HS256OnlyInDevelopment(t *testing.T) {
+	setEnv(t, "AUTH_HS256_SECRET", "a-shared-secret-of-at-least-32-bytes")
+
+	err := config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "AUTH_HS256_SECRET is only allowed with ENV=development") {
+		t.Errorf("Expected HS256 to be rejected in production, got %v", err)
+	}
+
+	setEnv(t, "ENV", "development")
+	setEnv(t, "PAYMENT_PSP_URL", "")
+	setEnv(t, "PAYMENT_FAKE_PSP", "true")
+	setEnv(t, "AUTH_JWKS_URL", "")
+	if err := config.Load().Validate(); err != nil {
+		t.Errorf("Expected HS256 to be allowed in development, got %v", err)
+	}
+}
+
+func TestValidate_AuthRequiredOutsideDevelopment(t *testing.T) {
+	setEnv(t, "AUTH_ENABLED", "false")
+
+	err := config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "AUTH_ENABLED is required unless ENV=development") {
+		t.Errorf("Expected auth to be required in production, got %v", err)
+	}
+
+	setEnv(t, "ENV", "development")
+	setEnv(t, "PAYMENT_PSP_URL", "")
+	setEnv(t, "PAYMENT_FAKE_PSP", "true")
+	if err := config.Load().Validate(); err != nil {
+		t.Errorf("Expected auth to be optional in development, got %v", err)
+	}
+}
+
+func TestLoad_Tracing(t *testing.T) {
+	cfg := config.Load()
+	if cfg.Tracing.Exporter != config.TraceExporterNone {
+		t.Errorf("Expected tracing exporter none by default, got %s", cfg.Tracing.Exporter)
+	}
+
+	setEnv(t, "OTEL_TRACES_EXPORTER", "OTLP")
+	setEnv(t, "OTEL_EXPORTER_OTLP_ENDPOINT", "collector:4317")
+	setEnv(t, "OTEL_TRACES_SAMPLER_ARG", "0.25")
+
+	cfg = config.Load()
+	if cfg.Tracing.Exporter != config.TraceExporterOTLP {
+		t.Errorf("Expected exporter otlp, got %s", cfg.Tracing.Exporter)
+	}
+	if cfg.Tracing.OTLPEndpoint != "collector:4317" {
+		t.Errorf("Expected endpoint collector:4317, got %s", cfg.Tracing.OTLPEndpoint)
+	}
+	if cfg.Tracing.SampleRatio != 0.25 {
+		t.Errorf("Expected sample ratio 0.25, got %v", cfg.Tracing.SampleRatio)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected configuration to be valid, got %v", err)
+	}
+}
+
+func TestValidate_RejectsBadTracing(t *testing.T) {
+	setEnv(t, "OTEL_TRACES_EXPORTER", "jaeger")
+	setEnv(t, "OTEL_TRACES_SAMPLER_ARG", "2")
+
+	err := config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), `unsupported exporter "jaeger"`) {
+		t.Errorf("Expected unsupported exporter error, got %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "OTEL_TRACES_SAMPLER_ARG must be between 0 and 1") {
+		t.Errorf("Expected sample ratio error, got %v", err)
+	}
+}
+
+func TestValidate_IndexModeRequiresStationsFile(t *testing.T) {
+	setEnv(t, "SEARCH_BACKEND_MODE", "index")
+	setEnv(t, "ESTIMATE_BACKEND_MODE", "index")
+
+	err := config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "SEARCH_STATIONS_FILE is required") {
+		t.Errorf("Expected missing stations file error, got %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), `estimate: unsupported backend mode "index"`) {
+		t.Errorf("Expected index mode to be rejected for estimate, got %v", err)
+	}
+
+	setEnv(t, "ESTIMATE_BACKEND_MODE", "mock")
+	setEnv(t, "SEARCH_STATIONS_FILE", "data/stations.geojson")
+	if err := config.Load().Validate(); err != nil {
+		t.Errorf("Expected configuration to be valid, got %v", err)
+	}
+}
+
+func TestValidate_MoneyFormat(t *testing.T) {
+	if got := config.Load().API.MoneyFormat; got != "legacy" {
+		t.Errorf("Expected legacy money format by default, got %q", got)
+	}
+
+	setEnv(t, "MONEY_FORMAT", "Decimal")
+	if err := config.Load().Validate(); err != nil {
+		t.Errorf("Expected configuration to be valid, got %v", err)
+	}
+
+	setEnv(t, "MONEY_FORMAT", "float")
+	err := config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), `MONEY_FORMAT: unsupported format "float"`) {
+		t.Errorf("Expected unsupported money format error, got %v", err)
+	}
+}
+
+func TestValidate_Payment(t *testing.T) {
+	cfg := config.Load()
+	if cfg.Payment.FakePSP || cfg.Payment.AutoCapture {
+		t.Errorf("Expected no fake PSP and no auto-capture by default, got %+v", cfg.Payment)
+	}
+	if cfg.Payment.PublicURL != "http://localhost:8080" {
+		t.Errorf("Expected the public URL to default to the API port, got %q", cfg.Payment.PublicURL)
+	}
+
+	setEnv(t, "PAYMENT_PSP_URL", "localhost:8090")
+	setEnv(t, "PAYMENT_MERCHANT_VPA", "bluechargenet")
+	err := config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "PAYMENT_PSP_URL must be an absolute http(s) URL") ||
+		!strings.Contains(err.Error(), "PAYMENT_MERCHANT_VPA must be a UPI address") {
+		t.Errorf("Expected payment configuration errors, got %v", err)
+	}
+}
+
+func TestValidate_FakePSPOnlyInDevelopment(t *testing.T) {
+	setEnv(t, "PAYMENT_PSP_URL", "")
+	err := config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "PAYMENT_PSP_URL is required when payment uses mock mode") {
+		t.Errorf("Expected a missing PSP error, got %v", err)
+	}
+
+	setEnv(t, "PAYMENT_FAKE_PSP", "true")
+	err = config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "PAYMENT_FAKE_PSP is only allowed with ENV=development") {
+		t.Errorf("Expected the fake PSP to be rejected outside development, got %v", err)
+	}
+
+	setEnv(t, "ENV", "development")
+	if err := config.Load().Validate(); err != nil {
+		t.Errorf("Expected the fake PSP to be allowed in development, got %v", err)
+	}
+
+	setEnv(t, "PAYMENT_PSP_URL", "http://localhost:8090")
+	err = config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "PAYMENT_FAKE_PSP and PAYMENT_PSP_URL cannot both be set") {
+		t.Errorf("Expected conflicting PSP settings to be rejected, got %v", err)
+	}
+
+	// The payment backend in http mode needs no PSP.
+	setEnv(t, "PAYMENT_FAKE_PSP", "")
+	setEnv(t, "PAYMENT_PSP_URL", "")
+	setEnv(t, "PAYMENT_BACKEND_MODE", "http")
+	setEnv(t, "BACKEND_HTTP_BASE_URL", "http://backend.test")
+	if err := config.Load().Validate(); err != nil {
+		t.Errorf("Expected no PSP to be needed in http mode, got %v", err)
+	}
+}
+
+func TestLoad_PaymentWebhookSecrets(t *testing.T) {
+	setEnv(t, "PAYMENT_WEBHOOK_SECRETS", "fakepsp=s3cret, razorpay = rzp-secret")
+	cfg := config.Load()
+	if cfg.Payment.WebhookSecrets["fakepsp"] != "s3cret" || cfg.Payment.WebhookSecrets["razorpay"] != "rzp-secret" {
+		t.Errorf("Expected secrets per provider, got %v", cfg.Payment.WebhookSecrets)
+	}
+	if cfg.Payment.WebhookTolerance != 5*time.Minute {
+		t.Errorf("Expected a 5m default tolerance, got %v", cfg.Payment.WebhookTolerance)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected valid configuration, got %v", err)
+	}
+
+	setEnv(t, "PAYMENT_WEBHOOK_SECRETS", "fakepsp")
+	err := config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "PAYMENT_WEBHOOK_SECRETS must be a comma-separated list of provider=secret pairs") {
+		t.Errorf("Expected a webhook secrets error, got %v", err)
+	}
+}
+
+func TestLoad_PaymentRefundRetries(t *testing.T) {
+	cfg := config.Load()
+	if cfg.Payment.RefundMaxAttempts != 5 || cfg.Payment.RefundRetryBackoff != 30*time.Second {
+		t.Errorf("Expected 5 refund attempts 30s apart by default, got %d and %v",
+			cfg.Payment.RefundMaxAttempts, cfg.Payment.RefundRetryBackoff)
+	}
+	if cfg.Payment.RefundSweepInterval != 10*time.Second {
+		t.Errorf("Expected due refunds to be swept every 10s by default, got %v", cfg.Payment.RefundSweepInterval)
+	}
+
+	setEnv(t, "PAYMENT_REFUND_MAX_ATTEMPTS", "0")
+	setEnv(t, "PAYMENT_REFUND_RETRY_BACKOFF", "-1s")
+	setEnv(t, "PAYMENT_REFUND_SWEEP_INTERVAL", "0s")
+	err := config.Load().Validate()
+	if err == nil || !strings.Contains(err.Error(), "PAYMENT_REFUND_MAX_ATTEMPTS must be positive") ||
+		!strings.Contains(err.Error(), "PAYMENT_REFUND_RETRY_BACKOFF must be positive") ||
+		!strings.Contains(err.Error(), "PAYMENT_REFUND_SWEEP_INTERVAL must be positive") {
+		t.Errorf("Expected refund retry errors, got %v", err)
+	}
+}
+
+func TestValidate_MalformedValues(t *testing.T) {
+	setEnv(t, "AUTH_ENABLED", "yes")
+	setEnv(t, "PAYMENT_REFUND_MAX_ATTEMPTS", "five")
+	setEnv(t, "PAYMENT_WEBHOOK_TOLERANCE", "5")
+
+	cfg := config.Load()
+	if cfg.Auth.Enabled {
+		t.Errorf("Expected the default for a malformed AUTH_ENABLED")
+	}
+	err := cfg.Validate()
+	for _, want := range []string{
+		`AUTH_ENABLED="yes" is not a boolean`,
+		`PAYMENT_REFUND_MAX_ATTEMPTS="five" is not an integer`,
+		`PAYMENT_WEBHOOK_TOLERANCE="5" is not a duration`,
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q, got %v", want, err)
+		}
+	}
+}
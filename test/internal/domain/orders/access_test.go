@@ -0,0 +1,98 @@
+package orders_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/auth"
+	"bff-go-mvp/internal/domain/orders"
+	"bff-go-mvp/internal/httpclient"
+)
+
+func TestAccessPolicy(t *testing.T) {
+	repo := orders.NewMemoryRepository()
+	policy := orders.NewAccessPolicy(repo, "operator", false)
+
+	alice := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", BAPID: "bap-1"})
+	bob := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "bob", BAPID: "bap-1"})
+	aliceOtherBAP := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", BAPID: "bap-2"})
+	operator := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "ops", Roles: []string{"operator"}})
+
+	require.NoError(t, repo.Create(alice, &orders.Order{ID: "order-1"}))
+	require.NoError(t, policy.Claim(alice, "order-1"))
+
+	stored, err := repo.Get(alice, "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, orders.Owner{UserID: "alice", BAPID: "bap-1"}, stored.Owner)
+
+	assert.NoError(t, policy.Authorize(alice, "order-1"))
+	assert.NoError(t, policy.Authorize(operator, "order-1"))
+	assert.NoError(t, policy.Authorize(context.Background(), "order-1"), "no principal when auth is disabled")
+
+	// Other callers see the order as missing.
+	for _, ctx := range []context.Context{bob, aliceOtherBAP} {
+		err := policy.Authorize(ctx, "order-1")
+		assert.True(t, apperror.IsKind(err, apperror.KindNotFound))
+		assert.ErrorIs(t, err, orders.ErrOrderNotFound)
+	}
+	assert.True(t, apperror.IsKind(policy.Authorize(alice, "order-unknown"), apperror.KindNotFound))
+}
+
+func TestAccessPolicy_DeniesMissingPrincipalWhenAuthenticated(t *testing.T) {
+	repo := orders.NewMemoryRepository()
+	policy := orders.NewAccessPolicy(repo, "operator", true)
+	alice := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice"})
+
+	require.NoError(t, repo.Create(alice, &orders.Order{ID: "order-1"}))
+	require.NoError(t, policy.Claim(alice, "order-1"))
+	assert.NoError(t, policy.Authorize(alice, "order-1"))
+
+	assert.True(t, apperror.IsKind(policy.Authorize(context.Background(), "order-1"), apperror.KindUnauthorized))
+	assert.True(t, apperror.IsKind(policy.Claim(context.Background(), "order-1"), apperror.KindUnauthorized))
+}
+
+func TestHTTPOwnership(t *testing.T) {
+	// The backend keeps the owner with the order; a second BFF instance
+	// reads it back.
+	var mu sync.Mutex
+	owners := map[string]string{}
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			owners[r.URL.Path] = string(body)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			body, ok := owners[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = io.WriteString(w, body)
+		}
+	}))
+	defer backend.Close()
+	ctx := context.Background()
+
+	writer := orders.NewHTTPOwnership(httpclient.New(backend.URL, time.Second))
+	require.NoError(t, writer.SetOwner(ctx, "order-1", orders.Owner{UserID: "alice", BAPID: "bap-1"}))
+
+	reader := orders.NewHTTPOwnership(httpclient.New(backend.URL, time.Second))
+	owner, err := reader.OwnerOf(ctx, "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, orders.Owner{UserID: "alice", BAPID: "bap-1"}, owner)
+
+	_, err = reader.OwnerOf(ctx, "order-2")
+	assert.True(t, apperror.IsKind(err, apperror.KindNotFound))
+}
@@ -0,0 +1,74 @@
+package orders_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/domain/orders"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+)
+
+func TestMemoryRepository_CreateAndGet(t *testing.T) {
+	repo := orders.NewMemoryRepository()
+	ctx := context.Background()
+
+	order := &orders.Order{
+		ID:              "order-1",
+		Status:          orders.StatusQuoted,
+		PriceComponents: []model.PriceComponent{{Type: "UNIT", Value: money.New(10000, 2), Currency: "INR"}},
+	}
+	require.NoError(t, repo.Create(ctx, order))
+	assert.Error(t, repo.Create(ctx, order), "duplicate IDs are rejected")
+
+	got, err := repo.Get(ctx, "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, orders.StatusQuoted, got.Status)
+	assert.False(t, got.CreatedAt.IsZero())
+
+	// Returned orders are copies.
+	got.PriceComponents[0].Value = money.New(1, 0)
+	again, err := repo.Get(ctx, "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, money.New(10000, 2), again.PriceComponents[0].Value)
+}
+
+func TestMemoryRepository_Update(t *testing.T) {
+	repo := orders.NewMemoryRepository()
+	ctx := context.Background()
+	require.NoError(t, repo.Create(ctx, &orders.Order{ID: "order-1", Status: orders.StatusQuoted}))
+
+	updated, err := repo.Update(ctx, "order-1", func(o *orders.Order) error {
+		o.Status = orders.StatusActive
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, orders.StatusActive, updated.Status)
+
+	// A failed update leaves the stored order untouched.
+	boom := errors.New("boom")
+	_, err = repo.Update(ctx, "order-1", func(o *orders.Order) error {
+		o.Status = orders.StatusCancelled
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+
+	got, err := repo.Get(ctx, "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, orders.StatusActive, got.Status)
+}
+
+func TestMemoryRepository_NotFound(t *testing.T) {
+	repo := orders.NewMemoryRepository()
+	ctx := context.Background()
+
+	_, err := repo.Get(ctx, "missing")
+	assert.ErrorIs(t, err, orders.ErrOrderNotFound)
+
+	_, err = repo.Update(ctx, "missing", func(*orders.Order) error { return nil })
+	assert.ErrorIs(t, err, orders.ErrOrderNotFound)
+}
@@ -0,0 +1,115 @@
+package orders_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/orders"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+	"bff-go-mvp/internal/pricing"
+)
+
+var stoppedAt = time.Date(2026, 1, 10, 9, 30, 0, 0, time.UTC)
+
+// chargedOrder is a paid order for 300 INR at 20 INR/kWh with a 10 INR
+// service fee, whose session delivered energy in duration.
+func chargedOrder(energy, duration model.ChargingMetric) *orders.Order {
+	return &orders.Order{
+		ID:            "order-1",
+		PaymentStatus: orders.PaymentPaid,
+		Amount:        model.Amount{Value: money.New(30000, 2), Currency: "INR"},
+		Offer: model.Offer{
+			ID:    "offer-1",
+			Price: model.Price{Currency: "INR", Value: 20, ApplicableQuantity: &model.ApplicableQuantity{UnitCode: "KWH", UnitQuantity: 1}},
+		},
+		Policy: pricing.Policy{ServiceFees: []pricing.Adjustment{{Description: "Service fee", Fixed: 10}}},
+		ChargingTelemetry: &model.ChargingTelemetry{
+			Metrics: []model.ChargingMetric{energy, duration},
+		},
+	}
+}
+
+func components(s orders.Settlement) []string {
+	out := []string{}
+	for _, c := range s.Components {
+		out = append(out, c.Type+" "+c.Value.String())
+	}
+	return out
+}
+
+func TestSettle_RefundsUnusedPrepaidAmount(t *testing.T) {
+	o := chargedOrder(
+		model.ChargingMetric{Name: "ENERGY", Value: 8500, UnitCode: "WH"},
+		model.ChargingMetric{Name: "SESSION_DURATION", Value: 1230, UnitCode: "SEC"},
+	)
+
+	s, err := orders.Settle(o, stoppedAt)
+	require.NoError(t, err)
+	assert.Equal(t, pricing.Usage{Energy: 8.5, Minutes: 20.5}, s.Usage)
+	assert.Equal(t, []string{"UNIT 170.00", "FEE 10.00", "PAID -300.00", "REFUND 120.00"}, components(s))
+	assert.Equal(t, "180.00", s.Breakdown.Subtotal.Value.String())
+	assert.Equal(t, "0.00", s.Breakdown.Total.Value.String())
+}
+
+func TestSettle_BalanceDue(t *testing.T) {
+	o := chargedOrder(
+		model.ChargingMetric{Name: "ENERGY", Value: 16, UnitCode: "KWH"},
+		model.ChargingMetric{Name: "SESSION_DURATION", Value: 40, UnitCode: "MIN"},
+	)
+
+	s, err := orders.Settle(o, stoppedAt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"UNIT 320.00", "FEE 10.00", "PAID -300.00"}, components(s))
+	assert.Equal(t, "30.00", s.Breakdown.Total.Value.String())
+}
+
+func TestSettle_RejectsMissingTelemetry(t *testing.T) {
+	o := chargedOrder(
+		model.ChargingMetric{Name: "POWER", Value: 18.4, UnitCode: "KW"},
+		model.ChargingMetric{Name: "SESSION_DURATION", Value: 40, UnitCode: "MIN"},
+	)
+	_, err := orders.Settle(o, stoppedAt)
+	assert.True(t, apperror.IsKind(err, apperror.KindConflict))
+
+	o.ChargingTelemetry = nil
+	_, err = orders.Settle(o, stoppedAt)
+	assert.True(t, apperror.IsKind(err, apperror.KindConflict))
+
+	o = chargedOrder(
+		model.ChargingMetric{Name: "ENERGY", Value: 16, UnitCode: "KW"},
+		model.ChargingMetric{Name: "SESSION_DURATION", Value: 40, UnitCode: "MIN"},
+	)
+	_, err = orders.Settle(o, stoppedAt)
+	assert.True(t, apperror.IsKind(err, apperror.KindConflict), "KW is not an energy unit")
+}
+
+func TestSettle_BillsIdleTimeAfterGracePeriod(t *testing.T) {
+	o := chargedOrder(
+		model.ChargingMetric{Name: "ENERGY", Value: 8.5, UnitCode: "KWH"},
+		model.ChargingMetric{Name: "SESSION_DURATION", Value: 20, UnitCode: "MIN"},
+	)
+	o.Offer.OfferAttributes = &model.OfferAttributes{IdleFeePolicy: "₹2/min after 10 min post-charge"}
+	o.ChargingEndedAt = stoppedAt
+
+	s, err := orders.Settle(o, stoppedAt.Add(9*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"UNIT 170.00", "FEE 10.00", "PAID -300.00", "REFUND 120.00"}, components(s),
+		"no idle fee within the grace period")
+
+	s, err = orders.Settle(o, stoppedAt.Add(24*time.Minute+10*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Minute+10*time.Second, s.Idle)
+	assert.Equal(t, []string{"UNIT 170.00", "FEE 10.00", "IDLE_FEE 30.00", "PAID -300.00", "REFUND 90.00"}, components(s))
+	assert.Equal(t, "Idle fee (15 min at 2 INR/min after 10 min grace)", s.Components[2].Description)
+
+	// Once unplugged the idle time stops accruing.
+	o.UnpluggedAt = stoppedAt.Add(12 * time.Minute)
+	s, err = orders.Settle(o, stoppedAt.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"UNIT 170.00", "FEE 10.00", "IDLE_FEE 4.00", "PAID -300.00", "REFUND 116.00"}, components(s))
+}
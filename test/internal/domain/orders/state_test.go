@@ -0,0 +1,147 @@
+package orders_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/domain/orders"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+)
+
+func quotedOrder() *orders.Order {
+	return &orders.Order{
+		ID:             "order-1",
+		Status:         orders.StatusQuoted,
+		PaymentStatus:  orders.PaymentPending,
+		ChargingStatus: orders.ChargingIdle,
+	}
+}
+
+func TestOrder_Apply_HappyPath(t *testing.T) {
+	o := quotedOrder()
+	now := time.Date(2025, 1, 27, 17, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []orders.Action{orders.ActionPay, orders.ActionCancel}, o.AllowedActions())
+
+	require.NoError(t, o.Apply(orders.ActionPay, now))
+	assert.Equal(t, orders.State{Order: orders.StatusActive, Payment: orders.PaymentPaid, Charging: orders.ChargingIdle}, o.State())
+
+	require.NoError(t, o.Apply(orders.ActionStart, now))
+	assert.Equal(t, orders.ChargingActive, o.ChargingStatus)
+	assert.Equal(t, now, o.ChargingStartedAt)
+	assert.Equal(t, []orders.Action{orders.ActionStop}, o.AllowedActions())
+
+	require.NoError(t, o.Apply(orders.ActionStop, now.Add(time.Hour)))
+	assert.Equal(t, orders.StatusCompleted, o.Status)
+	assert.Equal(t, orders.ChargingCompleted, o.ChargingStatus)
+	assert.Equal(t, []orders.Action{orders.ActionUnplug, orders.ActionRate}, o.AllowedActions())
+
+	require.NoError(t, o.Apply(orders.ActionUnplug, now.Add(time.Hour+20*time.Minute)))
+	assert.Equal(t, orders.ChargingUnplugged, o.ChargingStatus)
+	assert.Equal(t, 20*time.Minute, o.IdleTime(now.Add(3*time.Hour)))
+	assert.Error(t, o.Apply(orders.ActionUnplug, now), "the connector is unplugged once")
+
+	require.NoError(t, o.Apply(orders.ActionRate, now))
+	assert.Equal(t, []orders.Action{orders.ActionRate}, o.AllowedActions())
+}
+
+func TestOrder_Apply_RejectsIllegalTransitions(t *testing.T) {
+	now := time.Now()
+
+	o := quotedOrder()
+	err := o.Apply(orders.ActionStart, now)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, orders.ErrInvalidTransition))
+	assert.Equal(t, orders.ChargingIdle, o.ChargingStatus, "a rejected action leaves the order unchanged")
+
+	var te *orders.TransitionError
+	require.ErrorAs(t, err, &te)
+	assert.Equal(t, orders.ActionStart, te.Action)
+	assert.Equal(t, orders.StatusQuoted, te.State.Order)
+	assert.Equal(t, []orders.Action{orders.ActionPay, orders.ActionCancel}, te.Allowed)
+
+	// Payment cannot be taken twice.
+	require.NoError(t, o.Apply(orders.ActionPay, now))
+	assert.Error(t, o.Apply(orders.ActionPay, now))
+
+	// Orders cannot be cancelled while charging.
+	require.NoError(t, o.Apply(orders.ActionStart, now))
+	assert.Error(t, o.Apply(orders.ActionCancel, now))
+}
+
+func TestOrder_Apply_CancelRefundsPaidOrders(t *testing.T) {
+	now := time.Now()
+
+	o := quotedOrder()
+	o.Amount = model.Amount{Value: money.New(12864, 2), Currency: "INR"}
+	require.NoError(t, o.Apply(orders.ActionPay, now))
+	require.NoError(t, o.Apply(orders.ActionCancel, now))
+	assert.Equal(t, orders.StatusCancelled, o.Status)
+	assert.Empty(t, o.AllowedActions())
+
+	// The payment stays PAID until the refund completes.
+	assert.Equal(t, orders.PaymentPaid, o.PaymentStatus)
+	refund := o.AddRefund(money.New(5000, 2), orders.RefundReasonCancellation, now)
+	require.NotNil(t, refund)
+	assert.Equal(t, orders.RefundPending, refund.Status)
+	assert.Nil(t, o.AddRefund(money.Decimal{}, orders.RefundReasonCancellation, now), "nothing to refund")
+	require.NoError(t, o.CompleteRefund(refund.ID, "rfnd_1", now))
+	assert.Equal(t, orders.RefundSucceeded, o.FindRefund(refund.ID).Status)
+	assert.Equal(t, orders.PaymentPaid, o.PaymentStatus, "a partial refund keeps the payment PAID")
+
+	rest := o.AddRefund(money.New(7864, 2), orders.RefundReasonCancellation, now)
+	require.NoError(t, o.CompleteRefund(rest.ID, "rfnd_2", now))
+	assert.Equal(t, orders.PaymentRefunded, o.PaymentStatus)
+
+	unpaid := quotedOrder()
+	require.NoError(t, unpaid.Apply(orders.ActionCancel, now))
+	assert.Equal(t, orders.PaymentPending, unpaid.PaymentStatus)
+}
+
+func TestOrder_ResolvePaymentAttempt_RefundsUnappliedCaptures(t *testing.T) {
+	now := time.Now()
+	attempt := func(id, method string) orders.PaymentAttempt {
+		return orders.PaymentAttempt{
+			ID:     id,
+			Method: method,
+			Amount: model.Amount{Value: money.New(12864, 2), Currency: "INR"},
+			Status: orders.PaymentPending,
+		}
+	}
+
+	// A capture after cancelling becomes the payment and is refunded in full.
+	o := quotedOrder()
+	o.PaymentAttempts = []orders.PaymentAttempt{attempt("pay-1", "WALLET")}
+	require.NoError(t, o.Apply(orders.ActionCancel, now))
+	assert.Equal(t, orders.PaymentVoided, o.PaymentAttempts[0].Status)
+	require.NoError(t, o.ResolvePaymentAttempt("pay-1", orders.PaymentPaid, now))
+	assert.Equal(t, orders.StatusCancelled, o.Status)
+	assert.Equal(t, orders.PaymentPaid, o.PaymentStatus)
+	require.Len(t, o.Refunds, 1)
+	assert.Equal(t, orders.RefundReasonUnappliedPayment, o.Refunds[0].Reason)
+	assert.Equal(t, "pay-1", o.Refunds[0].PaymentAttemptID)
+	assert.Equal(t, "128.64", o.Refunds[0].Amount.Value.String())
+	require.NoError(t, o.CompleteRefund(o.Refunds[0].ID, "rfnd_1", now))
+	assert.Equal(t, orders.PaymentRefunded, o.PaymentStatus)
+
+	// A second capture is refunded and leaves the payment of the first alone.
+	o = quotedOrder()
+	o.PaymentAttempts = []orders.PaymentAttempt{attempt("pay-1", "UPI_INTENT"), attempt("pay-2", "CARD")}
+	require.NoError(t, o.ResolvePaymentAttempt("pay-2", orders.PaymentPaid, now))
+	require.NoError(t, o.ResolvePaymentAttempt("pay-1", orders.PaymentPaid, now))
+	assert.Equal(t, orders.StatusActive, o.Status)
+	assert.Equal(t, "pay-2", o.PaidAttemptID)
+	require.Len(t, o.Refunds, 1)
+	assert.Equal(t, "pay-1", o.Refunds[0].PaymentAttemptID)
+	require.NoError(t, o.CompleteRefund(o.Refunds[0].ID, "rfnd_1", now))
+	assert.Equal(t, orders.PaymentPaid, o.PaymentStatus)
+
+	// Reporting the capture again records nothing more.
+	require.NoError(t, o.ResolvePaymentAttempt("pay-1", orders.PaymentPaid, now))
+	assert.Len(t, o.Refunds, 1)
+}
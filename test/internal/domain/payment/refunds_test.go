@@ -0,0 +1,196 @@
+package payment_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/orders"
+	"bff-go-mvp/internal/domain/payment"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+)
+
+// flakyPSP fails the first refunds it is asked for, then refunds through
+// the PSP it wraps.
+type flakyPSP struct {
+	payment.PSP
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (p *flakyPSP) CreateRefund(ctx context.Context, req payment.PSPRefundRequest) (payment.PSPRefund, error) {
+	p.mu.Lock()
+	p.calls++
+	fail := p.calls <= p.failures
+	p.mu.Unlock()
+	if fail {
+		return payment.PSPRefund{}, errors.New("psp unavailable")
+	}
+	return p.PSP.CreateRefund(ctx, req)
+}
+
+// paidOrderWithRefund pays order-1 and records a pending refund of 50 INR,
+// returning a refund service whose PSP fails the first failures attempts.
+func paidOrderWithRefund(t *testing.T, failures, maxAttempts int) (*payment.RefundService, *orders.MemoryRepository, string) {
+	t.Helper()
+	svc, repo, psp := newService(t, true)
+	ctx := context.Background()
+	_, err := svc.InitiatePayment(ctx, "order-1", model.PaymentRequest{Method: "CARD"})
+	require.NoError(t, err)
+
+	var refundID string
+	_, err = repo.Update(ctx, "order-1", func(o *orders.Order) error {
+		refundID = o.AddRefund(money.New(50, 0), orders.RefundReasonCancellation, time.Now().UTC()).ID
+		return nil
+	})
+	require.NoError(t, err)
+
+	flaky := &flakyPSP{PSP: psp, failures: failures}
+	retry := payment.RetryPolicy{MaxAttempts: maxAttempts, Backoff: 10 * time.Millisecond}
+	return payment.NewRefundService(repo, payment.NewGateways(flaky, merchant), retry, zap.NewNop()), repo, refundID
+}
+
+// sweep runs the refund sweeper until the test ends.
+func sweep(t *testing.T, refunds *payment.RefundService) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go refunds.Run(ctx, 5*time.Millisecond)
+}
+
+func refundOf(t *testing.T, repo *orders.MemoryRepository, id string) (orders.Refund, string) {
+	t.Helper()
+	o, err := repo.Get(context.Background(), "order-1")
+	require.NoError(t, err)
+	r := o.FindRefund(id)
+	require.NotNil(t, r)
+	return *r, o.PaymentStatus
+}
+
+func TestRefundService_RetriesFailedRefunds(t *testing.T) {
+	refunds, repo, id := paidOrderWithRefund(t, 2, 5)
+
+	refunds.ProcessRefunds(context.Background(), "order-1")
+	r, status := refundOf(t, repo, id)
+	assert.Equal(t, orders.RefundPending, r.Status)
+	assert.Equal(t, 1, r.Attempts)
+	assert.Equal(t, "psp unavailable", r.LastError)
+	assert.True(t, r.NextAttemptAt.After(r.CreatedAt), "the retry is backed off")
+	assert.Equal(t, orders.PaymentPaid, status)
+
+	sweep(t, refunds)
+	assert.Eventually(t, func() bool {
+		r, _ := refundOf(t, repo, id)
+		return r.Status == orders.RefundSucceeded
+	}, 2*time.Second, 5*time.Millisecond)
+	r, status = refundOf(t, repo, id)
+	assert.Equal(t, 3, r.Attempts)
+	assert.Empty(t, r.LastError)
+	assert.NotEmpty(t, r.Reference)
+	assert.Equal(t, orders.PaymentPaid, status, "50 of 128.64 INR is a partial refund")
+
+	list, err := refunds.ListRefunds(context.Background(), "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, "50.00", list.Refunded.Value.String())
+}
+
+func TestRefundService_GivesUpAfterMaxAttempts(t *testing.T) {
+	refunds, repo, id := paidOrderWithRefund(t, 10, 2)
+
+	refunds.ProcessRefunds(context.Background(), "order-1")
+	sweep(t, refunds)
+	assert.Eventually(t, func() bool {
+		r, _ := refundOf(t, repo, id)
+		return r.Status == orders.RefundFailed
+	}, 2*time.Second, 5*time.Millisecond)
+	r, status := refundOf(t, repo, id)
+	assert.Equal(t, 2, r.Attempts)
+	assert.Equal(t, "psp unavailable", r.LastError)
+	assert.Equal(t, orders.PaymentPaid, status)
+}
+
+func TestRefundService_SurvivesCancelledRequests(t *testing.T) {
+	refunds, repo, id := paidOrderWithRefund(t, 0, 5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	refunds.ProcessRefunds(ctx, "order-1")
+	r, _ := refundOf(t, repo, id)
+	assert.Equal(t, orders.RefundSucceeded, r.Status)
+}
+
+func TestRefundService_SweepsDueRefundsFromTheStore(t *testing.T) {
+	// A refund recorded before a restart is sent by the sweep of the new
+	// process; refunds that are not due yet are left alone.
+	refunds, repo, id := paidOrderWithRefund(t, 0, 5)
+	var later string
+	_, err := repo.Update(context.Background(), "order-1", func(o *orders.Order) error {
+		r := o.AddRefund(money.New(10, 0), orders.RefundReasonCancellation, time.Now().UTC())
+		r.NextAttemptAt = time.Now().Add(time.Hour)
+		later = r.ID
+		return nil
+	})
+	require.NoError(t, err)
+
+	refunds.Sweep(context.Background())
+	r, _ := refundOf(t, repo, id)
+	assert.Equal(t, orders.RefundSucceeded, r.Status)
+	assert.Equal(t, 1, r.Attempts)
+	r, _ = refundOf(t, repo, later)
+	assert.Equal(t, orders.RefundPending, r.Status)
+	assert.Zero(t, r.Attempts)
+
+	ids, err := repo.DueRefunds(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	ids, err = repo.DueRefunds(context.Background(), time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"order-1"}, ids)
+}
+
+func TestRefundService_StopsWhenContextIsDone(t *testing.T) {
+	refunds, repo, id := paidOrderWithRefund(t, 0, 5)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		refunds.Run(ctx, time.Millisecond)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after its context was cancelled")
+	}
+
+	refunds.Sweep(ctx)
+	r, _ := refundOf(t, repo, id)
+	assert.Equal(t, orders.RefundPending, r.Status, "a stopped sweep sends nothing")
+	assert.Zero(t, r.Attempts)
+}
+
+func TestRefundService_HandleEventRejectsOtherProviders(t *testing.T) {
+	refunds, repo, id := paidOrderWithRefund(t, 0, 5)
+	refunds.ProcessRefunds(context.Background(), "order-1")
+	r, _ := refundOf(t, repo, id)
+
+	event := payment.PSPEvent{ID: "evt_1", Type: payment.EventRefundSucceeded, Refund: &payment.PSPRefund{
+		ID: r.Reference, Reference: id, OrderID: "order-1",
+	}}
+	_, _, err := refunds.HandleEvent(context.Background(), "otherpsp", event)
+	assert.True(t, apperror.IsKind(err, apperror.KindConflict), "%v", err)
+
+	_, refund, err := refunds.HandleEvent(context.Background(), "fakepsp", event)
+	require.NoError(t, err)
+	assert.Equal(t, orders.RefundSucceeded, refund.Status)
+}
@@ -0,0 +1,199 @@
+package payment_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/orders"
+	"bff-go-mvp/internal/domain/payment"
+	"bff-go-mvp/internal/fakepsp"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+)
+
+var merchant = payment.Merchant{VPA: "bluechargenet@upi", Name: "BlueChargeNet"}
+
+// newService returns a payment service over a fake PSP and a repository
+// holding one quoted order for 128.64 INR.
+func newService(t *testing.T, autoCapture bool) (*payment.MockService, *orders.MemoryRepository, *fakepsp.Server) {
+	t.Helper()
+	repo := orders.NewMemoryRepository()
+	require.NoError(t, repo.Create(context.Background(), &orders.Order{
+		ID:                    "order-1",
+		Status:                orders.StatusQuoted,
+		PaymentStatus:         orders.PaymentPending,
+		ChargingStatus:        orders.ChargingIdle,
+		Amount:                model.Amount{Value: money.New(12864, 2), Currency: "INR"},
+		AcceptedPaymentMethod: []string{"UPI", "Card"},
+	}))
+	psp := fakepsp.New(fakepsp.Options{BaseURL: "http://psp.test", AutoCapture: autoCapture})
+	gateways := payment.NewGateways(psp, merchant)
+	refunds := payment.NewRefundService(repo, gateways, payment.RetryPolicy{MaxAttempts: 3, Backoff: time.Second}, zap.NewNop())
+	return payment.NewMockService(repo, gateways, merchant, "fakepsp", refunds), repo, psp
+}
+
+func TestInitiatePayment_UPIIntentLink(t *testing.T) {
+	svc, _, _ := newService(t, true)
+
+	resp, err := svc.InitiatePayment(context.Background(), "order-1", model.PaymentRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "ACTIVE", resp.Order.Status)
+	assert.Equal(t, "PAID", resp.Payment.Status)
+	assert.Equal(t, merchant.VPA, resp.BeneficiaryID)
+	require.NotNil(t, resp.Attempt)
+	assert.Equal(t, "UPI_INTENT", resp.Attempt.Method, "the first accepted method is the default")
+	assert.Equal(t, "PAID", resp.Attempt.Status)
+
+	require.True(t, strings.HasPrefix(resp.PaymentURL, "upi://pay?"), resp.PaymentURL)
+	link, err := url.Parse(resp.PaymentURL)
+	require.NoError(t, err)
+	q := link.Query()
+	assert.Equal(t, "bluechargenet@upi", q.Get("pa"))
+	assert.Equal(t, "128.64", q.Get("am"))
+	assert.Equal(t, "INR", q.Get("cu"))
+	assert.Equal(t, resp.Attempt.Reference, q.Get("tr"))
+}
+
+func TestInitiatePayment_PendingUntilCaptured(t *testing.T) {
+	svc, repo, psp := newService(t, false)
+	ctx := context.Background()
+
+	resp, err := svc.InitiatePayment(ctx, "order-1", model.PaymentRequest{Method: "card", ReturnURL: "https://app.test/orders/order-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "quoted_price", resp.Order.Status)
+	assert.Equal(t, "PENDING", resp.Attempt.Status)
+	assert.Equal(t, "http://psp.test/checkout/"+resp.Attempt.Reference, resp.PaymentURL)
+
+	p, ok := psp.Payment(resp.Attempt.Reference)
+	require.True(t, ok)
+	assert.Equal(t, resp.Attempt.ID, p.Reference)
+	assert.Equal(t, "128.64", p.Amount.Value.String(), "the amount comes from the quote")
+
+	// Initiating again resumes the pending attempt.
+	again, err := svc.InitiatePayment(ctx, "order-1", model.PaymentRequest{Method: "CARD"})
+	require.NoError(t, err)
+	assert.Equal(t, resp.Attempt, again.Attempt)
+
+	// Another method starts a new attempt.
+	upi, err := svc.InitiatePayment(ctx, "order-1", model.PaymentRequest{Method: "UPI_COLLECT", VPA: "driver@okaxis"})
+	require.NoError(t, err)
+	assert.NotEqual(t, resp.Attempt.ID, upi.Attempt.ID)
+
+	order, err := repo.Get(ctx, "order-1")
+	require.NoError(t, err)
+	assert.Len(t, order.PaymentAttempts, 2)
+	assert.Equal(t, orders.PaymentPending, order.PaymentStatus)
+}
+
+func TestInitiatePayment_RejectsInvalidRequests(t *testing.T) {
+	svc, _, _ := newService(t, true)
+	ctx := context.Background()
+
+	for _, req := range []model.PaymentRequest{
+		{Method: "CASH"},
+		{Method: "WALLET"}, // not accepted by the order
+		{Method: "UPI_COLLECT"},
+		{Method: "UPI_COLLECT", VPA: "not-a-vpa"},
+		{Method: "CARD", ReturnURL: "/relative"},
+	} {
+		_, err := svc.InitiatePayment(ctx, "order-1", req)
+		assert.True(t, apperror.IsKind(err, apperror.KindValidation), "%+v: %v", req, err)
+	}
+
+	// A rejected UPI collect leaves nothing behind to block the next try.
+	resp, err := svc.InitiatePayment(ctx, "order-1", model.PaymentRequest{Method: "UPI_COLLECT", VPA: "buyer@bank"})
+	require.NoError(t, err)
+	assert.Equal(t, "PAID", resp.Attempt.Status)
+}
+
+// blockingPSP holds payment creation until release is closed.
+type blockingPSP struct {
+	payment.PSP
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingPSP) CreatePayment(ctx context.Context, req payment.PSPRequest) (payment.PSPPayment, error) {
+	p.started <- struct{}{}
+	<-p.release
+	return p.PSP.CreatePayment(ctx, req)
+}
+
+func TestInitiatePayment_ConcurrentInitiationsCreateOnePayment(t *testing.T) {
+	_, repo, psp := newService(t, true)
+	blocking := &blockingPSP{PSP: psp, started: make(chan struct{}, 2), release: make(chan struct{})}
+	svc := payment.NewMockService(repo, payment.NewGateways(blocking, merchant), merchant, "fakepsp", nil)
+	ctx := context.Background()
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := svc.InitiatePayment(ctx, "order-1", model.PaymentRequest{Method: "CARD"})
+		first <- err
+	}()
+	<-blocking.started
+
+	// While the first initiation waits on the PSP, a second one is turned
+	// away instead of creating another payment.
+	_, err := svc.InitiatePayment(ctx, "order-1", model.PaymentRequest{Method: "CARD"})
+	assert.True(t, apperror.IsKind(err, apperror.KindConflict), "%v", err)
+	close(blocking.release)
+	require.NoError(t, <-first)
+
+	order, err := repo.Get(ctx, "order-1")
+	require.NoError(t, err)
+	require.Len(t, order.PaymentAttempts, 1)
+	assert.Equal(t, orders.PaymentPaid, order.PaymentAttempts[0].Status)
+	assert.Len(t, blocking.started, 0, "the PSP was called once")
+}
+
+func TestHandleEvent_CaptureAndRefund(t *testing.T) {
+	svc, repo, _ := newService(t, false)
+	ctx := context.Background()
+
+	pay, err := svc.InitiatePayment(ctx, "order-1", model.PaymentRequest{Method: "CARD"})
+	require.NoError(t, err)
+	event := func(id, typ string, amount money.Decimal) payment.PSPEvent {
+		return payment.PSPEvent{ID: id, Type: typ, Payment: payment.PSPPayment{
+			ID:        pay.Attempt.Reference,
+			Reference: pay.Attempt.ID,
+			OrderID:   "order-1",
+			Amount:    model.Amount{Value: amount, Currency: "INR"},
+		}}
+	}
+
+	// The event must come from the attempt's provider.
+	_, err = svc.HandleEvent(ctx, "otherpsp", event("evt_0", payment.EventPaymentCaptured, money.New(12864, 2)))
+	assert.True(t, apperror.IsKind(err, apperror.KindConflict), "%v", err)
+
+	// The captured amount must be the attempt's.
+	_, err = svc.HandleEvent(ctx, "fakepsp", event("evt_1", payment.EventPaymentCaptured, money.New(100, 0)))
+	assert.True(t, apperror.IsKind(err, apperror.KindConflict), "%v", err)
+	_, err = svc.HandleEvent(ctx, "fakepsp", event("evt_2", "payment.disputed", money.New(12864, 2)))
+	assert.True(t, apperror.IsKind(err, apperror.KindValidation), "%v", err)
+
+	resp, err := svc.HandleEvent(ctx, "fakepsp", event("evt_3", payment.EventPaymentCaptured, money.New(12864, 2)))
+	require.NoError(t, err)
+	assert.Equal(t, "ACTIVE", resp.Order.Status)
+	assert.Equal(t, "PAID", resp.Payment.Status)
+	assert.Equal(t, "PAID", resp.Attempt.Status)
+
+	// A capture cannot be reported as failed afterwards.
+	_, err = svc.HandleEvent(ctx, "fakepsp", event("evt_4", payment.EventPaymentFailed, money.New(12864, 2)))
+	assert.True(t, apperror.IsKind(err, apperror.KindConflict), "%v", err)
+
+	// Refunds are reported by refund events only.
+	_, err = svc.HandleEvent(ctx, "fakepsp", event("evt_5", "payment.refunded", money.New(12864, 2)))
+	assert.True(t, apperror.IsKind(err, apperror.KindValidation), "%v", err)
+
+	order, err := repo.Get(ctx, "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, orders.PaymentPaid, order.PaymentStatus)
+}
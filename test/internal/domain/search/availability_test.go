@@ -0,0 +1,156 @@
+package search_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/search"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/vehicles"
+)
+
+func station(id, tz string, windows ...model.AvailabilityWindow) model.Catalog {
+	return model.Catalog{ID: id, Timezone: tz, AvailabilityWindow: windows}
+}
+
+func window(start, end string) model.AvailabilityWindow {
+	return model.AvailabilityWindow{StartTime: start, EndTime: end}
+}
+
+// hoursCatalogs returns stations with day, overnight, all-day and
+// out-of-country opening hours.
+func hoursCatalogs() []model.Catalog {
+	return []model.Catalog{
+		station("day", "", window("06:00:00", "22:00:00")),
+		station("overnight", "Asia/Kolkata", window("20:00:00", "02:00:00")),
+		station("all-day", "", window("00:00:00", "23:59:59")),
+		station("new-york", "America/New_York", window("08:00:00", "20:00:00")),
+		station("unlisted", ""),
+	}
+}
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse(time.RFC3339, s)
+	require.NoError(t, err)
+	return v
+}
+
+func applyAt(t *testing.T, req model.SearchRequest, now string) []model.Catalog {
+	t.Helper()
+	c, err := search.NewCriteria(req, mustTime(t, now), vehicles.Default())
+	require.NoError(t, err)
+	return c.Apply(hoursCatalogs())
+}
+
+func TestCriteria_TimeWindowKeepsStationsOpenThroughout(t *testing.T) {
+	// 23:00-01:30 IST is 13:30-16:00 in New York (EDT).
+	got := applyAt(t, model.SearchRequest{TimeWindow: &model.TimeWindow{
+		Start: "2026-03-10T23:00:00+05:30",
+		End:   "2026-03-11T01:30:00+05:30",
+	}}, "2026-03-10T09:00:00+05:30")
+
+	assert.Equal(t, []string{"overnight", "all-day", "new-york", "unlisted"}, catalogIDs(got))
+}
+
+func TestCriteria_TimeWindowPastClosingIsExcluded(t *testing.T) {
+	// The overnight station closes at 02:00; New York is mid-afternoon.
+	got := applyAt(t, model.SearchRequest{TimeWindow: &model.TimeWindow{
+		Start: "2026-03-11T01:00:00+05:30",
+		End:   "2026-03-11T03:00:00+05:30",
+	}}, "2026-03-10T09:00:00+05:30")
+
+	assert.Equal(t, []string{"all-day", "new-york", "unlisted"}, catalogIDs(got))
+}
+
+func TestCriteria_TimeWindowAcrossMidnightForAllDayStation(t *testing.T) {
+	got := applyAt(t, model.SearchRequest{TimeWindow: &model.TimeWindow{
+		Start: "2026-03-10T22:00:00Z",
+		End:   "2026-03-11T22:00:00Z",
+	}}, "2026-03-10T09:00:00Z")
+
+	assert.Equal(t, []string{"all-day", "unlisted"}, catalogIDs(got))
+}
+
+func TestCriteria_OpenNow(t *testing.T) {
+	got := applyAt(t, model.SearchRequest{OpenNow: true}, "2026-03-10T07:00:00+05:30")
+
+	assert.Equal(t, []string{"day", "all-day", "unlisted"}, catalogIDs(got))
+}
+
+func TestCriteria_ReportsNextOpeningOfClosedStations(t *testing.T) {
+	got := applyAt(t, model.SearchRequest{}, "2026-03-11T03:00:00+05:30")
+
+	require.Len(t, got, 5)
+	next := map[string]string{}
+	for _, c := range got {
+		next[c.ID] = c.NextOpening
+	}
+	assert.Equal(t, map[string]string{
+		"day":       "2026-03-11T06:00:00+05:30",
+		"overnight": "2026-03-11T20:00:00+05:30",
+		"all-day":   "",
+		"new-york":  "",
+		"unlisted":  "",
+	}, next)
+}
+
+func TestCriteria_NextOpeningAcrossDaylightSavingChange(t *testing.T) {
+	// New York moves to EDT at 02:00 on 8 March 2026.
+	got := applyAt(t, model.SearchRequest{}, "2026-03-07T21:00:00-05:00")
+
+	require.Equal(t, "new-york", got[3].ID)
+	assert.Equal(t, "2026-03-08T08:00:00-04:00", got[3].NextOpening)
+}
+
+func TestCriteria_UnreadableHoursKeepStation(t *testing.T) {
+	c, err := search.NewCriteria(model.SearchRequest{OpenNow: true}, time.Now(), vehicles.Default())
+	require.NoError(t, err)
+
+	got := c.Apply([]model.Catalog{
+		station("bad-zone", "Mars/Olympus", window("06:00:00", "22:00:00")),
+		station("bad-clock", "", window("six", "22:00:00")),
+	})
+
+	assert.Equal(t, []string{"bad-zone", "bad-clock"}, catalogIDs(got))
+}
+
+func TestNewCriteria_RejectsInvalidTimeWindows(t *testing.T) {
+	tests := map[string]struct {
+		req   model.SearchRequest
+		field string
+	}{
+		"open_now with window": {
+			req:   model.SearchRequest{OpenNow: true, TimeWindow: &model.TimeWindow{Start: "2026-03-10T10:00:00Z"}},
+			field: "open_now",
+		},
+		"missing start": {
+			req:   model.SearchRequest{TimeWindow: &model.TimeWindow{End: "2026-03-10T10:00:00Z"}},
+			field: "time_window.start",
+		},
+		"malformed start": {
+			req:   model.SearchRequest{TimeWindow: &model.TimeWindow{Start: "10:00"}},
+			field: "time_window.start",
+		},
+		"end before start": {
+			req:   model.SearchRequest{TimeWindow: &model.TimeWindow{Start: "2026-03-10T10:00:00Z", End: "2026-03-10T09:00:00Z"}},
+			field: "time_window.end",
+		},
+		"window too long": {
+			req:   model.SearchRequest{TimeWindow: &model.TimeWindow{Start: "2026-03-10T10:00:00Z", End: "2400-01-01T00:00:00Z"}},
+			field: "time_window.end",
+		},
+	}
+
+	for name, tt := range tests {
+		_, err := search.NewCriteria(tt.req, time.Now(), vehicles.Default())
+
+		require.Error(t, err, name)
+		assert.True(t, apperror.IsKind(err, apperror.KindValidation), name)
+		assert.Equal(t, tt.field, apperror.From(err).Details["field"], name)
+	}
+}
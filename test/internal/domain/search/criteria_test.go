@@ -0,0 +1,207 @@
+package search_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/domain/search"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/vehicles"
+)
+
+func connector(id, connectorType string, powerKW float64, amenities ...string) model.Connector {
+	return model.Connector{
+		ID: id,
+		ConnectorAttributes: model.ConnectorAttributes{
+			ConnectorType:  connectorType,
+			MaxPowerKW:     powerKW,
+			AmenityFeature: amenities,
+		},
+	}
+}
+
+func offer(id string, price float64, items ...string) model.Offer {
+	return model.Offer{ID: id, Items: items, Price: model.Price{Currency: "INR", Value: price}}
+}
+
+func ptr(v float64) *float64 { return &v }
+
+// criteriaCatalogs returns three stations with distinct connectors, prices,
+// ratings and distances.
+func criteriaCatalogs() []model.Catalog {
+	return []model.Catalog{
+		{
+			ID:             "alpha",
+			Provider:       model.Provider{ID: "ecopower-charging"},
+			Rating:         &model.Rating{Value: 4.1},
+			DistanceMeters: ptr(900),
+			Connectors: []model.Connector{
+				connector("alpha-ccs", "CCS2", 60, "Restroom", "Wi-Fi"),
+				connector("alpha-t2", "TYPE 2", 22, "Restroom"),
+			},
+			Offers: []model.Offer{offer("alpha-dc", 20, "alpha-ccs"), offer("alpha-ac", 14, "alpha-t2")},
+		},
+		{
+			ID:             "beta",
+			Provider:       model.Provider{ID: "voltgrid"},
+			Rating:         &model.Rating{Value: 4.7},
+			DistanceMeters: ptr(300),
+			Connectors:     []model.Connector{connector("beta-ccs", "CCS2", 150, "Restaurant", "Restroom", "Wi-Fi")},
+			Offers:         []model.Offer{offer("beta-dc", 24, "beta-ccs")},
+		},
+		{
+			ID:             "gamma",
+			Provider:       model.Provider{ID: "statecharge"},
+			DistanceMeters: ptr(1500),
+			Connectors:     []model.Connector{connector("gamma-bharat", "BHARAT AC-001", 3.3)},
+			Offers:         []model.Offer{offer("gamma-ac", 10, "gamma-bharat")},
+		},
+	}
+}
+
+func apply(t *testing.T, filters *model.SearchFilters, sort *model.SearchSort) []model.Catalog {
+	t.Helper()
+	c, err := search.NewCriteria(model.SearchRequest{Filters: filters, Sort: sort}, time.Now(), vehicles.Default())
+	require.NoError(t, err)
+	return c.Apply(criteriaCatalogs())
+}
+
+func TestCriteria_NoFiltersKeepsEverything(t *testing.T) {
+	assert.Equal(t, criteriaCatalogs(), apply(t, nil, nil))
+}
+
+func TestCriteria_FiltersByCPO(t *testing.T) {
+	assert.Equal(t, []string{"beta"}, catalogIDs(apply(t, &model.SearchFilters{CPO: "VoltGrid"}, nil)))
+}
+
+func TestCriteria_ConnectorTypeDropsOtherConnectorsAndTheirOffers(t *testing.T) {
+	got := apply(t, &model.SearchFilters{ConnectorType: "type_2"}, nil)
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "alpha", got[0].ID)
+	require.Len(t, got[0].Connectors, 1)
+	assert.Equal(t, "alpha-t2", got[0].Connectors[0].ID)
+	require.Len(t, got[0].Offers, 1)
+	assert.Equal(t, "alpha-ac", got[0].Offers[0].ID)
+}
+
+func TestCriteria_PowerIsAMinimum(t *testing.T) {
+	got := apply(t, &model.SearchFilters{MaxPowerKW: 60}, nil)
+
+	assert.Equal(t, []string{"alpha", "beta"}, catalogIDs(got))
+	assert.Len(t, got[0].Connectors, 1)
+}
+
+func TestCriteria_AmenitiesAreAllOf(t *testing.T) {
+	got := apply(t, &model.SearchFilters{Amenities: []string{"restroom", "Wi-Fi"}}, nil)
+
+	assert.Equal(t, []string{"alpha", "beta"}, catalogIDs(got))
+	assert.Equal(t, "alpha-ccs", got[0].Connectors[0].ID)
+	assert.Len(t, got[0].Connectors, 1)
+}
+
+func TestCriteria_VehicleCompatibility(t *testing.T) {
+	got := apply(t, &model.SearchFilters{Vehicle: &model.Vehicle{Type: "2-wheeler"}}, nil)
+	assert.Equal(t, []string{"gamma"}, catalogIDs(got))
+
+	got = apply(t, &model.SearchFilters{Vehicle: &model.Vehicle{Make: "Tata", Type: "4-wheeler"}}, nil)
+	assert.Equal(t, []string{"alpha", "beta", "gamma"}, catalogIDs(got))
+}
+
+func TestCriteria_VehicleCompatibilityFromRegistry(t *testing.T) {
+	// The Nexon EV charges from TYPE_2 and CCS2 only, unlike the 4-wheeler
+	// default.
+	got := apply(t, &model.SearchFilters{Vehicle: &model.Vehicle{Make: "tata", Model: "Nexon  EV", Type: "4-wheeler"}}, nil)
+	assert.Equal(t, []string{"alpha", "beta"}, catalogIDs(got))
+	assert.Len(t, got[0].Connectors, 2)
+
+	// A known make and model needs no type.
+	got = apply(t, &model.SearchFilters{Vehicle: &model.Vehicle{Make: "Ather", Model: "450X"}}, nil)
+	assert.Equal(t, []string{"gamma"}, catalogIDs(got))
+}
+
+func TestCriteria_PriceSortComparesOneUnit(t *testing.T) {
+	perUnit := func(id string, price float64, unit string, quantity float64) model.Offer {
+		o := offer(id, price)
+		o.Price.ApplicableQuantity = &model.ApplicableQuantity{UnitCode: unit, UnitQuantity: quantity}
+		return o
+	}
+	catalogs := []model.Catalog{
+		{ID: "per-minute", Offers: []model.Offer{perUnit("m", 2, "MIN", 1)}},
+		{ID: "per-10-kwh", Offers: []model.Offer{perUnit("k10", 150, "KWH", 10)}},
+		{ID: "per-hour", Offers: []model.Offer{perUnit("h", 90, "HUR", 1)}},
+		{ID: "no-offers"},
+		// The per-kWh offer represents the catalog, not the lower time rate.
+		{ID: "mixed", Offers: []model.Offer{perUnit("mm", 1, "MIN", 1), offer("mk", 18)}},
+		{ID: "per-kwh", Offers: []model.Offer{offer("k", 12)}},
+	}
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"asc", []string{"per-kwh", "per-10-kwh", "mixed", "per-hour", "per-minute", "no-offers"}},
+		{"desc", []string{"mixed", "per-10-kwh", "per-kwh", "per-minute", "per-hour", "no-offers"}},
+	}
+	for _, tt := range tests {
+		c, err := search.NewCriteria(model.SearchRequest{Sort: &model.SearchSort{SortBy: "price", Order: tt.order}}, time.Now(), vehicles.Default())
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, catalogIDs(c.Apply(catalogs)), tt.order)
+	}
+}
+
+func TestCriteria_Sort(t *testing.T) {
+	tests := []struct {
+		sort model.SearchSort
+		want []string
+	}{
+		{model.SearchSort{SortBy: "distance"}, []string{"beta", "alpha", "gamma"}},
+		{model.SearchSort{SortBy: "distance", Order: "desc"}, []string{"gamma", "alpha", "beta"}},
+		{model.SearchSort{SortBy: "price"}, []string{"gamma", "alpha", "beta"}},
+		{model.SearchSort{SortBy: "power", Order: "DESC"}, []string{"beta", "alpha", "gamma"}},
+		// gamma has no rating and stays last in both orders.
+		{model.SearchSort{SortBy: "rating"}, []string{"alpha", "beta", "gamma"}},
+		{model.SearchSort{SortBy: "rating", Order: "desc"}, []string{"beta", "alpha", "gamma"}},
+	}
+
+	for _, tt := range tests {
+		sort := tt.sort
+		assert.Equal(t, tt.want, catalogIDs(apply(t, nil, &sort)), "%+v", tt.sort)
+	}
+}
+
+func TestCriteria_SortsAfterFiltering(t *testing.T) {
+	// Filtering alpha down to its AC connector leaves its 14 INR offer.
+	got := apply(t, &model.SearchFilters{MaxPowerKW: 3}, &model.SearchSort{SortBy: "price"})
+	assert.Equal(t, []string{"gamma", "alpha", "beta"}, catalogIDs(got))
+
+	got = apply(t, &model.SearchFilters{ConnectorType: "CCS2"}, &model.SearchSort{SortBy: "price"})
+	assert.Equal(t, []string{"alpha", "beta"}, catalogIDs(got))
+	assert.Equal(t, 20.0, got[0].Offers[0].Price.Value)
+}
+
+func TestNewCriteria_RejectsUnsupportedValues(t *testing.T) {
+	tests := map[string]struct {
+		filters *model.SearchFilters
+		sort    *model.SearchSort
+		field   string
+	}{
+		"connector type": {filters: &model.SearchFilters{ConnectorType: "SCHUKO"}, field: "filters.connector_type"},
+		"vehicle type":   {filters: &model.SearchFilters{Vehicle: &model.Vehicle{Type: "bus"}}, field: "filters.vehicle.type"},
+		"negative power": {filters: &model.SearchFilters{MaxPowerKW: -1}, field: "filters.max_power_kw"},
+		"sort key":       {sort: &model.SearchSort{SortBy: "name"}, field: "sort.sort_by"},
+		"sort order":     {sort: &model.SearchSort{SortBy: "price", Order: "random"}, field: "sort.order"},
+	}
+
+	for name, tt := range tests {
+		_, err := search.NewCriteria(model.SearchRequest{Filters: tt.filters, Sort: tt.sort}, time.Now(), vehicles.Default())
+
+		require.Error(t, err, name)
+		assert.True(t, apperror.IsKind(err, apperror.KindValidation), name)
+		assert.Equal(t, tt.field, apperror.From(err).Details["field"], name)
+	}
+}
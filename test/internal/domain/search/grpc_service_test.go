@@ -0,0 +1,172 @@
+package search_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"bff-go-mvp/internal/domain/search"
+	"bff-go-mvp/internal/grpc"
+	"bff-go-mvp/internal/grpc/grpctest"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/transaction"
+	"bff-go-mvp/internal/vehicles"
+	discoverypb "bff-go-mvp/proto/discovery/gen"
+)
+
+func newGRPCSearchService(t *testing.T, fn grpctest.DiscoverFunc) *search.GRPCService {
+	t.Helper()
+
+	srv := grpctest.NewFuncServer(fn)
+	t.Cleanup(srv.Close)
+
+	conn, err := srv.Dial()
+	require.NoError(t, err)
+	client := grpc.NewClientFromConn(conn)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return search.NewGRPCService(client, vehicles.Default(), zap.NewNop())
+}
+
+func stationCatalog(id string) *discoverypb.Catalog {
+	attrs, _ := structpb.NewStruct(map[string]interface{}{
+		"connectorType": "TYPE_2",
+		"maxPowerKW":    60,
+		"powerType":     "DC",
+		"status":        "Available",
+	})
+	return &discoverypb.Catalog{
+		Id:       id,
+		Provider: &discoverypb.Provider{Id: "ecopower-charging", Descriptor_: &discoverypb.Descriptor{Name: "EcoPower"}},
+		Items: []*discoverypb.Item{
+			{
+				Id:             id + "-connector",
+				ItemAttributes: attrs,
+				AvailableAt: []*discoverypb.LocationItem{
+					{
+						Geo:     &discoverypb.Geo{Type: "Point", Coordinates: []float64{77.5946, 12.9716}},
+						Address: &discoverypb.Address{Full: "MG Road"},
+					},
+				},
+				Rating: &discoverypb.Rating{Value: 4.5, Count: 128},
+				Offers: []*discoverypb.Offer{
+					{
+						Id:    id + "-offer",
+						Price: &discoverypb.Price{Currency: "INR", Value: 18, ApplicableQuantity: &discoverypb.ApplicableQuantity{UnitCode: "KWH", UnitQuantity: 1}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGRPCService_Search(t *testing.T) {
+	var intent *discoverypb.Intent
+	svc := newGRPCSearchService(t, func(_ context.Context, req *discoverypb.DiscoveryRequest) (*discoverypb.DiscoveryResponse, error) {
+		intent = req.GetMessage().GetIntent()
+		return &discoverypb.DiscoveryResponse{
+			Context: req.GetContext(),
+			Message: &discoverypb.Message{
+				Catalogs: []*discoverypb.Catalog{stationCatalog("catalog-1")},
+			},
+		}, nil
+	})
+
+	resp, err := svc.Search(context.Background(), 1, 20, model.SearchRequest{
+		GeoCoordinates: []float64{12.9716, 77.5946},
+		DistanceMeters: 5000,
+		Filters:        &model.SearchFilters{ConnectorType: "TYPE_2", MaxPowerKW: 50},
+	})
+	require.NoError(t, err)
+
+	// The intent carries GeoJSON [lon, lat] ordering.
+	require.NotNil(t, intent)
+	assert.Equal(t, []float64{77.5946, 12.9716}, intent.GetCircle().GetGps().GetCoordinates())
+	assert.Equal(t, float64(5000), intent.GetCircle().GetRadiusMeters())
+	assert.Equal(t, "TYPE_2", intent.GetFilters().GetConnectorType())
+
+	assert.Equal(t, 1, resp.Total)
+	require.Len(t, resp.Catalogs, 1)
+	c := resp.Catalogs[0]
+	assert.Equal(t, "catalog-1", c.ID)
+	assert.Equal(t, "ecopower-charging", c.Provider.ID)
+	assert.Equal(t, []float64{12.9716, 77.5946}, c.Address.GeoCoordinates)
+	require.Len(t, c.Offers, 1)
+	assert.Equal(t, []string{"catalog-1-connector"}, c.Offers[0].Items)
+	assert.Equal(t, float64(18), c.Offers[0].Price.Value)
+}
+
+func TestGRPCService_Search_Paginates(t *testing.T) {
+	svc := newGRPCSearchService(t, func(_ context.Context, req *discoverypb.DiscoveryRequest) (*discoverypb.DiscoveryResponse, error) {
+		catalogs := make([]*discoverypb.Catalog, 0, 5)
+		for i := 0; i < 5; i++ {
+			catalogs = append(catalogs, stationCatalog(fmt.Sprintf("catalog-%d", i)))
+		}
+		return &discoverypb.DiscoveryResponse{Message: &discoverypb.Message{Catalogs: catalogs}}, nil
+	})
+
+	resp, err := svc.Search(context.Background(), 2, 2, model.SearchRequest{EvseID: "evse-1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, resp.Total)
+	require.Len(t, resp.Catalogs, 2)
+	assert.Equal(t, "catalog-2", resp.Catalogs[0].ID)
+	assert.Equal(t, "catalog-3", resp.Catalogs[1].ID)
+}
+
+func TestGRPCService_Search_ReappliesFilters(t *testing.T) {
+	called := false
+	svc := newGRPCSearchService(t, func(_ context.Context, req *discoverypb.DiscoveryRequest) (*discoverypb.DiscoveryResponse, error) {
+		called = true
+		return &discoverypb.DiscoveryResponse{Message: &discoverypb.Message{
+			Catalogs: []*discoverypb.Catalog{stationCatalog("catalog-1")},
+		}}, nil
+	})
+
+	// The discovery service ignored the power filter; the 60 kW station is dropped.
+	resp, err := svc.Search(context.Background(), 1, 20, model.SearchRequest{
+		EvseID:  "evse-1",
+		Filters: &model.SearchFilters{MaxPowerKW: 100},
+	})
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.Zero(t, resp.Total)
+	assert.Empty(t, resp.Catalogs)
+
+	// Unsupported values fail before any downstream call.
+	called = false
+	_, err = svc.Search(context.Background(), 1, 20, model.SearchRequest{
+		EvseID: "evse-1",
+		Sort:   &model.SearchSort{SortBy: "popularity"},
+	})
+	assert.Error(t, err)
+	assert.False(t, called)
+}
+
+func TestGRPCService_SearchPropagatesTransactionID(t *testing.T) {
+	var (
+		becknCtx *discoverypb.Context
+		md       metadata.MD
+	)
+	svc := newGRPCSearchService(t, func(ctx context.Context, req *discoverypb.DiscoveryRequest) (*discoverypb.DiscoveryResponse, error) {
+		becknCtx = req.GetContext()
+		md, _ = metadata.FromIncomingContext(ctx)
+		return &discoverypb.DiscoveryResponse{Context: req.GetContext(), Message: &discoverypb.Message{}}, nil
+	})
+
+	ctx := transaction.NewContext(context.Background(), "txn-42")
+	_, err := svc.Search(ctx, 1, 20, model.SearchRequest{EvseID: "evse-1"})
+	require.NoError(t, err)
+
+	require.NotNil(t, becknCtx)
+	assert.Equal(t, "txn-42", becknCtx.GetTransactionId())
+	assert.NotEmpty(t, becknCtx.GetMessageId())
+	assert.NotEqual(t, "txn-42", becknCtx.GetMessageId())
+	assert.Equal(t, []string{"txn-42"}, md.Get(transaction.MetadataKey))
+}
@@ -0,0 +1,126 @@
+package search_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/domain/search"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/vehicles"
+)
+
+const stationsFixture = "../../../../data/stations.geojson"
+
+func newIndexService(t *testing.T) *search.IndexService {
+	t.Helper()
+
+	stations, err := search.LoadStations(stationsFixture)
+	require.NoError(t, err)
+	svc, err := search.NewIndexService(stations, vehicles.Default())
+	require.NoError(t, err)
+	return svc
+}
+
+func catalogIDs(catalogs []model.Catalog) []string {
+	ids := make([]string, 0, len(catalogs))
+	for _, c := range catalogs {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestIndexService_SearchByRadius(t *testing.T) {
+	svc := newIndexService(t)
+
+	resp, err := svc.Search(context.Background(), 1, 20, model.SearchRequest{
+		GeoCoordinates: []float64{12.9716, 77.5946},
+		DistanceMeters: 3000,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, resp.Total)
+	assert.Equal(t, []string{
+		"catalog-ub-city",
+		"catalog-cubbon-park",
+		"catalog-mg-road-metro",
+		"catalog-lalbagh-west-gate",
+		"catalog-majestic-bus-stand",
+	}, catalogIDs(resp.Catalogs))
+
+	prev := 0.0
+	for _, c := range resp.Catalogs {
+		require.NotNil(t, c.DistanceMeters, c.ID)
+		assert.LessOrEqual(t, *c.DistanceMeters, 3000.0)
+		assert.GreaterOrEqual(t, *c.DistanceMeters, prev)
+		prev = *c.DistanceMeters
+	}
+	assert.Equal(t, []float64{12.9716, 77.5963}, resp.Catalogs[0].Address.GeoCoordinates)
+}
+
+func TestIndexService_SearchPaginates(t *testing.T) {
+	svc := newIndexService(t)
+	req := model.SearchRequest{GeoCoordinates: []float64{12.9716, 77.5946}, DistanceMeters: 3000}
+
+	resp, err := svc.Search(context.Background(), 2, 2, req)
+	require.NoError(t, err)
+	assert.Equal(t, 5, resp.Total)
+	assert.Equal(t, 2, resp.Page)
+	assert.Equal(t, 2, resp.PerPage)
+	assert.Equal(t, []string{"catalog-mg-road-metro", "catalog-lalbagh-west-gate"}, catalogIDs(resp.Catalogs))
+
+	for _, page := range []int{4, 4611686018427387904, math.MaxInt} {
+		resp, err = svc.Search(context.Background(), page, 2, req)
+		require.NoError(t, err, "page %d", page)
+		assert.Equal(t, 5, resp.Total)
+		assert.Empty(t, resp.Catalogs, "page %d", page)
+		assert.NotNil(t, resp.Catalogs)
+	}
+}
+
+func TestIndexService_SearchByEVSE(t *testing.T) {
+	svc := newIndexService(t)
+
+	resp, err := svc.Search(context.Background(), 1, 20, model.SearchRequest{EvseID: "IN*VGN*E0003*2"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, []string{"catalog-ub-city"}, catalogIDs(resp.Catalogs))
+	assert.Nil(t, resp.Catalogs[0].DistanceMeters)
+
+	resp, err = svc.Search(context.Background(), 1, 20, model.SearchRequest{EvseID: "IN*XXX*E9999*1"})
+	require.NoError(t, err)
+	assert.Zero(t, resp.Total)
+	assert.Empty(t, resp.Catalogs)
+}
+
+func TestParseStations_CatalogArray(t *testing.T) {
+	stations, err := search.ParseStations([]byte(`[
+		{"id": "a", "address": {"name": "A", "geo_coordinates": [12.97, 77.59]}},
+		{"id": "b", "address": {"name": "B", "geo_coordinates": [19.06, 72.86]}}
+	]`))
+
+	require.NoError(t, err)
+	require.Len(t, stations, 2)
+	assert.Equal(t, []float64{19.06, 72.86}, stations[1].Address.GeoCoordinates)
+}
+
+func TestParseStations_RejectsNonPointFeatures(t *testing.T) {
+	_, err := search.ParseStations([]byte(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[77.5, 12.9], [77.6, 13.0]]}, "properties": {"id": "a"}}
+	]}`))
+
+	assert.ErrorContains(t, err, "feature 0: geometry must be a Point")
+}
+
+func TestNewIndexService_RejectsInvalidCoordinates(t *testing.T) {
+	_, err := search.NewIndexService([]model.Catalog{
+		{ID: "ok", Address: model.Address{GeoCoordinates: []float64{12.97, 77.59}}},
+		{ID: "bad", Address: model.Address{GeoCoordinates: []float64{120, 77.59}}},
+	}, vehicles.Default())
+
+	assert.ErrorContains(t, err, `station "bad"`)
+}
@@ -0,0 +1,127 @@
+package fakepsp_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/domain/payment"
+	"bff-go-mvp/internal/fakepsp"
+	"bff-go-mvp/internal/httpclient"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+	"bff-go-mvp/internal/webhook"
+)
+
+func TestServer_CheckoutCallsBack(t *testing.T) {
+	events := make(chan payment.PSPEvent, 1)
+	verifier := webhook.NewVerifier(map[string]string{"fakepsp": "s3cret"}, time.Minute)
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.NoError(t, verifier.Verify("fakepsp", r.Header.Get(webhook.SignatureHeader), body), "callbacks are signed")
+		var e payment.PSPEvent
+		require.NoError(t, json.Unmarshal(body, &e))
+		events <- e
+	}))
+	defer callback.Close()
+
+	var psp *fakepsp.Server
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		psp.Handler().ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	psp = fakepsp.New(fakepsp.Options{BaseURL: srv.URL, CallbackURL: callback.URL, WebhookSecret: "s3cret"})
+
+	// The BFF's PSP client creates the payment over HTTP.
+	client := payment.NewPSPClient(httpclient.New(srv.URL, 5*time.Second))
+	require.NoError(t, client.Check(context.Background()))
+	p, err := client.CreatePayment(context.Background(), payment.PSPRequest{
+		Reference: "pay-1",
+		OrderID:   "order-1",
+		Method:    payment.MethodCard,
+		Amount:    model.Amount{Value: money.New(50000, 2), Currency: "INR"},
+		ReturnURL: "https://app.test/done",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, payment.PSPStatusCreated, p.Status)
+	assert.Equal(t, srv.URL+"/checkout/"+p.ID, p.CheckoutURL)
+
+	page, err := http.Get(p.CheckoutURL)
+	require.NoError(t, err)
+	page.Body.Close()
+	assert.Equal(t, http.StatusOK, page.StatusCode)
+
+	// Approving on the checkout page captures, calls back and returns the
+	// buyer to the app.
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := noRedirect.PostForm(p.CheckoutURL, url.Values{"outcome": {"approve"}})
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusSeeOther, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Location"), "https://app.test/done?"))
+	assert.Contains(t, resp.Header.Get("Location"), "status=CAPTURED")
+
+	select {
+	case e := <-events:
+		assert.Equal(t, payment.EventPaymentCaptured, e.Type)
+		assert.Equal(t, p.ID, e.Payment.ID)
+		assert.Equal(t, "pay-1", e.Payment.Reference)
+	case <-time.After(time.Second):
+		t.Fatal("no callback")
+	}
+
+	// A payment is completed once.
+	resp, err = noRedirect.PostForm(p.CheckoutURL, url.Values{"outcome": {"decline"}})
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestServer_Refunds(t *testing.T) {
+	psp := fakepsp.New(fakepsp.Options{})
+	srv := httptest.NewServer(psp.Handler())
+	defer srv.Close()
+	ctx := context.Background()
+	inr := func(v int64) model.Amount { return model.Amount{Value: money.New(v, 0), Currency: "INR"} }
+
+	// The BFF's PSP client refunds over HTTP.
+	client := payment.NewPSPClient(httpclient.New(srv.URL, 5*time.Second))
+	p, err := client.CreatePayment(ctx, payment.PSPRequest{
+		Reference: "pay-1", OrderID: "order-1", Method: payment.MethodCard, Amount: inr(500),
+	})
+	require.NoError(t, err)
+	again, err := client.CreatePayment(ctx, payment.PSPRequest{
+		Reference: "pay-1", OrderID: "order-1", Method: payment.MethodCard, Amount: inr(500),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, p.ID, again.ID, "payments are idempotent by reference")
+	_, err = client.CreateRefund(ctx, payment.PSPRefundRequest{Reference: "rfd-0", PaymentID: p.ID, Amount: inr(100)})
+	assert.Error(t, err, "the payment is not captured yet")
+	_, err = psp.Complete(ctx, p.ID, true)
+	require.NoError(t, err)
+
+	r, err := client.CreateRefund(ctx, payment.PSPRefundRequest{Reference: "rfd-1", PaymentID: p.ID, OrderID: "order-1", Amount: inr(200)})
+	require.NoError(t, err)
+	assert.Equal(t, payment.PSPRefundSucceeded, r.Status)
+	refundAgain, err := client.CreateRefund(ctx, payment.PSPRefundRequest{Reference: "rfd-1", PaymentID: p.ID, OrderID: "order-1", Amount: inr(200)})
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, refundAgain.ID, "refunds are idempotent by reference")
+
+	_, err = client.CreateRefund(ctx, payment.PSPRefundRequest{Reference: "rfd-2", PaymentID: p.ID, Amount: inr(301)})
+	assert.Error(t, err, "refunds cannot exceed the captured amount")
+	_, err = client.CreateRefund(ctx, payment.PSPRefundRequest{Reference: "rfd-3", PaymentID: p.ID, Amount: inr(300)})
+	require.NoError(t, err)
+	captured, ok := psp.Payment(p.ID)
+	require.True(t, ok)
+	assert.Equal(t, payment.PSPStatusRefunded, captured.Status)
+}
@@ -0,0 +1,113 @@
+package geo_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/geo"
+)
+
+func TestDistance(t *testing.T) {
+	bengaluru := geo.Point{Lat: 12.9716, Lon: 77.5946}
+	mumbai := geo.Point{Lat: 19.0760, Lon: 72.8777}
+
+	assert.InDelta(t, 845_000, geo.Distance(bengaluru, mumbai), 5_000)
+	assert.Zero(t, geo.Distance(bengaluru, bengaluru))
+	assert.InDelta(t, geo.Distance(bengaluru, mumbai), geo.Distance(mumbai, bengaluru), 1e-6)
+}
+
+func TestGeohash(t *testing.T) {
+	assert.Equal(t, "ezs42", geo.Geohash(geo.Point{Lat: 42.6, Lon: -5.6}, 5))
+	assert.Equal(t, "u4pruydqqvj", geo.Geohash(geo.Point{Lat: 57.64911, Lon: 10.40744}, 11))
+}
+
+func TestIndex_WithinOrdersByDistance(t *testing.T) {
+	center := geo.Point{Lat: 12.9716, Lon: 77.5946}
+	ix := geo.NewIndex([]geo.Item[string]{
+		{Point: geo.Point{Lat: 12.9345, Lon: 77.6112}, Value: "koramangala"},
+		{Point: geo.Point{Lat: 12.9763, Lon: 77.5929}, Value: "cubbon-park"},
+		{Point: geo.Point{Lat: 19.0607, Lon: 72.8633}, Value: "mumbai"},
+		{Point: geo.Point{Lat: 12.9719, Lon: 77.6412}, Value: "indiranagar"},
+	})
+
+	hits := ix.Within(center, 6000)
+
+	var values []string
+	for _, h := range hits {
+		values = append(values, h.Value)
+	}
+	assert.Equal(t, []string{"cubbon-park", "koramangala", "indiranagar"}, values)
+	assert.True(t, sort.SliceIsSorted(hits, func(i, j int) bool { return hits[i].DistanceMeters < hits[j].DistanceMeters }))
+	assert.Empty(t, ix.Within(center, 0))
+}
+
+func TestIndex_SameDistanceKeepsInsertionOrder(t *testing.T) {
+	p := geo.Point{Lat: 12.9716, Lon: 77.5946}
+	ix := geo.NewIndex([]geo.Item[string]{{Point: p, Value: "first"}, {Point: p, Value: "second"}})
+
+	hits := ix.Within(p, 10)
+
+	require.Len(t, hits, 2)
+	assert.Equal(t, "first", hits[0].Value)
+	assert.Equal(t, "second", hits[1].Value)
+}
+
+// TestIndex_WithinMatchesBruteForce compares radius queries against a full
+// scan, including queries that straddle the antimeridian and the poles.
+func TestIndex_WithinMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	centers := []geo.Point{
+		{Lat: 12.9716, Lon: 77.5946},
+		{Lat: 0, Lon: 179.99},
+		{Lat: -36.85, Lon: -179.95},
+		{Lat: 89.9, Lon: 10},
+		{Lat: 60.17, Lon: 24.94},
+	}
+
+	var items []geo.Item[int]
+	for i, c := range centers {
+		for j := 0; j < 400; j++ {
+			p := geo.Point{
+				Lat: clamp(c.Lat+rng.NormFloat64()*0.5, -90, 90),
+				Lon: wrap(c.Lon + rng.NormFloat64()*0.5),
+			}
+			items = append(items, geo.Item[int]{Point: p, Value: i*1000 + j})
+		}
+	}
+	ix := geo.NewIndex(items)
+	require.Equal(t, len(items), ix.Len())
+
+	for _, c := range centers {
+		for _, radius := range []float64{100, 2_000, 25_000, 80_000, 500_000} {
+			want := map[int]bool{}
+			for _, it := range items {
+				if geo.Distance(c, it.Point) <= radius {
+					want[it.Value] = true
+				}
+			}
+			got := map[int]bool{}
+			for _, h := range ix.Within(c, radius) {
+				got[h.Value] = true
+			}
+			assert.Equal(t, want, got, "center %v radius %v", c, radius)
+		}
+	}
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return max(lo, min(hi, v))
+}
+
+func wrap(lon float64) float64 {
+	switch {
+	case lon >= 180:
+		return lon - 360
+	case lon < -180:
+		return lon + 360
+	}
+	return lon
+}
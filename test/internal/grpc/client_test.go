@@ -2,34 +2,94 @@ package grpc_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"bff-go-mvp/internal/apperror"
 	"bff-go-mvp/internal/grpc"
+	"bff-go-mvp/internal/grpc/grpctest"
 	"bff-go-mvp/pkg/models"
+	discoverypb "bff-go-mvp/proto/discovery/gen"
 )
 
+func newTestClient(t *testing.T, fn grpctest.DiscoverFunc) *grpc.Client {
+	t.Helper()
+
+	srv := grpctest.NewFuncServer(fn)
+	t.Cleanup(srv.Close)
+
+	conn, err := srv.Dial()
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	client := grpc.NewClientFromConn(conn)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
 func TestClient_CallDiscoveryService(t *testing.T) {
-	client := grpc.NewClient("localhost:50051")
+	var received *discoverypb.DiscoveryRequest
+	client := newTestClient(t, func(_ context.Context, req *discoverypb.DiscoveryRequest) (*discoverypb.DiscoveryResponse, error) {
+		received = req
+		attrs, _ := structpb.NewStruct(map[string]interface{}{"connectorType": "CCS2", "maxPowerKW": 60})
+		return &discoverypb.DiscoveryResponse{
+			Context: &discoverypb.Context{
+				Action:        "on_discover",
+				TransactionId: req.GetContext().GetTransactionId(),
+				MessageId:     fmt.Sprintf("resp-%s", req.GetContext().GetMessageId()),
+			},
+			Message: &discoverypb.Message{
+				Catalogs: []*discoverypb.Catalog{
+					{
+						Id:       "catalog-1",
+						Provider: &discoverypb.Provider{Id: "provider-1"},
+						Items: []*discoverypb.Item{
+							{Id: "item-1", ItemAttributes: attrs},
+						},
+					},
+				},
+			},
+		}, nil
+	})
 
 	req := &models.DiscoveryRequest{
 		Context: models.Context{
 			Version:       "1.0.0",
-			Action:        "on_discover",
+			Action:        "discover",
 			Domain:        "mobility",
 			TransactionID: "test-txn-123",
 			MessageID:     "test-msg-456",
 		},
 		Message: models.Message{
+			Intent: &models.Intent{
+				Circle: &models.Circle{
+					Gps:          models.Geo{Type: "Point", Coordinates: []float64{77.5946, 12.9716}},
+					RadiusMeters: 5000,
+				},
+			},
 			Catalogs: []models.Catalog{},
 		},
 	}
 
-	ctx := context.Background()
-	resp, err := client.CallDiscoveryService(ctx, req)
+	resp, err := client.CallDiscoveryService(context.Background(), req)
 	if err != nil {
 		t.Fatalf("CallDiscoveryService failed: %v", err)
 	}
 
+	if received.GetMessage().GetIntent().GetCircle().GetRadiusMeters() != 5000 {
+		t.Errorf("Expected intent radius to reach the server, got %v", received.GetMessage().GetIntent())
+	}
+
 	if resp.Context.TransactionID != req.Context.TransactionID {
 		t.Errorf("Expected transaction ID %s, got %s", req.Context.TransactionID, resp.Context.TransactionID)
 	}
@@ -37,12 +97,92 @@ func TestClient_CallDiscoveryService(t *testing.T) {
 	if resp.Context.MessageID == req.Context.MessageID {
 		t.Errorf("Expected response message ID to be different from request")
 	}
+
+	if len(resp.Message.Catalogs) != 1 || len(resp.Message.Catalogs[0].Items) != 1 {
+		t.Fatalf("Expected one catalog with one item, got %+v", resp.Message.Catalogs)
+	}
+
+	attrs := resp.Message.Catalogs[0].Items[0].ItemAttributes
+	if attrs["connectorType"] != "CCS2" || attrs["maxPowerKW"] != float64(60) {
+		t.Errorf("Expected item attributes to round-trip, got %v", attrs)
+	}
+}
+
+func TestClient_CallDiscoveryService_Error(t *testing.T) {
+	client := newTestClient(t, func(context.Context, *discoverypb.DiscoveryRequest) (*discoverypb.DiscoveryResponse, error) {
+		return nil, status.Error(codes.Unavailable, "backend down")
+	})
+
+	_, err := client.CallDiscoveryService(context.Background(), &models.DiscoveryRequest{})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable error, got %v", err)
+	}
+	if !apperror.IsKind(err, apperror.KindUnavailable) {
+		t.Errorf("Expected unavailable app error, got %v", err)
+	}
 }
 
 func TestClient_Close(t *testing.T) {
-	client := grpc.NewClient("localhost:50051")
-	err := client.Close()
+	client, err := grpc.NewClient("localhost:50051")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	err = client.Close()
 	if err != nil {
 		t.Errorf("Close() should not return an error: %v", err)
 	}
 }
+
+func TestClient_PropagatesTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	var md metadata.MD
+	client := newTestClient(t, func(ctx context.Context, req *discoverypb.DiscoveryRequest) (*discoverypb.DiscoveryResponse, error) {
+		md, _ = metadata.FromIncomingContext(ctx)
+		return &discoverypb.DiscoveryResponse{Context: req.GetContext()}, nil
+	})
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	_, err := client.CallDiscoveryService(ctx, &models.DiscoveryRequest{Context: models.Context{Action: "discover"}})
+	parent.End()
+	if err != nil {
+		t.Fatalf("CallDiscoveryService failed: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[0].Name != "discovery.DiscoveryService/Discover" {
+		t.Fatalf("unexpected spans: %+v", spans)
+	}
+	rpcSpan := spans[0]
+	if rpcSpan.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("RPC span is not a child of the caller's span")
+	}
+
+	traceparent := md.Get("traceparent")
+	want := "00-" + rpcSpan.SpanContext.TraceID().String() + "-" + rpcSpan.SpanContext.SpanID().String() + "-01"
+	if len(traceparent) != 1 || traceparent[0] != want {
+		t.Errorf("Expected traceparent %s, got %v", want, traceparent)
+	}
+}
+
+func TestClient_Check(t *testing.T) {
+	client := newTestClient(t, func(_ context.Context, req *discoverypb.DiscoveryRequest) (*discoverypb.DiscoveryResponse, error) {
+		return &discoverypb.DiscoveryResponse{Context: req.GetContext()}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Check(ctx); err != nil {
+		t.Fatalf("Expected ready connection, got %v", err)
+	}
+
+	_ = client.Close()
+	if err := client.Check(ctx); err == nil {
+		t.Error("Expected error for closed connection")
+	}
+}
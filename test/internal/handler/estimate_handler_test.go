@@ -5,20 +5,25 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"bff-go-mvp/internal/config"
+	"bff-go-mvp/internal/health"
 	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
 	"bff-go-mvp/internal/router"
 )
 
 func TestEstimateHandler_Success(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := config.Load()
-	r := router.New(cfg, logger)
+	r := router.New(t.Context(), cfg, logger, health.New())
 
 	reqBody := model.EstimateRequest{
 		EvseID:      "evse-123",
@@ -36,6 +41,7 @@ func TestEstimateHandler_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/v1/estimate", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Transaction-Id", "txn-123")
+	req.Header.Set("X-Bpp-Id", "bpp-123")
 	w := httptest.NewRecorder()
 
 	r.ServeHTTP(w, req)
@@ -43,18 +49,70 @@ func TestEstimateHandler_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
 	assert.Equal(t, "txn-123", w.Header().Get("X-Transaction-Id"))
-	assert.NotEmpty(t, w.Header().Get("X-Bpp-Id"))
+	assert.Equal(t, "bpp-123", w.Header().Get("X-Bpp-Id"))
 
 	var resp model.EstimateResponse
 	err = json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.NoError(t, err)
-	assert.Equal(t, "1231208-id", resp.Order.ID)
+	assert.True(t, strings.HasPrefix(resp.Order.ID, "order-"))
+	assert.Equal(t, "quoted_price", resp.Order.Status)
 }
 
-func TestEstimateHandler_MissingTransactionID(t *testing.T) {
+// createOrder posts an estimate and returns the ID of the order it created.
+func createOrder(t *testing.T, r http.Handler) string {
+	t.Helper()
+
+	bodyBytes, err := json.Marshal(model.EstimateRequest{
+		EvseID:      "evse-123",
+		ConnectorID: "connector-456",
+		Vehicle:     model.Vehicle{Make: "TestMake", Model: "TestModel", Type: "4-wheeler"},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/estimate", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Transaction-Id", "txn-setup")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp model.EstimateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotEmpty(t, resp.Order.ID)
+	return resp.Order.ID
+}
+
+// payOrder initiates payment for an order, which settles immediately in mock mode.
+func payOrder(t *testing.T, r http.Handler, orderID string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/"+orderID+"/payment", nil)
+	req.Header.Set("X-Transaction-Id", "txn-setup")
+	req.Header.Set("X-Bpp-Id", "bpp-setup")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+// completeOrder pays for an order and runs a charging session to completion.
+func completeOrder(t *testing.T, r http.Handler, orderID string) {
+	t.Helper()
+
+	payOrder(t, r, orderID)
+	for _, action := range []string{"start", "stop"} {
+		req := httptest.NewRequest(http.MethodPut, "/v1/orders/"+orderID+"/"+action, nil)
+		req.Header.Set("X-Transaction-Id", "txn-setup")
+		req.Header.Set("X-Bpp-Id", "bpp-setup")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Less(t, w.Code, 300, "%s failed: %s", action, w.Body.String())
+	}
+}
+
+func TestEstimateHandler_GeneratesTransactionID(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := config.Load()
-	r := router.New(cfg, logger)
+	r := router.New(t.Context(), cfg, logger, health.New())
 
 	reqBody := model.EstimateRequest{
 		EvseID:      "evse-123",
@@ -73,7 +131,165 @@ func TestEstimateHandler_MissingTransactionID(t *testing.T) {
 
 	r.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusOK, w.Code)
+	_, err = uuid.Parse(w.Header().Get("X-Transaction-Id"))
+	assert.NoError(t, err)
+	assert.Empty(t, w.Header().Get("X-Bpp-Id"))
 }
 
+// postEstimate posts an estimate request to a router with the default config.
+// postEstimate posts body and asks for money in the decimal format.
+func postEstimate(t *testing.T, body model.EstimateRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	return postEstimateAs(t, money.FormatDecimal, body)
+}
+
+func postEstimateAs(t *testing.T, format string, body model.EstimateRequest) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := router.New(t.Context(), config.Load(), zap.NewNop(), health.New())
+	bodyBytes, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/estimate", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	if format != "" {
+		req.Header.Set("X-Money-Format", format)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestEstimateHandler_PricesRequestedEnergy(t *testing.T) {
+	w := postEstimate(t, model.EstimateRequest{
+		EvseID:      "evse-123",
+		ConnectorID: "ev-charger-ccs2-001",
+		OfferID:     "offer-ccs2-60kw-kwh",
+		Energy:      &model.Energy{Value: 20, Unit: "kWh"},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
 
+	var resp model.EstimateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// 20 kWh at 18 INR/kWh: 360 + 72 surge - 54 discount + 10 service fee
+	// + 9 buyer finder fee.
+	assert.Equal(t, model.Amount{Value: money.New(39700, 2), Currency: "INR"}, resp.Amount)
+	assert.Equal(t, &model.Energy{Value: 20, Unit: "kWh"}, resp.Energy)
+	// The default 4-wheeler has a 40 kWh battery charged from 20% at up to
+	// 50 kW DC, tapering above 50%.
+	assert.Equal(t, "26", resp.DurationInMinutes)
+	assert.Equal(t, "50", resp.PercentageOfBatteryCharged)
+	var total money.Decimal
+	for _, c := range resp.PriceComponents {
+		total = total.Add(c.Value)
+	}
+	assert.Equal(t, resp.Amount.Value, total)
+	if assert.NotNil(t, resp.Breakdown) {
+		assert.Equal(t, resp.Amount, resp.Breakdown.Subtotal)
+		assert.Equal(t, resp.Amount, resp.Breakdown.Total)
+	}
+}
+
+func TestEstimateHandler_MoneyFormats(t *testing.T) {
+	body := model.EstimateRequest{
+		EvseID:      "evse-123",
+		ConnectorID: "ev-charger-ccs2-001",
+		OfferID:     "offer-ccs2-60kw-kwh",
+		Energy:      &model.Energy{Value: 20, Unit: "kWh"},
+	}
+
+	decimal := postEstimateAs(t, money.FormatDecimal, body)
+	require.Equal(t, http.StatusOK, decimal.Code, decimal.Body.String())
+	assert.Contains(t, decimal.Body.String(), `"amount":{"value":"397.00","currency":"INR"}`)
+	assert.Contains(t, decimal.Body.String(), `"type":"DISCOUNT","value":"-54.00"`)
+
+	// Without the header the configured default, legacy, keeps numbers.
+	legacy := postEstimateAs(t, "", body)
+	require.Equal(t, http.StatusOK, legacy.Code, legacy.Body.String())
+	assert.Equal(t, money.FormatLegacy, legacy.Header().Get("X-Money-Format"))
+	assert.Contains(t, legacy.Body.String(), `"amount":{"value":397,"currency":"INR"}`)
+	assert.Contains(t, legacy.Body.String(), `"type":"DISCOUNT","value":-54`)
+
+	unknown := postEstimateAs(t, "float", body)
+	assert.Equal(t, http.StatusBadRequest, unknown.Code)
+}
+
+func TestEstimateHandler_PricesRequestedAmount(t *testing.T) {
+	w := postEstimate(t, model.EstimateRequest{
+		EvseID:      "evse-123",
+		ConnectorID: "ev-charger-ccs2-001",
+		Amount:      &model.Amount{Value: money.New(200, 0), Currency: "INR"},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp model.EstimateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.LessOrEqual(t, resp.Amount.Value.Cmp(money.New(200, 0)), 0)
+	assert.Equal(t, 1, resp.Amount.Value.Cmp(money.New(1999, 1)))
+	require.NotNil(t, resp.Energy)
+	assert.InDelta(t, 9.8, resp.Energy.Value, 0.1)
+}
+
+func TestEstimateHandler_RejectsUnpriceableRequests(t *testing.T) {
+	tests := map[string]struct {
+		body model.EstimateRequest
+		code int
+	}{
+		"unknown offer": {
+			body: model.EstimateRequest{EvseID: "evse-123", ConnectorID: "connector-456", OfferID: "offer-unknown"},
+			code: http.StatusNotFound,
+		},
+		"incompatible vehicle": {
+			body: model.EstimateRequest{EvseID: "evse-123", ConnectorID: "ev-charger-ccs2-001", Vehicle: model.Vehicle{Make: "Ather", Model: "450X"}},
+			code: http.StatusUnprocessableEntity,
+		},
+		"more energy than the battery takes": {
+			body: model.EstimateRequest{EvseID: "evse-123", ConnectorID: "connector-456", Energy: &model.Energy{Value: 40, Unit: "kWh"}},
+			code: http.StatusUnprocessableEntity,
+		},
+		"foreign currency": {
+			body: model.EstimateRequest{EvseID: "evse-123", ConnectorID: "connector-456", Amount: &model.Amount{Value: money.New(20, 0), Currency: "USD"}},
+			code: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for name, tt := range tests {
+		w := postEstimate(t, tt.body)
+		assert.Equal(t, tt.code, w.Code, name)
+	}
+}
+
+func TestEstimateHandler_RejectsOutOfRangeAmounts(t *testing.T) {
+	r := router.New(t.Context(), config.Load(), zap.NewNop(), health.New())
+	for _, value := range []string{`"1e20"`, `1e20`, `"999999999999999999"`} {
+		body := `{"evse_id":"evse-123","connector_id":"connector-456","amount":{"value":` + value + `,"currency":"INR"}}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/estimate", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, value)
+	}
+}
+
+func TestEstimateHandler_UsesVehicleChargingCurve(t *testing.T) {
+	soc := 60.0
+	w := postEstimate(t, model.EstimateRequest{
+		EvseID:        "evse-123",
+		ConnectorID:   "ev-charger-ccs2-001",
+		Vehicle:       model.Vehicle{Make: "Tata", Model: "Tiago EV", Type: "4-wheeler"},
+		StateOfCharge: &soc,
+		Amount:        &model.Amount{Value: money.New(5000, 0), Currency: "INR"},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp model.EstimateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// The budget covers more than the 9.6 kWh that fill the 24 kWh battery
+	// from 60%, so the estimate stops at a full battery.
+	assert.Equal(t, 9.6, resp.Energy.Value)
+	assert.Equal(t, "40", resp.PercentageOfBatteryCharged)
+	assert.Equal(t, -1, resp.Amount.Value.Cmp(money.New(5000, 0)))
+}
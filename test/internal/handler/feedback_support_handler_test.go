@@ -11,25 +11,28 @@ import (
 	"go.uber.org/zap"
 
 	"bff-go-mvp/internal/config"
+	"bff-go-mvp/internal/health"
 	"bff-go-mvp/internal/model"
 	"bff-go-mvp/internal/router"
 )
 
-func buildTestRouter() http.Handler {
+func buildTestRouter(t *testing.T) http.Handler {
 	logger := zap.NewNop()
 	cfg := config.Load()
-	return router.New(cfg, logger)
+	return router.New(t.Context(), cfg, logger, health.New())
 }
 
 func TestFeedbackHandler_SetOrderRating_Success(t *testing.T) {
-	r := buildTestRouter()
+	r := buildTestRouter(t)
+	orderID := createOrder(t, r)
+	completeOrder(t, r, orderID)
 
 	reqBody := model.RatingRequest{
 		Value: 5,
 	}
 	bodyBytes, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPost, "/v1/orders/order-123/rating", bytes.NewReader(bodyBytes))
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/"+orderID+"/rating", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Transaction-Id", "txn-1")
 	req.Header.Set("X-Bpp-Id", "bpp-1")
@@ -41,14 +44,31 @@ func TestFeedbackHandler_SetOrderRating_Success(t *testing.T) {
 	var resp model.RatingResponse
 	err := json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.NoError(t, err)
-	assert.Equal(t, "order-bpp-789012", resp.Order.ID)
+	assert.Equal(t, orderID, resp.Order.ID)
 	assert.NotNil(t, resp.FeedbackForm)
 }
 
+func TestFeedbackHandler_SetOrderRating_NotCompleted(t *testing.T) {
+	r := buildTestRouter(t)
+	orderID := createOrder(t, r)
+
+	bodyBytes, _ := json.Marshal(model.RatingRequest{Value: 5})
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/"+orderID+"/rating", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Transaction-Id", "txn-1")
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
 func TestSupportHandler_GetOrderSupport_Success(t *testing.T) {
-	r := buildTestRouter()
+	r := buildTestRouter(t)
+	orderID := createOrder(t, r)
 
-	req := httptest.NewRequest(http.MethodGet, "/v1/orders/order-123/support", nil)
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/"+orderID+"/support", nil)
 	req.Header.Set("X-Transaction-Id", "txn-1")
 	req.Header.Set("X-Bpp-Id", "bpp-1")
 	w := httptest.NewRecorder()
@@ -60,7 +80,21 @@ func TestSupportHandler_GetOrderSupport_Success(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.NoError(t, err)
 	assert.Equal(t, "BlueCharge Support Team", resp.Name)
+	assert.Equal(t, orderID, resp.Order.ID)
 	assert.Contains(t, resp.Channels, "phone")
 }
 
+func TestSupportHandler_GetOrderSupport_NotFound(t *testing.T) {
+	r := buildTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/order-unknown/support", nil)
+	req.Header.Set("X-Transaction-Id", "txn-1")
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 
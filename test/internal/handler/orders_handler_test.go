@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"bff-go-mvp/internal/config"
+	"bff-go-mvp/internal/health"
 	"bff-go-mvp/internal/model"
 	"bff-go-mvp/internal/router"
 	"encoding/json"
@@ -17,9 +18,10 @@ import (
 func TestOrdersHandler_GetOrder_Success(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := config.Load()
-	r := router.New(cfg, logger)
+	r := router.New(t.Context(), cfg, logger, health.New())
+	orderID := createOrder(t, r)
 
-	req := httptest.NewRequest(http.MethodGet, "/v1/orders/order-123", nil)
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/"+orderID, nil)
 	req.Header.Set("X-Transaction-Id", "txn-1")
 	req.Header.Set("X-Bpp-Id", "bpp-1")
 	w := httptest.NewRecorder()
@@ -33,16 +35,39 @@ func TestOrdersHandler_GetOrder_Success(t *testing.T) {
 	var resp model.OrderResponse
 	err := json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.NoError(t, err)
-	assert.Equal(t, "order-bpp-789012", resp.Order.ID)
-	assert.Equal(t, "https://track.bluechargenet-aggregator.io/session/SESSION-9876543210", resp.TrackingURL)
-	assert.NotNil(t, resp.ChargingTelemetry)
-	assert.GreaterOrEqual(t, len(resp.ChargingTelemetry.Metrics), 1)
+	assert.Equal(t, orderID, resp.Order.ID)
+	assert.Equal(t, "quoted_price", resp.Order.Status)
+	assert.Equal(t, "PENDING", resp.Payment.Status)
+	assert.Equal(t, "connector-456", resp.ConnectorID)
+	assert.Equal(t, "TestMake", resp.Vehicle.Make)
+	assert.Nil(t, resp.ChargingTelemetry)
+}
+
+func TestOrdersHandler_GetOrder_NotFound(t *testing.T) {
+	logger := zap.NewNop()
+	cfg := config.Load()
+	r := router.New(t.Context(), cfg, logger, health.New())
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/order-unknown", nil)
+	req.Header.Set("X-Transaction-Id", "txn-1")
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var resp model.Error
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "NOT_FOUND", resp.Error.Code)
+	assert.Equal(t, "order-unknown", resp.Error.Details["order_id"])
+	assert.NotEmpty(t, resp.Error.Details["timestamp"])
 }
 
 func TestOrdersHandler_GetOrder_MissingHeaders(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := config.Load()
-	r := router.New(cfg, logger)
+	r := router.New(t.Context(), cfg, logger, health.New())
 
 	req := httptest.NewRequest(http.MethodGet, "/v1/orders/order-123", nil)
 	w := httptest.NewRecorder()
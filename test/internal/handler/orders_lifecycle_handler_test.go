@@ -11,20 +11,22 @@ import (
 	"go.uber.org/zap"
 
 	"bff-go-mvp/internal/config"
+	"bff-go-mvp/internal/health"
 	"bff-go-mvp/internal/model"
 	"bff-go-mvp/internal/router"
 )
 
-func buildRouter() http.Handler {
+func buildRouter(t *testing.T) http.Handler {
 	logger := zap.NewNop()
 	cfg := config.Load()
-	return router.New(cfg, logger)
+	return router.New(t.Context(), cfg, logger, health.New())
 }
 
 func TestOrdersLifecycle_EstimateCancel_Success(t *testing.T) {
-	r := buildRouter()
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
 
-	req := httptest.NewRequest(http.MethodGet, "/v1/orders/order-123/cancel?activity=test", nil)
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/"+orderID+"/cancel?activity=test", nil)
 	req.Header.Set("X-Transaction-Id", "txn-1")
 	req.Header.Set("X-Bpp-Id", "bpp-1")
 	w := httptest.NewRecorder()
@@ -35,16 +37,18 @@ func TestOrdersLifecycle_EstimateCancel_Success(t *testing.T) {
 	var resp model.CancelEstimateResponse
 	err := json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.NoError(t, err)
-	assert.Equal(t, "order-bpp-789012", resp.Order.ID)
+	assert.Equal(t, orderID, resp.Order.ID)
+	assert.Equal(t, "quoted_price", resp.Order.Status, "estimating a cancellation does not change the order")
 }
 
 func TestOrdersLifecycle_Cancel_Success(t *testing.T) {
-	r := buildRouter()
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
 
 	body := map[string]interface{}{"reason": "user_cancel"}
 	bodyBytes, _ := json.Marshal(body)
 
-	req := httptest.NewRequest(http.MethodPost, "/v1/orders/order-123/cancel", bytes.NewReader(bodyBytes))
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/"+orderID+"/cancel", bytes.NewReader(bodyBytes))
 	req.Header.Set("X-Transaction-Id", "txn-1")
 	req.Header.Set("X-Bpp-Id", "bpp-1")
 	w := httptest.NewRecorder()
@@ -59,20 +63,38 @@ func TestOrdersLifecycle_Cancel_Success(t *testing.T) {
 }
 
 func TestOrdersLifecycle_Start_Stop_Success(t *testing.T) {
-	r := buildRouter()
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
+	payOrder(t, r, orderID)
 
 	// Start
-	startReq := httptest.NewRequest(http.MethodPut, "/v1/orders/order-123/start", nil)
+	startReq := httptest.NewRequest(http.MethodPut, "/v1/orders/"+orderID+"/start", nil)
 	startReq.Header.Set("X-Transaction-Id", "txn-1")
 	startReq.Header.Set("X-Bpp-Id", "bpp-1")
 	startW := httptest.NewRecorder()
 	r.ServeHTTP(startW, startReq)
 	assert.Equal(t, http.StatusAccepted, startW.Code)
 
+	// The order now reports an active charging session.
+	getReq := httptest.NewRequest(http.MethodGet, "/v1/orders/"+orderID, nil)
+	getReq.Header.Set("X-Transaction-Id", "txn-1")
+	getReq.Header.Set("X-Bpp-Id", "bpp-1")
+	getW := httptest.NewRecorder()
+	r.ServeHTTP(getW, getReq)
+	assert.Equal(t, http.StatusOK, getW.Code)
+
+	var getResp model.OrderResponse
+	err := json.Unmarshal(getW.Body.Bytes(), &getResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "ACTIVE", getResp.Charging.Status)
+	assert.Equal(t, "PAID", getResp.Payment.Status)
+	assert.Contains(t, getResp.TrackingURL, orderID)
+	assert.NotNil(t, getResp.ChargingTelemetry)
+
 	// Stop
 	stopBody := model.StopChargingRequest{ReasonCode: "USER", Message: "Stop now"}
 	stopBytes, _ := json.Marshal(stopBody)
-	stopReq := httptest.NewRequest(http.MethodPut, "/v1/orders/order-123/stop", bytes.NewReader(stopBytes))
+	stopReq := httptest.NewRequest(http.MethodPut, "/v1/orders/"+orderID+"/stop", bytes.NewReader(stopBytes))
 	stopReq.Header.Set("X-Transaction-Id", "txn-1")
 	stopReq.Header.Set("X-Bpp-Id", "bpp-1")
 	stopW := httptest.NewRecorder()
@@ -80,7 +102,229 @@ func TestOrdersLifecycle_Start_Stop_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, stopW.Code)
 
 	var stopResp model.StopChargingResponse
-	err := json.Unmarshal(stopW.Body.Bytes(), &stopResp)
+	err = json.Unmarshal(stopW.Body.Bytes(), &stopResp)
 	assert.NoError(t, err)
 	assert.Equal(t, "COMPLETED", stopResp.Order.Status)
+	assert.Equal(t, orderID, stopResp.Order.ID)
+
+	// The 10.2 kWh delivered cost less than the 30 kWh prepaid, so the rest
+	// is refunded and nothing is left to pay.
+	if assert.NotNil(t, stopResp.Breakdown) && assert.GreaterOrEqual(t, len(stopResp.PriceComponents), 3) {
+		n := len(stopResp.PriceComponents)
+		assert.Equal(t, "Charging cost (10.2 kWh at 18 INR/kWh)", stopResp.PriceComponents[0].Description)
+		assert.Equal(t, "PAID", stopResp.PriceComponents[n-2].Type)
+		assert.Equal(t, "REFUND", stopResp.PriceComponents[n-1].Type)
+		assert.Equal(t, 1, stopResp.Breakdown.Subtotal.Value.Sign())
+		assert.True(t, stopResp.Breakdown.Total.Value.IsZero())
+	}
+}
+
+func TestOrdersLifecycle_StopEstimateAgreesWithStop(t *testing.T) {
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
+	payOrder(t, r, orderID)
+
+	send := func(method, action string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/v1/orders/"+orderID+"/"+action, nil)
+		req.Header.Set("X-Transaction-Id", "txn-1")
+		req.Header.Set("X-Bpp-Id", "bpp-1")
+		req.Header.Set("X-Money-Format", "decimal")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	assert.Equal(t, http.StatusAccepted, send(http.MethodPut, "start").Code)
+
+	w := send(http.MethodGet, "stop")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var estimate model.StopEstimateResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &estimate))
+
+	w = send(http.MethodPut, "stop")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var stop model.StopChargingResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &stop))
+
+	assert.NotEmpty(t, stop.PriceComponents)
+	assert.Equal(t, estimate.PriceComponents, stop.PriceComponents)
+	assert.Equal(t, estimate.Breakdown, stop.Breakdown)
+}
+
+func TestOrdersLifecycle_Unplug(t *testing.T) {
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
+
+	send := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/v1/orders/"+orderID+path, nil)
+		req.Header.Set("X-Transaction-Id", "txn-1")
+		req.Header.Set("X-Bpp-Id", "bpp-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	assert.Equal(t, http.StatusConflict, send(http.MethodPut, "/unplug").Code, "nothing to unplug before charging")
+	completeOrder(t, r, orderID)
+
+	w := send(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var order model.OrderResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	assert.NotEmpty(t, order.PriceComponents, "a stopped order carries its bill")
+
+	w = send(http.MethodPut, "/unplug")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp model.UnplugResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "UNPLUGGED", resp.Charging.Status)
+	// Unplugging within the offer's 10 minute grace period costs nothing.
+	assert.Equal(t, order.PriceComponents, resp.PriceComponents)
+	for _, c := range resp.PriceComponents {
+		assert.NotEqual(t, "IDLE_FEE", c.Type)
+	}
+
+	assert.Equal(t, http.StatusConflict, send(http.MethodPut, "/unplug").Code, "the connector is unplugged once")
+}
+
+func TestOrdersLifecycle_Cancel_RefundsPaidOrder(t *testing.T) {
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
+	payOrder(t, r, orderID)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/"+orderID+"/cancel", nil)
+	req.Header.Set("X-Transaction-Id", "txn-1")
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	req.Header.Set("X-Money-Format", "decimal")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var resp model.CancelResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	types := []string{}
+	for _, c := range resp.PriceComponents {
+		types = append(types, c.Type)
+	}
+	assert.Equal(t, []string{"FEE", "PAID", "REFUND"}, types)
+	if assert.NotNil(t, resp.Breakdown) {
+		// The subtotal is the fee; the refund returns the rest of the payment.
+		assert.Equal(t, resp.PriceComponents[0].Value, resp.Breakdown.Subtotal.Value)
+		assert.Equal(t, "0.00", resp.Breakdown.Total.Value.String())
+	}
+}
+
+func TestOrdersLifecycle_Start_Unpaid(t *testing.T) {
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/orders/"+orderID+"/start", nil)
+	req.Header.Set("X-Transaction-Id", "txn-1")
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var resp model.Error
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "CONFLICT", resp.Error.Code)
+	assert.Equal(t, orderID, resp.Error.Details["order_id"])
+	assert.Equal(t, "quoted_price", resp.Error.Details["order_status"])
+	assert.Equal(t, "PENDING", resp.Error.Details["payment_status"])
+	assert.Equal(t, []interface{}{"payment", "cancel"}, resp.Error.Details["allowed_actions"])
+}
+
+func TestOrdersLifecycle_Cancel_Completed(t *testing.T) {
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
+	completeOrder(t, r, orderID)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/"+orderID+"/cancel", nil)
+	req.Header.Set("X-Transaction-Id", "txn-1")
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var resp model.Error
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "COMPLETED", resp.Error.Details["order_status"])
+	assert.Equal(t, []interface{}{"unplug", "rating"}, resp.Error.Details["allowed_actions"])
+}
+
+func TestOrdersLifecycle_Start_UnknownOrder(t *testing.T) {
+	r := buildRouter(t)
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/orders/order-unknown/start", nil)
+	req.Header.Set("X-Transaction-Id", "txn-1")
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func cancelOrder(t *testing.T, r http.Handler, method, orderID, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, "/v1/orders/"+orderID+"/cancel"+query, nil)
+	req.Header.Set("X-Transaction-Id", "txn-1")
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	req.Header.Set("X-Money-Format", "decimal")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestOrdersLifecycle_CancelAgreesWithEstimate(t *testing.T) {
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
+	payOrder(t, r, orderID)
+
+	w := cancelOrder(t, r, http.MethodGet, orderID, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var estimate model.CancelEstimateResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &estimate))
+
+	w = cancelOrder(t, r, http.MethodPost, orderID, "")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var cancel model.CancelResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &cancel))
+
+	// Booked without a time window, the reservation started at the estimate,
+	// so the no-show tier applies: 30% of the amount paid.
+	if assert.NotEmpty(t, estimate.PriceComponents) && assert.NotEmpty(t, cancel.PriceComponents) {
+		assert.Equal(t, estimate.PriceComponents[0], cancel.PriceComponents[0])
+		assert.Contains(t, estimate.PriceComponents[0].Description, "30%")
+	}
+	if assert.NotNil(t, estimate.Breakdown) && assert.NotNil(t, cancel.Breakdown) {
+		assert.Equal(t, estimate.Breakdown.Subtotal, cancel.Breakdown.Subtotal)
+		assert.Equal(t, -1, estimate.Breakdown.Total.Value.Sign(), "the rest of the payment is owed back")
+	}
+}
+
+func TestOrdersLifecycle_Cancel_IgnoresWaiverCodesOfBuyers(t *testing.T) {
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
+	payOrder(t, r, orderID)
+
+	// Without the operator role, an operator-fault code is not honoured.
+	w := cancelOrder(t, r, http.MethodGet, orderID, "?cancel_code=CHARGER_FAULT")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var estimate model.CancelEstimateResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &estimate))
+	if assert.NotNil(t, estimate.Breakdown) {
+		assert.Equal(t, 1, estimate.Breakdown.Subtotal.Value.Sign())
+	}
+
+	w = cancelOrder(t, r, http.MethodPost, orderID, "?cancel_code=CHARGER_FAULT")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var cancel model.CancelResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &cancel))
+	if assert.NotEmpty(t, cancel.PriceComponents) {
+		assert.Equal(t, 1, cancel.PriceComponents[0].Value.Sign())
+		assert.NotContains(t, cancel.PriceComponents[0].Description, "waived")
+	}
 }
@@ -5,19 +5,25 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"bff-go-mvp/internal/config"
+	"bff-go-mvp/internal/health"
+	"bff-go-mvp/internal/model"
 	"bff-go-mvp/internal/router"
 )
 
 func TestPaymentHandler_Success(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := config.Load()
-	r := router.New(cfg, logger)
+	r := router.New(t.Context(), cfg, logger, health.New())
+	orderID := createOrder(t, r)
 
 	body := map[string]interface{}{
 		"dummy": "value",
@@ -25,7 +31,7 @@ func TestPaymentHandler_Success(t *testing.T) {
 	bodyBytes, err := json.Marshal(body)
 	assert.NoError(t, err)
 
-	req := httptest.NewRequest(http.MethodPost, "/v1/orders/order-123/payment", bytes.NewReader(bodyBytes))
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/"+orderID+"/payment", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Transaction-Id", "txn-abc")
 	req.Header.Set("X-Bpp-Id", "bpp-xyz")
@@ -37,12 +43,37 @@ func TestPaymentHandler_Success(t *testing.T) {
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
 	assert.Equal(t, "txn-abc", w.Header().Get("X-Transaction-Id"))
 	assert.Equal(t, "bpp-xyz", w.Header().Get("X-Bpp-Id"))
+
+	var resp model.PaymentResponse
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, orderID, resp.Order.ID)
+	assert.Equal(t, "ACTIVE", resp.Order.Status)
+}
+
+func TestPaymentHandler_UnknownOrder(t *testing.T) {
+	logger := zap.NewNop()
+	cfg := config.Load()
+	r := router.New(t.Context(), cfg, logger, health.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/order-unknown/payment", nil)
+	req.Header.Set("X-Transaction-Id", "txn-abc")
+	req.Header.Set("X-Bpp-Id", "bpp-xyz")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var resp model.Error
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "NOT_FOUND", resp.Error.Code)
 }
 
 func TestPaymentHandler_MissingHeaders(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := config.Load()
-	r := router.New(cfg, logger)
+	r := router.New(t.Context(), cfg, logger, health.New())
 
 	req := httptest.NewRequest(http.MethodPost, "/v1/orders/order-123/payment", nil)
 	w := httptest.NewRecorder()
@@ -53,3 +84,107 @@ func TestPaymentHandler_MissingHeaders(t *testing.T) {
 }
 
 
+
+func TestPaymentHandler_WalletCheckoutPage(t *testing.T) {
+	cfg := config.Load()
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+	orderID := createOrder(t, r)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/"+orderID+"/payment", strings.NewReader(`{"method":"WALLET","wallet":"PAYTM"}`))
+	req.Header.Set("X-Bpp-Id", "bpp-xyz")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp model.PaymentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Attempt)
+	assert.Equal(t, "WALLET", resp.Attempt.Method)
+
+	// Without PAYMENT_PSP_URL the checkout page is served by the BFF itself.
+	checkout, err := url.Parse(resp.PaymentURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/psp/checkout/"+resp.Attempt.Reference, checkout.Path)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, checkout.Path, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), orderID)
+}
+
+func TestPaymentHandler_RejectsUnsupportedMethod(t *testing.T) {
+	cfg := config.Load()
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+	orderID := createOrder(t, r)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/"+orderID+"/payment", strings.NewReader(`{"method":"CASH"}`))
+	req.Header.Set("X-Bpp-Id", "bpp-xyz")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp model.Error
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "method", resp.Error.Details["field"])
+}
+
+func listRefunds(t *testing.T, r http.Handler, orderID string) model.RefundsResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/"+orderID+"/refunds", nil)
+	req.Header.Set("X-Bpp-Id", "bpp-xyz")
+	req.Header.Set("X-Money-Format", "decimal")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp model.RefundsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestPaymentHandler_ListRefunds_Cancel(t *testing.T) {
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
+	payOrder(t, r, orderID)
+	assert.Empty(t, listRefunds(t, r, orderID).Refunds)
+
+	w := cancelOrder(t, r, http.MethodPost, orderID, "")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var cancel model.CancelResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cancel))
+	refunded := cancel.PriceComponents[len(cancel.PriceComponents)-1]
+	require.Equal(t, "REFUND", refunded.Type)
+
+	resp := listRefunds(t, r, orderID)
+	assert.Equal(t, "PAID", resp.Payment.Status, "the cancellation fee is kept")
+	require.Len(t, resp.Refunds, 1)
+	refund := resp.Refunds[0]
+	assert.Equal(t, "SUCCEEDED", refund.Status)
+	assert.Equal(t, "CANCELLATION", refund.Reason)
+	assert.Equal(t, 1, refund.Attempts)
+	assert.NotEmpty(t, refund.Reference, "the PSP's refund ID")
+	assert.Equal(t, refunded.Value.String(), refund.Amount.Value.String())
+	assert.Equal(t, refund.Amount, resp.Refunded)
+}
+
+func TestPaymentHandler_ListRefunds_Stop(t *testing.T) {
+	r := buildRouter(t)
+	orderID := createOrder(t, r)
+	completeOrder(t, r, orderID)
+
+	resp := listRefunds(t, r, orderID)
+	assert.Equal(t, "COMPLETED", resp.Order.Status)
+	assert.Equal(t, "PAID", resp.Payment.Status, "only the unused amount is refunded")
+	require.Len(t, resp.Refunds, 1)
+	assert.Equal(t, "UNUSED_PREPAID", resp.Refunds[0].Reason)
+	assert.Equal(t, "SUCCEEDED", resp.Refunds[0].Status)
+	assert.Equal(t, 1, resp.Refunded.Value.Sign())
+}
+
+func TestPaymentHandler_ListRefunds_UnknownOrder(t *testing.T) {
+	r := buildRouter(t)
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/missing/refunds", nil)
+	req.Header.Set("X-Bpp-Id", "bpp-xyz")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -11,6 +11,7 @@ import (
 	"go.uber.org/zap"
 
 	"bff-go-mvp/internal/config"
+	"bff-go-mvp/internal/health"
 	"bff-go-mvp/internal/model"
 	"bff-go-mvp/internal/router"
 )
@@ -19,7 +20,7 @@ func TestSearchHandler_Success(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := config.Load()
 
-	r := router.New(cfg, logger)
+	r := router.New(t.Context(), cfg, logger, health.New())
 
 	reqBody := model.SearchRequest{
 		GeoCoordinates: []float64{12.9716, 77.5946},
@@ -49,7 +50,7 @@ func TestSearchHandler_Success(t *testing.T) {
 func TestSearchHandler_InvalidOneOf(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := config.Load()
-	r := router.New(cfg, logger)
+	r := router.New(t.Context(), cfg, logger, health.New())
 
 	// Neither evse_id nor geo_coordinates provided -> bad request
 	reqBody := model.SearchRequest{}
@@ -66,3 +67,46 @@ func TestSearchHandler_InvalidOneOf(t *testing.T) {
 }
 
 
+
+func TestSearchHandler_UnsupportedFilterValues(t *testing.T) {
+	r := router.New(t.Context(), config.Load(), zap.NewNop(), health.New())
+
+	for name, req := range map[string]model.SearchRequest{
+		"connector type": {EvseID: "evse-1", Filters: &model.SearchFilters{ConnectorType: "TYPE_9"}},
+		"vehicle type":   {EvseID: "evse-1", Filters: &model.SearchFilters{Vehicle: &model.Vehicle{Type: "truck"}}},
+		"sort key":       {EvseID: "evse-1", Sort: &model.SearchSort{SortBy: "name"}},
+		"sort order":     {EvseID: "evse-1", Sort: &model.SearchSort{SortBy: "price", Order: "up"}},
+	} {
+		bodyBytes, err := json.Marshal(req)
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/search", bytes.NewReader(bodyBytes)))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, name)
+		var body model.Error
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "VALIDATION_ERROR", body.Error.Code, name)
+		assert.NotEmpty(t, body.Error.Details["allowed"], name)
+	}
+}
+
+func TestSearchHandler_FiltersMockCatalog(t *testing.T) {
+	r := router.New(t.Context(), config.Load(), zap.NewNop(), health.New())
+
+	search := func(filters *model.SearchFilters) model.SearchResponse {
+		bodyBytes, err := json.Marshal(model.SearchRequest{EvseID: "evse-1", Filters: filters})
+		assert.NoError(t, err)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/search", bytes.NewReader(bodyBytes)))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp model.SearchResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	assert.Equal(t, 1, search(&model.SearchFilters{ConnectorType: "Type 2", MaxPowerKW: 50}).Total)
+	assert.Equal(t, 0, search(&model.SearchFilters{MaxPowerKW: 100}).Total)
+	assert.Equal(t, 0, search(&model.SearchFilters{CPO: "someone-else"}).Total)
+}
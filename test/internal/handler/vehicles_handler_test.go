@@ -0,0 +1,46 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/config"
+	"bff-go-mvp/internal/health"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/router"
+)
+
+func TestVehiclesHandler_ListsAndFilters(t *testing.T) {
+	r := router.New(t.Context(), config.Load(), zap.NewNop(), health.New())
+
+	list := func(query string) model.VehiclesResponse {
+		req := httptest.NewRequest(http.MethodGet, "/v1/vehicles"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp model.VehiclesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	all := list("")
+	assert.Equal(t, len(all.Vehicles), all.Total)
+	assert.NotZero(t, all.Total)
+
+	tata := list("?make=tata&type=4-wheeler")
+	require.NotZero(t, tata.Total)
+	for _, v := range tata.Vehicles {
+		assert.Equal(t, "Tata", v.Make)
+		assert.Positive(t, v.UsableBatteryKWh)
+		assert.NotEmpty(t, v.ChargingCurve)
+	}
+
+	assert.Equal(t, 0, list("?make=nobody").Total)
+}
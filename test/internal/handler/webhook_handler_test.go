@@ -0,0 +1,203 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/config"
+	"bff-go-mvp/internal/domain/payment"
+	"bff-go-mvp/internal/health"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/router"
+	"bff-go-mvp/internal/webhook"
+)
+
+const webhookSecret = "webhook-test-secret"
+
+// newWebhookServer serves the router over HTTP, so that the in-process fake
+// PSP can call back its webhook, with payments captured on the checkout page.
+func newWebhookServer(t *testing.T) (*httptest.Server, http.Handler) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(nil)
+	cfg := config.Load()
+	cfg.Payment.AutoCapture = false
+	cfg.Payment.PublicURL = "http://" + srv.Listener.Addr().String()
+	cfg.Payment.WebhookSecrets = map[string]string{"fakepsp": webhookSecret}
+	require.NoError(t, cfg.Validate())
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+	srv.Config.Handler = r
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv, r
+}
+
+// initiateWalletPayment starts a payment that stays pending at the PSP.
+func initiateWalletPayment(t *testing.T, r http.Handler, orderID string) model.PaymentResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/"+orderID+"/payment", strings.NewReader(`{"method":"WALLET"}`))
+	req.Header.Set("X-Bpp-Id", "bpp-xyz")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp model.PaymentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "PENDING", resp.Attempt.Status)
+	return resp
+}
+
+func paymentStatus(t *testing.T, r http.Handler, orderID string) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/"+orderID, nil)
+	req.Header.Set("X-Bpp-Id", "bpp-xyz")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var order model.OrderResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	return order.Payment.Status
+}
+
+func TestPaymentWebhook_CheckoutApprovalPaysOrder(t *testing.T) {
+	srv, r := newWebhookServer(t)
+	orderID := createOrder(t, r)
+	pay := initiateWalletPayment(t, r, orderID)
+	assert.Equal(t, "PENDING", paymentStatus(t, r, orderID))
+
+	// Approving on the checkout page makes the fake PSP call back the
+	// signed webhook before it redirects the buyer.
+	approvePayment(t, srv, pay)
+
+	assert.Equal(t, "PAID", paymentStatus(t, r, orderID))
+}
+
+func TestPaymentWebhook_SignedEvents(t *testing.T) {
+	_, r := newWebhookServer(t)
+	orderID := createOrder(t, r)
+	pay := initiateWalletPayment(t, r, orderID)
+
+	event, err := json.Marshal(payment.PSPEvent{
+		ID:        "evt_1",
+		Type:      payment.EventPaymentFailed,
+		CreatedAt: time.Now().UTC(),
+		Payment: payment.PSPPayment{
+			ID:        pay.Attempt.Reference,
+			Reference: pay.Attempt.ID,
+			OrderID:   orderID,
+			Method:    payment.MethodWallet,
+			Amount:    pay.Amount,
+			Status:    payment.PSPStatusFailed,
+		},
+	})
+	require.NoError(t, err)
+	send := func(provider, signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/webhooks/payments/"+provider, strings.NewReader(string(event)))
+		req.Header.Set(webhook.SignatureHeader, signature)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) string {
+		var resp model.Error
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Error.Code
+	}
+
+	// Unsigned, forged, stale and unknown-provider events are rejected.
+	w := send("fakepsp", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, webhook.CodeInvalidSignature, errorCode(w))
+	w = send("fakepsp", webhook.Sign("wrong-secret", time.Now(), event))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, webhook.CodeInvalidSignature, errorCode(w))
+	w = send("fakepsp", webhook.Sign(webhookSecret, time.Now().Add(-time.Hour), event))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, webhook.CodeStaleEvent, errorCode(w))
+	assert.Equal(t, http.StatusNotFound, send("acme", webhook.Sign(webhookSecret, time.Now(), event)).Code)
+	assert.Equal(t, "PENDING", paymentStatus(t, r, orderID))
+
+	// A signed failure marks the payment FAILED.
+	w = send("fakepsp", webhook.Sign(webhookSecret, time.Now(), event))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var ack model.PaymentWebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ack))
+	assert.Equal(t, "processed", ack.Status)
+	assert.Equal(t, "FAILED", ack.Payment.Status)
+	assert.Equal(t, "FAILED", ack.Attempt.Status)
+
+	// Redelivering the event is acknowledged without applying it again.
+	w = send("fakepsp", webhook.Sign(webhookSecret, time.Now(), event))
+	require.Equal(t, http.StatusOK, w.Code)
+	var dup model.PaymentWebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &dup))
+	assert.Equal(t, model.PaymentWebhookResponse{EventID: "evt_1", Status: "duplicate"}, dup)
+	assert.Equal(t, "FAILED", paymentStatus(t, r, orderID))
+}
+
+// approvePayment approves a payment on the fake PSP's checkout page, which
+// calls back the capture webhook.
+func approvePayment(t *testing.T, srv *httptest.Server, pay model.PaymentResponse) {
+	t.Helper()
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := noRedirect.PostForm(srv.URL+"/psp/checkout/"+pay.Attempt.Reference, url.Values{"outcome": {"approve"}})
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusSeeOther, resp.StatusCode)
+}
+
+func TestPaymentWebhook_CaptureAfterCancelIsRefunded(t *testing.T) {
+	srv, r := newWebhookServer(t)
+	orderID := createOrder(t, r)
+	pay := initiateWalletPayment(t, r, orderID)
+
+	w := cancelOrder(t, r, http.MethodPost, orderID, "")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	approvePayment(t, srv, pay)
+
+	resp := listRefunds(t, r, orderID)
+	assert.Equal(t, "CANCELLED", resp.Order.Status)
+	assert.Equal(t, "REFUNDED", resp.Payment.Status)
+	require.Len(t, resp.Refunds, 1)
+	assert.Equal(t, "UNAPPLIED_PAYMENT", resp.Refunds[0].Reason)
+	assert.Equal(t, "SUCCEEDED", resp.Refunds[0].Status)
+	assert.Equal(t, pay.Attempt.ID, resp.Refunds[0].PaymentAttemptID)
+	assert.Zero(t, pay.Amount.Value.Cmp(resp.Refunded.Value), "refunded %s", resp.Refunded.Value)
+}
+
+func TestPaymentWebhook_SecondCaptureIsRefunded(t *testing.T) {
+	srv, r := newWebhookServer(t)
+	orderID := createOrder(t, r)
+	wallet := initiateWalletPayment(t, r, orderID)
+
+	// The buyer switches to UPI while the wallet payment is pending.
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders/"+orderID+"/payment", strings.NewReader(`{"method":"UPI_INTENT"}`))
+	req.Header.Set("X-Bpp-Id", "bpp-xyz")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var upi model.PaymentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &upi))
+	require.NotEqual(t, wallet.Attempt.ID, upi.Attempt.ID)
+
+	// Both payments are captured.
+	approvePayment(t, srv, upi)
+	approvePayment(t, srv, wallet)
+
+	resp := listRefunds(t, r, orderID)
+	assert.Equal(t, "ACTIVE", resp.Order.Status)
+	assert.Equal(t, "PAID", resp.Payment.Status, "the UPI payment still pays the order")
+	require.Len(t, resp.Refunds, 1)
+	assert.Equal(t, "UNAPPLIED_PAYMENT", resp.Refunds[0].Reason)
+	assert.Equal(t, "SUCCEEDED", resp.Refunds[0].Status)
+	assert.Equal(t, wallet.Attempt.ID, resp.Refunds[0].PaymentAttemptID)
+	assert.Zero(t, wallet.Amount.Value.Cmp(resp.Refunded.Value), "refunded %s", resp.Refunded.Value)
+}
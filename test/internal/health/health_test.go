@@ -0,0 +1,93 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/health"
+)
+
+func ok(context.Context) error { return nil }
+
+func ready(t *testing.T, h *health.Health) (int, health.Report) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	h.ReadyHandler(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report health.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	return w.Code, report
+}
+
+func TestReady_AllChecksPass(t *testing.T) {
+	h := health.New()
+	h.Register("a", time.Second, health.CheckerFunc(ok))
+	h.Register("b", time.Second, health.CheckerFunc(ok))
+
+	code, report := ready(t, h)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Len(t, report.Checks, 2)
+	assert.Equal(t, health.StatusOK, report.Checks["a"].Status)
+}
+
+func TestReady_FailingCheck(t *testing.T) {
+	h := health.New()
+	h.Register("store", time.Second, health.CheckerFunc(ok))
+	h.Register("backend", time.Second, health.CheckerFunc(func(context.Context) error {
+		return errors.New("connection refused")
+	}))
+
+	code, report := ready(t, h)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["store"].Status)
+	assert.Equal(t, health.StatusFail, report.Checks["backend"].Status)
+	assert.Equal(t, "connection refused", report.Checks["backend"].Error)
+}
+
+func TestReady_PerCheckTimeout(t *testing.T) {
+	h := health.New()
+	h.Register("slow", 20*time.Millisecond, health.CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	start := time.Now()
+	code, report := ready(t, h)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestReady_FailsWhileDraining(t *testing.T) {
+	h := health.New()
+	h.Register("store", time.Second, health.CheckerFunc(ok))
+	h.Drain()
+
+	code, report := ready(t, h)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusShuttingDown, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["store"].Status)
+}
+
+func TestLive(t *testing.T) {
+	h := health.New()
+	h.Register("backend", time.Second, health.CheckerFunc(func(context.Context) error {
+		return errors.New("down")
+	}))
+	h.Drain()
+
+	w := httptest.NewRecorder()
+	h.LiveHandler(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
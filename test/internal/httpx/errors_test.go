@@ -0,0 +1,72 @@
+package httpx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/httpx"
+	"bff-go-mvp/internal/model"
+)
+
+func TestStatusFor(t *testing.T) {
+	cases := map[apperror.Kind]int{
+		apperror.KindBadRequest:   http.StatusBadRequest,
+		apperror.KindUnauthorized: http.StatusUnauthorized,
+		apperror.KindNotFound:     http.StatusNotFound,
+		apperror.KindConflict:     http.StatusConflict,
+		apperror.KindValidation:   http.StatusUnprocessableEntity,
+		apperror.KindInternal:     http.StatusInternalServerError,
+		apperror.Kind("unknown"):  http.StatusInternalServerError,
+	}
+	for kind, want := range cases {
+		assert.Equal(t, want, httpx.StatusFor(kind), kind)
+	}
+}
+
+func TestKindForStatus(t *testing.T) {
+	assert.Equal(t, apperror.KindNotFound, httpx.KindForStatus(http.StatusNotFound))
+	assert.Equal(t, apperror.KindValidation, httpx.KindForStatus(http.StatusUnprocessableEntity))
+	assert.Equal(t, apperror.KindBadRequest, httpx.KindForStatus(http.StatusTeapot))
+	assert.Equal(t, apperror.KindInternal, httpx.KindForStatus(http.StatusBadGateway))
+}
+
+func TestWriteServiceError_AppError(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := apperror.NotFound("Order not found.").WithDetail("order_id", "order-bpp-789012")
+
+	httpx.WriteServiceError(w, zap.NewNop(), "lookup failed", err)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var body model.Error
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "NOT_FOUND", body.Error.Code)
+	assert.Equal(t, "Order not found.", body.Error.Message)
+	assert.Equal(t, "order-bpp-789012", body.Error.Details["order_id"])
+
+	ts, ok := body.Error.Details["timestamp"].(string)
+	require.True(t, ok)
+	_, parseErr := time.Parse(time.RFC3339, ts)
+	assert.NoError(t, parseErr)
+	assert.NotContains(t, err.Details, "timestamp", "the error itself is not modified")
+}
+
+func TestWriteServiceError_PlainError(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	httpx.WriteServiceError(w, zap.NewNop(), "lookup failed", errors.New("connection refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var body model.Error
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "INTERNAL_ERROR", body.Error.Code)
+	assert.Equal(t, apperror.InternalMessage, body.Error.Message, "causes are not exposed")
+}
@@ -0,0 +1,104 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/metrics"
+)
+
+// scrape returns the exposition text of m.
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMiddleware_LabelsByRouteTemplate(t *testing.T) {
+	m := metrics.New()
+	r := mux.NewRouter()
+	r.Use(m.Middleware)
+	r.HandleFunc("/v1/orders/{order_id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods(http.MethodGet)
+
+	for _, id := range []string{"order-1", "order-2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/orders/"+id, nil))
+	}
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `bff_http_requests_total{method="GET",route="/v1/orders/{order_id}",status="404"} 2`)
+	assert.Contains(t, out, `bff_http_request_duration_seconds_count{method="GET",route="/v1/orders/{order_id}",status="404"} 2`)
+	assert.Contains(t, out, `bff_http_requests_in_flight{method="GET",route="/v1/orders/{order_id}"} 0`)
+	assert.NotContains(t, out, "order-1")
+}
+
+func TestMiddleware_UnmatchedRoute(t *testing.T) {
+	m := metrics.New()
+	m.Middleware(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/random/path", nil))
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `bff_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.NotContains(t, out, "/random/path")
+}
+
+func TestMiddleware_NonStandardMethod(t *testing.T) {
+	m := metrics.New()
+	h := m.Middleware(http.NotFoundHandler())
+	for _, method := range []string{"FOO", "BAR", http.MethodPatch} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/random/path", nil))
+	}
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `bff_http_requests_total{method="OTHER",route="unmatched",status="404"} 2`)
+	assert.Contains(t, out, `bff_http_requests_total{method="PATCH",route="unmatched",status="404"} 1`)
+	assert.NotContains(t, out, `method="FOO"`)
+}
+
+func TestBackend_ObserveOutcome(t *testing.T) {
+	m := metrics.New()
+	b := m.Backend("orders", "mock")
+
+	b.Observe("get_order", time.Now(), nil)
+	b.Observe("get_order", time.Now(), apperror.NotFound("Order not found."))
+	b.Observe("get_order", time.Now(), errors.New("boom"))
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `bff_backend_calls_total{backend="mock",domain="orders",operation="get_order",outcome="ok"} 1`)
+	assert.Contains(t, out, `bff_backend_calls_total{backend="mock",domain="orders",operation="get_order",outcome="not_found"} 1`)
+	assert.Contains(t, out, `bff_backend_calls_total{backend="mock",domain="orders",operation="get_order",outcome="internal"} 1`)
+	assert.Contains(t, out, `bff_backend_call_duration_seconds_count{backend="mock",domain="orders",operation="get_order"} 3`)
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	m := metrics.New()
+	intercept := m.UnaryClientInterceptor()
+
+	err := intercept(context.Background(), "/discovery.DiscoveryService/Discover", nil, nil, nil,
+		func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+			return status.Error(codes.Unavailable, "down")
+		})
+	require.Error(t, err)
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `bff_grpc_client_calls_total{code="Unavailable",method="/discovery.DiscoveryService/Discover"} 1`)
+	assert.Contains(t, out, `bff_grpc_client_call_duration_seconds_count{method="/discovery.DiscoveryService/Discover"} 1`)
+}
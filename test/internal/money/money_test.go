@@ -0,0 +1,112 @@
+package money_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]string{
+		"128.64": "128.64",
+		"-0.5":   "-0.5",
+		"30":     "30",
+		"+7.10":  "7.10",
+		".25":    "0.25",
+		"1.5e2":  "150",
+	}
+	for in, want := range tests {
+		d, err := money.Parse(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, d.String(), in)
+	}
+
+	for _, in := range []string{"", "-", "1.2.3", "12a", "1,5", "-+5", "+-5", "--5"} {
+		_, err := money.Parse(in)
+		assert.Error(t, err, in)
+		assert.NotErrorIs(t, err, money.ErrOutOfRange, in)
+	}
+
+	for _, in := range []string{"1234567890123456789", "1e20", "-1e20", "1e400", "1000000000", "999999999999999999", "0.1234567890"} {
+		_, err := money.Parse(in)
+		assert.ErrorIs(t, err, money.ErrOutOfRange, in)
+	}
+}
+
+func TestParse_BoundsFitEveryScale(t *testing.T) {
+	for _, in := range []string{"999999999.999999999", "-999999999.999999999", "000000000000123.5"} {
+		d, err := money.Parse(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, d.Sign(), d.Round(money.MaxScale).Sign(), in)
+		assert.Equal(t, d.Sign(), d.Round(2).Sign(), in)
+	}
+
+	d, err := money.Parse("123456789.5")
+	require.NoError(t, err)
+	assert.Equal(t, "123456789.50", d.Round(2).String())
+}
+
+func TestOverflowPanics(t *testing.T) {
+	assert.PanicsWithError(t, "money: 92233720368547758.07 + 0.01: amount out of range", func() {
+		money.New(9223372036854775807, 2).Add(money.New(1, 2))
+	})
+	assert.Panics(t, func() { money.New(123456789012345678, 0).Round(2) })
+	assert.Panics(t, func() { money.FromFloat(1e20, 2) })
+}
+
+func TestArithmeticIsExact(t *testing.T) {
+	// 0.1 + 0.2 is 0.30000000000000004 in float64.
+	sum := money.New(1, 1).Add(money.New(2, 1))
+	assert.Equal(t, "0.3", sum.String())
+	assert.Equal(t, 0, sum.Cmp(money.New(30, 2)))
+
+	assert.Equal(t, "-2.75", money.New(25, 2).Sub(money.New(3, 0)).String())
+	assert.Equal(t, -1, money.New(-1, 0).Sign())
+	assert.True(t, money.New(0, 2).IsZero())
+}
+
+func TestRound(t *testing.T) {
+	assert.Equal(t, "0.13", money.Of(0.125, "INR").String())
+	assert.Equal(t, "-0.13", money.Of(-0.125, "INR").String())
+	assert.Equal(t, "2.68", money.Of(2.675, "INR").String())
+	assert.Equal(t, "3", money.Of(2.5, "jpy").String())
+	assert.Equal(t, "1.235", money.Of(1.2345, "KWD").String())
+	assert.Equal(t, "30.00", money.In(money.New(30, 0), "INR").String())
+	assert.Equal(t, 2, money.MinorUnits("XYZ"))
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(model.Amount{Value: money.New(12864, 2), Currency: "INR"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value":"128.64","currency":"INR"}`, string(data))
+
+	for _, in := range []string{`{"value":"128.64","currency":"INR"}`, `{"value":128.64,"currency":"INR"}`} {
+		var a model.Amount
+		require.NoError(t, json.Unmarshal([]byte(in), &a), in)
+		assert.Equal(t, money.New(12864, 2), a.Value, in)
+	}
+
+	var a model.Amount
+	assert.Error(t, json.Unmarshal([]byte(`{"value":"12,50"}`), &a))
+}
+
+func TestAsNumbers(t *testing.T) {
+	v := model.StopChargingResponse{
+		Order: model.OrderInfo{ID: "order-1"},
+		PriceComponents: []model.PriceComponent{
+			{Type: "UNIT", Value: money.New(2000, 2), Currency: "INR", Description: "Charging cost"},
+			{Type: "REFUND", Value: money.New(-30000, 2), Currency: "INR", Description: "Refund"},
+		},
+	}
+
+	data, err := money.AsNumbers(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"order":{"id":"order-1","mode":"","status":""},"priceComponents":[`+
+		`{"type":"UNIT","value":20,"currency":"INR","description":"Charging cost"},`+
+		`{"type":"REFUND","value":-300,"currency":"INR","description":"Refund"}]}`, string(data))
+}
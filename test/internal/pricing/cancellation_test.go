@@ -0,0 +1,67 @@
+package pricing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/pricing"
+)
+
+var cancellation = pricing.CancellationPolicy{
+	FreeBefore: 2 * time.Hour,
+	Tiers: []pricing.CancellationTier{
+		{Within: time.Hour, Percent: 10},
+		{Within: 15 * time.Minute, Percent: 20},
+		{Within: 0, Percent: 50},
+	},
+	MinimumFee:  25,
+	WaiverCodes: []string{"CHARGER_FAULT"},
+}
+
+func TestCancellationPolicy_Evaluate(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	paid := model.Amount{Value: dec("400.00"), Currency: "INR"}
+
+	tests := []struct {
+		name   string
+		c      pricing.Cancellation
+		fee    string
+		refund string
+	}{
+		{"free well ahead", pricing.Cancellation{Paid: paid, Start: start, At: start.Add(-3 * time.Hour)}, "0.00", "400.00"},
+		{"free at the cutoff", pricing.Cancellation{Paid: paid, Start: start, At: start.Add(-2 * time.Hour)}, "0.00", "400.00"},
+		{"minimum fee between cutoff and tiers", pricing.Cancellation{Paid: paid, Start: start, At: start.Add(-90 * time.Minute)}, "25.00", "375.00"},
+		{"first tier", pricing.Cancellation{Paid: paid, Start: start, At: start.Add(-time.Hour)}, "40.00", "360.00"},
+		{"last 15 minutes", pricing.Cancellation{Paid: paid, Start: start, At: start.Add(-10 * time.Minute)}, "80.00", "320.00"},
+		{"no-show", pricing.Cancellation{Paid: paid, Start: start, At: start.Add(5 * time.Minute)}, "200.00", "200.00"},
+		{"operator fault", pricing.Cancellation{Paid: paid, Start: start, At: start.Add(5 * time.Minute), Code: "charger_fault", Operator: true}, "0.00", "400.00"},
+		{"operator fault as reason", pricing.Cancellation{Paid: paid, Start: start, At: start, Reason: "CHARGER_FAULT", Operator: true}, "0.00", "400.00"},
+		{"operator fault claimed by the buyer", pricing.Cancellation{Paid: paid, Start: start, At: start.Add(5 * time.Minute), Code: "CHARGER_FAULT"}, "200.00", "200.00"},
+		{"unpaid", pricing.Cancellation{Paid: model.Amount{Currency: "INR"}, Start: start, At: start}, "0.00", "0.00"},
+		{"fee capped at amount paid", pricing.Cancellation{Paid: model.Amount{Value: dec("20.00"), Currency: "INR"}, Start: start, At: start.Add(-time.Hour)}, "20.00", "0.00"},
+	}
+
+	for _, tt := range tests {
+		q := cancellation.Evaluate(tt.c)
+		assert.Equal(t, tt.fee, q.Fee.String(), tt.name)
+		assert.Equal(t, tt.refund, q.Refund.String(), tt.name)
+		assert.NotEmpty(t, q.Rule, tt.name)
+	}
+}
+
+func TestCancellationPolicy_Describe(t *testing.T) {
+	d := cancellation.Describe("INR")
+
+	assert.Equal(t, "50", d.Fee.Percentage)
+	assert.Equal(t, 120, d.FreeBeforeMinutes)
+	assert.Equal(t, []model.CancellationTier{
+		{WithinMinutes: 60, Percentage: "10"},
+		{WithinMinutes: 15, Percentage: "20"},
+		{WithinMinutes: 0, Percentage: "50"},
+	}, d.Tiers)
+	assert.Equal(t, &model.Amount{Value: dec("25.00"), Currency: "INR"}, d.MinimumFee)
+	assert.Equal(t, []string{"CHARGER_FAULT"}, d.FeeWaivedFor)
+}
@@ -0,0 +1,91 @@
+package pricing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/pricing"
+)
+
+func TestParseIdleFee(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   model.IdleFee
+	}{
+		{"₹2/min after 10 min post-charge", model.IdleFee{Rate: 2, Currency: "INR", Unit: "MIN", GracePeriodMinutes: 10}},
+		{"INR 120 per hour after 15 minutes, max ₹500", model.IdleFee{Rate: 120, Currency: "INR", Unit: "HUR", GracePeriodMinutes: 15, Cap: 500}},
+		{"Rs. 1.5/min, capped at 200", model.IdleFee{Rate: 1.5, Currency: "INR", Unit: "MIN", Cap: 200}},
+		{"3 per minute after 1 hour", model.IdleFee{Rate: 3, Currency: "INR", Unit: "MIN", GracePeriodMinutes: 60}},
+		{"Free", model.IdleFee{Currency: "INR", Unit: "MIN"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			got, err := pricing.ParseIdleFee(tt.policy, "INR")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseIdleFee_RejectsUnreadablePolicies(t *testing.T) {
+	for _, policy := range []string{"", "idle fees apply", "₹2 after 10 min", "$2/min"} {
+		_, err := pricing.ParseIdleFee(policy, "INR")
+		assert.Error(t, err, policy)
+	}
+	_, err := pricing.ParseIdleFee("2/min", "")
+	assert.Error(t, err, "no currency")
+}
+
+func TestIdleFeeOf(t *testing.T) {
+	offer := model.Offer{ID: "offer-1", Price: model.Price{Currency: "INR", Value: 18}}
+	fee, err := pricing.IdleFeeOf(offer)
+	require.NoError(t, err)
+	assert.Nil(t, fee)
+
+	offer.OfferAttributes = &model.OfferAttributes{IdleFeePolicy: "₹2/min after 10 min post-charge"}
+	fee, err = pricing.IdleFeeOf(offer)
+	require.NoError(t, err)
+	assert.Equal(t, &model.IdleFee{Rate: 2, Currency: "INR", Unit: "MIN", GracePeriodMinutes: 10}, fee)
+
+	// The structured fee takes precedence over the legacy string.
+	offer.OfferAttributes.IdleFee = &model.IdleFee{Rate: 60, Currency: "inr", Unit: "hur"}
+	fee, err = pricing.IdleFeeOf(offer)
+	require.NoError(t, err)
+	assert.Equal(t, &model.IdleFee{Rate: 60, Currency: "INR", Unit: "HUR"}, fee)
+
+	offer.OfferAttributes.IdleFee = &model.IdleFee{Rate: 1, Currency: "USD", Unit: "MIN"}
+	_, err = pricing.IdleFeeOf(offer)
+	assert.True(t, apperror.IsKind(err, apperror.KindValidation), "idle fee in another currency")
+
+	offer.OfferAttributes = &model.OfferAttributes{IdleFeePolicy: "ask the attendant"}
+	_, err = pricing.IdleFeeOf(offer)
+	assert.True(t, apperror.IsKind(err, apperror.KindValidation))
+}
+
+func TestIdleCharge(t *testing.T) {
+	fee := model.IdleFee{Rate: 2, Currency: "INR", Unit: "MIN", GracePeriodMinutes: 10, Cap: 50}
+
+	_, ok := pricing.IdleCharge(fee, 10*time.Minute)
+	assert.False(t, ok, "within the grace period")
+
+	c, ok := pricing.IdleCharge(fee, 14*time.Minute+time.Second)
+	require.True(t, ok)
+	assert.Equal(t, pricing.ComponentIdleFee, c.Type)
+	assert.Equal(t, "10.00", c.Value.String(), "every started minute is billed")
+	assert.Equal(t, "Idle fee (5 min at 2 INR/min after 10 min grace)", c.Description)
+
+	c, ok = pricing.IdleCharge(fee, 2*time.Hour)
+	require.True(t, ok)
+	assert.Equal(t, "50.00", c.Value.String())
+	assert.Equal(t, "Idle fee (110 min at 2 INR/min after 10 min grace, capped at 50.00 INR)", c.Description)
+
+	c, ok = pricing.IdleCharge(model.IdleFee{Rate: 90, Currency: "INR", Unit: "HUR"}, 20*time.Minute)
+	require.True(t, ok)
+	assert.Equal(t, "30.00", c.Value.String())
+	assert.Equal(t, "Idle fee (20 min at 90 INR/h)", c.Description)
+}
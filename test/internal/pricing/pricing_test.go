@@ -0,0 +1,265 @@
+package pricing_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/money"
+	"bff-go-mvp/internal/pricing"
+)
+
+// offer returns an offer priced per unit, with a 2.5% buyer finder fee.
+func offer(currency string, price float64, unit string, quantity float64) model.Offer {
+	return model.Offer{
+		ID: "offer-1",
+		Price: model.Price{
+			Currency:           currency,
+			Value:              price,
+			ApplicableQuantity: &model.ApplicableQuantity{UnitCode: unit, UnitQuantity: quantity},
+		},
+		OfferAttributes: &model.OfferAttributes{
+			BuyerFinderFee: &model.BuyerFinderFee{FeeType: "PERCENTAGE", FeeValue: 2.5},
+		},
+	}
+}
+
+var policy = pricing.Policy{
+	Surcharges:  []pricing.Adjustment{{Description: "Surge price", Percent: 20}},
+	Discounts:   []pricing.Adjustment{{Description: "Offer discount", Percent: 15}},
+	ServiceFees: []pricing.Adjustment{{Description: "Service fee", Fixed: 10}},
+}
+
+// dec parses a decimal literal; "540.00" is 540 at the scale of INR.
+func dec(s string) money.Decimal {
+	d, err := money.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func sum(components []model.PriceComponent) money.Decimal {
+	var total money.Decimal
+	for _, c := range components {
+		total = total.Add(c.Value)
+	}
+	return total
+}
+
+func TestPrice_Energy(t *testing.T) {
+	q, err := pricing.Price(pricing.Request{
+		Offer:   offer("INR", 18, "KWH", 1),
+		Profile: pricing.ConstantPower(60),
+		Energy:  &model.Energy{Value: 30, Unit: "kWh"},
+		Policy:  policy,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []model.PriceComponent{
+		{Type: "UNIT", Value: dec("540.00"), Currency: "INR", Description: "Charging cost (30 kWh at 18 INR/kWh)"},
+		{Type: "SURCHARGE", Value: dec("108.00"), Currency: "INR", Description: "Surge price (20%)"},
+		{Type: "DISCOUNT", Value: dec("-81.00"), Currency: "INR", Description: "Offer discount (15%)"},
+		{Type: "FEE", Value: dec("10.00"), Currency: "INR", Description: "Service fee"},
+		{Type: "FEE", Value: dec("13.50"), Currency: "INR", Description: "Buyer finder fee (2.5%)"},
+	}, q.Components)
+	assert.Equal(t, model.Amount{Value: dec("590.50"), Currency: "INR"}, q.Amount)
+	assert.Equal(t, model.Energy{Value: 30, Unit: "kWh"}, q.Energy)
+	assert.Equal(t, 30, q.DurationInMinutes)
+}
+
+func TestPrice_RoundsEachComponent(t *testing.T) {
+	q, err := pricing.Price(pricing.Request{
+		Offer:   offer("INR", 18.5, "KWH", 1),
+		Profile: pricing.ConstantPower(7.4),
+		Energy:  &model.Energy{Value: 12345, Unit: "Wh"},
+		Policy:  pricing.Policy{Surcharges: policy.Surcharges},
+	})
+	require.NoError(t, err)
+
+	// 228.3825 + 45.6765 + 5.7095625 rounds to 228.38 + 45.68 + 5.71.
+	values := []string{}
+	for _, c := range q.Components {
+		values = append(values, c.Value.String())
+	}
+	assert.Equal(t, []string{"228.38", "45.68", "5.71"}, values)
+	assert.Equal(t, dec("279.77"), q.Amount.Value)
+	assert.Equal(t, 12.345, q.Energy.Value)
+	// 100.1 minutes is billed as 101 started minutes.
+	assert.Equal(t, 101, q.DurationInMinutes)
+}
+
+func TestPrice_ZeroDecimalCurrency(t *testing.T) {
+	q, err := pricing.Price(pricing.Request{
+		Offer:   offer("JPY", 40, "KWH", 1),
+		Profile: pricing.ConstantPower(50),
+		Energy:  &model.Energy{Value: 10.55, Unit: "kWh"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, dec("422"), q.Components[0].Value)
+	assert.Equal(t, dec("11"), q.Components[1].Value)
+	assert.Equal(t, model.Amount{Value: dec("433"), Currency: "JPY"}, q.Amount)
+}
+
+func TestPrice_TimeTariffs(t *testing.T) {
+	tests := []struct {
+		name      string
+		offer     model.Offer
+		unit      string
+		describes string
+	}{
+		{"per minute", offer("INR", 3, "MIN", 1), "90.00", "Charging cost (30 min at 3 INR/min)"},
+		{"per 15 minutes", offer("INR", 10, "MIN", 15), "20.00", "Charging cost (30 min at 10 INR/15 min)"},
+		{"per hour", offer("INR", 150, "HUR", 1), "75.00", "Charging cost (0.5 h at 150 INR/h)"},
+	}
+
+	for _, tt := range tests {
+		q, err := pricing.Price(pricing.Request{
+			Offer:   tt.offer,
+			Profile: pricing.ConstantPower(22),
+			Energy:  &model.Energy{Value: 11, Unit: "kWh"},
+		})
+		require.NoError(t, err, tt.name)
+
+		assert.Equal(t, 30, q.DurationInMinutes, tt.name)
+		assert.Equal(t, tt.unit, q.Components[0].Value.String(), tt.name)
+		assert.Equal(t, tt.describes, q.Components[0].Description, tt.name)
+	}
+}
+
+func TestPrice_AmountBuysAsMuchAsTheBudgetCovers(t *testing.T) {
+	req := pricing.Request{
+		Offer:   offer("INR", 18, "KWH", 1),
+		Profile: pricing.ConstantPower(60),
+		Amount:  &model.Amount{Value: dec("500"), Currency: "inr"},
+		Policy:  policy,
+	}
+	q, err := pricing.Price(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 25.323, q.Energy.Value)
+	assert.Equal(t, model.Amount{Value: dec("500.00"), Currency: "INR"}, q.Amount)
+	assert.Equal(t, 26, q.DurationInMinutes)
+
+	// One more watt-hour would exceed the budget.
+	more, err := pricing.Price(pricing.Request{
+		Offer:   req.Offer,
+		Profile: req.Profile,
+		Energy:  &model.Energy{Value: 25.324, Unit: "kWh"},
+		Policy:  policy,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, more.Amount.Value.Cmp(dec("500")))
+}
+
+func TestPrice_AmountWithTimeTariff(t *testing.T) {
+	q, err := pricing.Price(pricing.Request{
+		Offer:   offer("INR", 3, "MIN", 1),
+		Profile: pricing.ConstantPower(30),
+		Amount:  &model.Amount{Value: dec("100"), Currency: "INR"},
+	})
+	require.NoError(t, err)
+
+	// 32 minutes cost 96 + 2.40 = 98.40; 33 would cost 101.48.
+	assert.Equal(t, 32, q.DurationInMinutes)
+	assert.Equal(t, 16.0, q.Energy.Value)
+	assert.Equal(t, dec("98.40"), q.Amount.Value)
+}
+
+func TestPrice_ComponentsSumToTotal(t *testing.T) {
+	for _, kwh := range []float64{0.001, 0.333, 1.005, 7.777, 12.345, 33.3, 99.999} {
+		for _, o := range []model.Offer{offer("INR", 17.35, "KWH", 1), offer("JPY", 41, "KWH", 1), offer("KWD", 0.065, "MIN", 1)} {
+			q, err := pricing.Price(pricing.Request{
+				Offer:   o,
+				Profile: pricing.ConstantPower(11),
+				Energy:  &model.Energy{Value: kwh, Unit: "kWh"},
+				Policy:  policy,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, sum(q.Components).String(), q.Amount.Value.String(), "%v kWh in %s", kwh, o.Price.Currency)
+		}
+	}
+}
+
+func TestPrice_RejectsInvalidRequests(t *testing.T) {
+	valid := offer("INR", 18, "KWH", 1)
+	energy := &model.Energy{Value: 10, Unit: "kWh"}
+
+	tests := map[string]struct {
+		req   pricing.Request
+		field string
+	}{
+		"no quantity":       {pricing.Request{Offer: valid, Profile: pricing.ConstantPower(60)}, "energy"},
+		"energy and amount": {pricing.Request{Offer: valid, Profile: pricing.ConstantPower(60), Energy: energy, Amount: &model.Amount{Value: dec("100"), Currency: "INR"}}, "amount"},
+		"energy unit":       {pricing.Request{Offer: valid, Profile: pricing.ConstantPower(60), Energy: &model.Energy{Value: 10, Unit: "kcal"}}, "energy.unit"},
+		"zero energy":       {pricing.Request{Offer: valid, Profile: pricing.ConstantPower(60), Energy: &model.Energy{Unit: "kWh"}}, "energy.value"},
+		"amount currency":   {pricing.Request{Offer: valid, Profile: pricing.ConstantPower(60), Amount: &model.Amount{Value: dec("100"), Currency: "USD"}}, "amount.currency"},
+		"amount below fees": {pricing.Request{Offer: valid, Profile: pricing.ConstantPower(60), Amount: &model.Amount{Value: dec("5"), Currency: "INR"}, Policy: policy}, "amount.value"},
+		"no power":          {pricing.Request{Offer: valid, Energy: energy}, "connector_id"},
+		"unsupported unit":  {pricing.Request{Offer: offer("INR", 18, "LTR", 1), Profile: pricing.ConstantPower(60), Energy: energy}, "offer_id"},
+	}
+
+	for name, tt := range tests {
+		_, err := pricing.Price(tt.req)
+
+		require.Error(t, err, name)
+		assert.True(t, apperror.IsKind(err, apperror.KindValidation), name)
+		assert.Equal(t, tt.field, apperror.From(err).Details["field"], name)
+	}
+}
+
+func TestBreakdown(t *testing.T) {
+	components := []model.PriceComponent{
+		pricing.Component(pricing.ComponentUnit, dec("540"), "INR", "Charging cost"),
+		pricing.Component(pricing.ComponentDiscount, dec("-81"), "INR", "Offer discount"),
+		pricing.Component(pricing.ComponentTax, dec("82.62"), "INR", "GST"),
+		pricing.Component(pricing.ComponentPaid, dec("-600"), "INR", "Amount paid"),
+		pricing.Component(pricing.ComponentRefund, dec("58.38"), "INR", "Refund"),
+	}
+
+	b, err := pricing.Breakdown("INR", components)
+	require.NoError(t, err)
+	assert.Equal(t, model.Amount{Value: dec("541.62"), Currency: "INR"}, b.Subtotal)
+	assert.Equal(t, model.Amount{Value: dec("0.00"), Currency: "INR"}, b.Total)
+
+	empty, err := pricing.Breakdown("JPY", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "0", empty.Total.Value.String())
+}
+
+func TestBreakdown_RejectsInvalidComponents(t *testing.T) {
+	tests := map[string]model.PriceComponent{
+		"unknown type":      {Type: "Pending Payment", Value: dec("10.00"), Currency: "INR"},
+		"negative charge":   {Type: pricing.ComponentFee, Value: dec("-10.00"), Currency: "INR"},
+		"positive discount": {Type: pricing.ComponentDiscount, Value: dec("10.00"), Currency: "INR"},
+		"positive payment":  {Type: pricing.ComponentPaid, Value: dec("10.00"), Currency: "INR"},
+		"negative refund":   {Type: pricing.ComponentRefund, Value: dec("-10.00"), Currency: "INR"},
+		"other currency":    {Type: pricing.ComponentUnit, Value: dec("10.00"), Currency: "USD"},
+	}
+
+	for name, c := range tests {
+		_, err := pricing.Breakdown("INR", []model.PriceComponent{c})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestPriceUsage(t *testing.T) {
+	// Time tariffs bill the started minute; energy is billed to the Wh.
+	q, err := pricing.PriceUsage(offer("INR", 3, "MIN", 1), pricing.Policy{}, pricing.Usage{Energy: 7.12345, Minutes: 20.2})
+	require.NoError(t, err)
+	assert.Equal(t, 21, q.DurationInMinutes)
+	assert.Equal(t, 7.123, q.Energy.Value)
+	assert.Equal(t, dec("63.00"), q.Components[0].Value)
+	assert.Equal(t, dec("64.58"), q.Amount.Value)
+
+	empty, err := pricing.PriceUsage(offer("INR", 18, "KWH", 1), policy, pricing.Usage{})
+	require.NoError(t, err)
+	assert.Equal(t, dec("10.00"), empty.Amount.Value, "only the fixed service fee")
+
+	_, err = pricing.PriceUsage(offer("INR", 18, "KWH", 1), policy, pricing.Usage{Energy: -1})
+	assert.Error(t, err)
+}
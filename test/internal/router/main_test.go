@@ -0,0 +1,15 @@
+package router_test
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain runs the tests against the in-process fake PSP, which captures
+// payments as soon as they are initiated.
+func TestMain(m *testing.M) {
+	os.Setenv("ENV", "development")
+	os.Setenv("PAYMENT_FAKE_PSP", "true")
+	os.Setenv("PAYMENT_AUTO_CAPTURE", "true")
+	os.Exit(m.Run())
+}
@@ -0,0 +1,433 @@
+package router_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+
+	"bff-go-mvp/internal/config"
+	"bff-go-mvp/internal/health"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/router"
+)
+
+func TestRouter_HTTPBackendMode(t *testing.T) {
+	var gotPath, gotQuery string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(model.SearchResponse{
+			Total:    1,
+			Page:     2,
+			PerPage:  5,
+			Catalogs: []model.Catalog{{ID: "from-http-backend"}},
+		})
+	}))
+	defer backend.Close()
+
+	cfg := config.Load()
+	cfg.Backend.Search = config.BackendModeHTTP
+	cfg.Backend.HTTPBaseURL = backend.URL
+	require.NoError(t, cfg.Validate())
+
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+
+	body, _ := json.Marshal(model.SearchRequest{EvseID: "evse-1"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/search?page=2&per_page=5", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/v1/search", gotPath)
+	assert.Equal(t, "page=2&per_page=5", gotQuery)
+
+	var resp model.SearchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Catalogs, 1)
+	assert.Equal(t, "from-http-backend", resp.Catalogs[0].ID)
+}
+
+func TestRouter_IndexBackendMode(t *testing.T) {
+	cfg := config.Load()
+	cfg.Backend.Search = config.BackendModeIndex
+	cfg.Backend.StationsFile = "../../../data/stations.geojson"
+	require.NoError(t, cfg.Validate())
+
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+
+	body, _ := json.Marshal(model.SearchRequest{GeoCoordinates: []float64{12.9716, 77.5946}, DistanceMeters: 1000})
+	req := httptest.NewRequest(http.MethodPost, "/v1/search", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp model.SearchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Total)
+	require.Len(t, resp.Catalogs, 2)
+	assert.Equal(t, "catalog-ub-city", resp.Catalogs[0].ID)
+	require.NotNil(t, resp.Catalogs[0].DistanceMeters)
+	assert.InDelta(t, 184, *resp.Catalogs[0].DistanceMeters, 5)
+
+	// The estimate prices the offer of the searched station's connector.
+	body, _ = json.Marshal(model.EstimateRequest{EvseID: "IN*VGN*E0003*1", ConnectorID: "ub-city-c1"})
+	req = httptest.NewRequest(http.MethodPost, "/v1/estimate", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var est model.EstimateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &est))
+	require.NotEmpty(t, est.PriceComponents)
+	assert.Equal(t, "Charging cost (30 kWh at 22 INR/kWh)", est.PriceComponents[0].Description)
+	assert.Equal(t, 660.0, est.PriceComponents[0].Value.Float64())
+}
+
+func TestRouter_HTTPBackendError(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer backend.Close()
+
+	cfg := config.Load()
+	cfg.Backend.Support = config.BackendModeHTTP
+	cfg.Backend.HTTPBaseURL = backend.URL
+
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/order-1/support", nil)
+	req.Header.Set("X-Transaction-Id", "txn-1")
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestRouter_HTTPBackendClientErrorPassesThrough(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(model.Error{Error: model.ErrorBody{
+			Code:    "CONFLICT",
+			Message: "Order cannot be started in its current state or charging already in progress.",
+			Details: map[string]interface{}{"order_id": "order-1"},
+		}})
+	}))
+	defer backend.Close()
+
+	cfg := config.Load()
+	cfg.Backend.Lifecycle = config.BackendModeHTTP
+	cfg.Backend.HTTPBaseURL = backend.URL
+
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/orders/order-1/start", nil)
+	req.Header.Set("X-Transaction-Id", "txn-1")
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var resp model.Error
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "CONFLICT", resp.Error.Code)
+	assert.Equal(t, "order-1", resp.Error.Details["order_id"])
+}
+
+func TestRouter_AuthEnabled(t *testing.T) {
+	const secret = "router-test-secret-of-32-bytes-min"
+
+	cfg := config.Load()
+	cfg.Auth.Enabled = true
+	cfg.Auth.HS256Secret = secret
+	require.NoError(t, cfg.Validate())
+
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+
+	// Probes, metrics and Swagger stay public.
+	for _, path := range []string{"/health", "/livez", "/readyz", "/metrics", "/swagger/index.html"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.NotEqual(t, http.StatusUnauthorized, w.Code, path)
+	}
+
+	body, _ := json.Marshal(model.SearchRequest{EvseID: "evse-1"})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/search", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/search", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRouter_OrderOwnership(t *testing.T) {
+	const secret = "router-test-secret-of-32-bytes-min"
+
+	cfg := config.Load()
+	cfg.Auth.Enabled = true
+	cfg.Auth.HS256Secret = secret
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+
+	token := func(sub string, roles ...string) string {
+		tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":   sub,
+			"roles": roles,
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return "Bearer " + tok
+	}
+	do := func(method, path, auth string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Authorization", auth)
+		req.Header.Set("X-Transaction-Id", "txn-1")
+		req.Header.Set("X-Bpp-Id", "bpp-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	body, _ := json.Marshal(model.EstimateRequest{EvseID: "evse-1", ConnectorID: "c-1"})
+	w := do(http.MethodPost, "/v1/estimate", token("alice"), body)
+	require.Equal(t, http.StatusOK, w.Code)
+	var est model.EstimateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &est))
+	orderPath := "/v1/orders/" + est.Order.ID
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, orderPath, token("alice"), nil).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, orderPath, token("ops", "operator"), nil).Code)
+
+	// Another user cannot read, pay for or cancel the order, and cannot tell it exists.
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, orderPath, token("mallory"), nil).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, orderPath+"/payment", token("mallory"), nil).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, orderPath+"/cancel", token("mallory"), nil).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, orderPath+"/support", token("mallory"), nil).Code)
+
+	// The order is untouched by the rejected calls.
+	w = do(http.MethodGet, orderPath, token("alice"), nil)
+	var order model.OrderResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	assert.Equal(t, "quoted_price", order.Order.Status)
+
+	// Only operators waive the cancellation fee with an operator-fault code.
+	waived := func(auth string) bool {
+		w := do(http.MethodGet, orderPath+"/cancel?cancel_code=CHARGER_FAULT", auth, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var estimate model.CancelEstimateResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &estimate))
+		require.NotEmpty(t, estimate.PriceComponents)
+		return strings.Contains(estimate.PriceComponents[0].Description, "waived")
+	}
+	assert.False(t, waived(token("alice")))
+	assert.True(t, waived(token("ops", "operator")))
+}
+
+func TestRouter_TransactionIDOnEveryResponse(t *testing.T) {
+	var forwarded string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get("X-Transaction-Id")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer backend.Close()
+
+	cfg := config.Load()
+	cfg.Backend.Support = config.BackendModeHTTP
+	cfg.Backend.HTTPBaseURL = backend.URL
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"success", http.MethodGet, "/health", http.StatusOK},
+		{"validation error", http.MethodGet, "/v1/orders/order-1", http.StatusBadRequest},
+		{"downstream error", http.MethodGet, "/v1/orders/order-1/support", http.StatusInternalServerError},
+		{"unknown route", http.MethodGet, "/v1/unknown", http.StatusNotFound},
+		{"method not allowed", http.MethodDelete, "/v1/search", http.StatusMethodNotAllowed},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.path == "/v1/orders/order-1/support" {
+				req.Header.Set("X-Bpp-Id", "bpp-1")
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.NotEmpty(t, w.Header().Get("X-Transaction-Id"))
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/order-1/support", nil)
+	req.Header.Set("X-Transaction-Id", "txn-forward")
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "txn-forward", w.Header().Get("X-Transaction-Id"))
+	assert.Equal(t, "txn-forward", forwarded)
+}
+
+func TestRouter_MetricsEndpoint(t *testing.T) {
+	cfg := config.Load()
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+
+	// An unknown order exercises both the HTTP and the backend call metrics.
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/order-missing", nil)
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	out := w.Body.String()
+	assert.Contains(t, out, `bff_http_requests_total{method="GET",route="/v1/orders/{order_id}",status="404"} 1`)
+	assert.Contains(t, out, `bff_backend_calls_total{backend="mock",domain="orders",operation="get_order",outcome="not_found"} 1`)
+	assert.NotContains(t, out, "order-missing")
+}
+
+func TestRouter_TracesDomainCallsUnderRequestSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	r := router.New(t.Context(), config.Load(), zap.NewNop(), health.New())
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/order-missing", nil)
+	req.Header.Set("X-Bpp-Id", "bpp-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+	assert.Equal(t, "orders.get_order", child.Name)
+	assert.Equal(t, "GET /v1/orders/{order_id}", server.Name)
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+	assert.Equal(t, server.SpanContext.TraceID(), child.SpanContext.TraceID())
+}
+
+func TestRouter_Readiness(t *testing.T) {
+	probes := health.New()
+	r := router.New(t.Context(), config.Load(), zap.NewNop(), probes)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["order_store"].Status)
+	assert.Equal(t, health.StatusOK, report.Checks["payment_gateway"].Status)
+
+	probes.Drain()
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRouter_ReadinessFailsWhenPSPUnreachable(t *testing.T) {
+	cfg := config.Load()
+	cfg.Payment.FakePSP = false
+	cfg.Payment.PSPURL = "http://127.0.0.1:1"
+	cfg.API.HealthCheckTimeout = 200 * time.Millisecond
+
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusFail, report.Checks["payment_gateway"].Status)
+}
+
+func TestRouter_ReadinessFailsWhenDiscoveryUnreachable(t *testing.T) {
+	cfg := config.Load()
+	cfg.Backend.Search = config.BackendModeGRPC
+	cfg.GRPC.ServiceAddress = "127.0.0.1:1"
+	cfg.API.HealthCheckTimeout = 200 * time.Millisecond
+
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, health.StatusFail, report.Checks["grpc_discovery"].Status)
+	assert.NotEmpty(t, report.Checks["grpc_discovery"].Error)
+	assert.Equal(t, health.StatusOK, report.Checks["order_store"].Status)
+}
+
+func TestRouter_FakePSPNotMountedByDefault(t *testing.T) {
+	// Outside development authentication is required, with keys from a JWKS.
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	set, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "router-test",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	require.NoError(t, err)
+	jwks := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwks, set, 0o600))
+
+	cfg := config.Load()
+	cfg.Env = "production"
+	cfg.Payment.FakePSP = false
+	cfg.Payment.PSPURL = "http://127.0.0.1:1"
+	cfg.Auth.Enabled = true
+	cfg.Auth.JWKSFile = jwks
+	require.NoError(t, cfg.Validate())
+
+	r := router.New(t.Context(), cfg, zap.NewNop(), health.New())
+	for _, path := range []string{"/psp/health", "/psp/checkout/pay_1"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
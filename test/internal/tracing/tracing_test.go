@@ -0,0 +1,81 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"bff-go-mvp/internal/config"
+	"bff-go-mvp/internal/tracing"
+)
+
+// installExporter routes the global tracer provider to an in-memory
+// exporter for the duration of the test.
+func installExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	_, err := tracing.Setup(context.Background(), config.TracingConfig{Exporter: config.TraceExporterNone})
+	require.NoError(t, err)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider(config.TracingConfig{ServiceName: "test", SampleRatio: 1}, sdktrace.NewSimpleSpanProcessor(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		otel.SetTracerProvider(prev)
+	})
+	return exporter
+}
+
+func newRouter(status int) *mux.Router {
+	r := mux.NewRouter()
+	r.Use(tracing.Middleware)
+	r.HandleFunc("/v1/orders/{order_id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}).Methods(http.MethodGet)
+	return r
+}
+
+func TestMiddleware_NamesSpanAfterRouteTemplate(t *testing.T) {
+	exporter := installExporter(t)
+
+	newRouter(http.StatusOK).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/orders/order-1", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /v1/orders/{order_id}", spans[0].Name)
+	assert.Contains(t, spans[0].Attributes, attribute.String("http.route", "/v1/orders/{order_id}"))
+	assert.Contains(t, spans[0].Attributes, attribute.Int("http.response.status_code", http.StatusOK))
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+}
+
+func TestMiddleware_ContinuesIncomingTrace(t *testing.T) {
+	exporter := installExporter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/order-1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	newRouter(http.StatusInternalServerError).ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
+
+func TestSetup_NoneExporter(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{Exporter: config.TraceExporterNone})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
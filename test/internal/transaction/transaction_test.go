@@ -0,0 +1,91 @@
+package transaction_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"bff-go-mvp/internal/transaction"
+)
+
+// serve runs the middleware around a handler that records the context ID.
+func serve(t *testing.T, header string) (*httptest.ResponseRecorder, string) {
+	t.Helper()
+
+	var seen string
+	h := transaction.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = transaction.FromContext(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set(transaction.Header, header)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w, seen
+}
+
+func TestMiddleware_AcceptsIncomingID(t *testing.T) {
+	w, seen := serve(t, "txn-123")
+
+	assert.Equal(t, "txn-123", seen)
+	assert.Equal(t, "txn-123", w.Header().Get(transaction.Header))
+	assert.Equal(t, http.StatusTeapot, w.Code)
+}
+
+func TestMiddleware_GeneratesMissingID(t *testing.T) {
+	w, seen := serve(t, "")
+
+	_, err := uuid.Parse(seen)
+	assert.NoError(t, err)
+	assert.Equal(t, seen, w.Header().Get(transaction.Header))
+}
+
+func TestMiddleware_ReplacesMalformedID(t *testing.T) {
+	for _, id := range []string{"has space", "bad\x7fbyte", strings.Repeat("a", 129)} {
+		w, seen := serve(t, id)
+
+		assert.NotEqual(t, id, seen)
+		_, err := uuid.Parse(seen)
+		assert.NoError(t, err, "id %q", id)
+		assert.Equal(t, seen, w.Header().Get(transaction.Header))
+	}
+}
+
+func TestLogger_AddsTransactionField(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	base := zap.New(core)
+
+	transaction.Logger(transaction.NewContext(context.Background(), "txn-1"), base).Info("with id")
+	transaction.Logger(context.Background(), base).Info("without id")
+
+	entries := logs.All()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "txn-1", entries[0].ContextMap()[transaction.LogField])
+	assert.NotContains(t, entries[1].ContextMap(), transaction.LogField)
+}
+
+func TestLogger_AddsTraceIDs(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9},
+		SpanID:  trace.SpanID{0x01},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	transaction.Logger(ctx, zap.New(core)).Info("traced")
+
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, sc.TraceID().String(), fields["trace_id"])
+	assert.Equal(t, sc.SpanID().String(), fields["span_id"])
+}
@@ -0,0 +1,238 @@
+package translator_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/translator"
+	"bff-go-mvp/pkg/models"
+)
+
+// discoveryFixture is a Beckn on_discover payload as it arrives over the wire.
+const discoveryFixture = `{
+  "context": {"action": "on_discover", "transaction_id": "txn-1", "message_id": "msg-1"},
+  "message": {
+    "catalogs": [
+      {
+        "@context": "https://becknprotocol.io/schemas/core/v2",
+        "@type": "beckn:Catalog",
+        "beckn:id": "catalog-ev-charging-001",
+        "beckn:descriptor": {"name": "EcoPower Stations"},
+        "beckn:provider": {
+          "beckn:id": "ecopower-charging",
+          "beckn:descriptor": {"name": "EcoPower Charging Pvt Ltd"},
+          "beckn:address": {"full": "MG Road, Bengaluru"}
+        },
+        "beckn:items": [
+          {
+            "@type": "beckn:Item",
+            "beckn:id": "ev-charger-ccs2-001",
+            "beckn:itemAttributes": {
+              "@context": "https://example.org/ev-charging.jsonld",
+              "@type": "ChargingService",
+              "connectorType": "CCS2",
+              "maxPowerKW": 60,
+              "minPowerKW": "5",
+              "socketCount": 2,
+              "reservationSupported": true,
+              "evseId": "IN*ECO*BTM*01*CCS2*A",
+              "powerType": "DC",
+              "connectorFormat": "CABLE",
+              "chargingSpeed": "FAST",
+              "status": "Available",
+              "amenityFeature": ["Restroom", "Wi-Fi"],
+              "availabilityWindow": [{"startTime": "06:00:00", "endTime": "22:00:00"}],
+              "vendorSpecificFlag": "x"
+            },
+            "beckn:availableAt": [
+              {"geo": {"type": "Point", "coordinates": [77.5946, 12.9716]}, "address": {"full": "MG JVLR Jogeshwari Caves Road"}}
+            ],
+            "beckn:rating": {"value": 4.0, "count": 100},
+            "beckn:offers": [
+              {
+                "beckn:id": "offer-ccs2-60kw-kwh",
+                "beckn:descriptor": {"name": "Per-kWh Tariff - CCS2 60kW"},
+                "beckn:price": {"currency": "INR", "value": 18, "applicableQuantity": {"unitText": "Kilowatt Hour", "unitCode": "KWH", "unitQuantity": 1}},
+                "beckn:validity": {"schema:startDate": "2025-10-01T00:00:00Z", "schema:endDate": "2026-03-31T23:59:59Z"},
+                "beckn:acceptedPaymentMethod": ["UPI", "Card"],
+                "beckn:offerAttributes": {
+                  "buyerFinderFee": {"feeType": "PERCENTAGE", "feeValue": 2.5},
+                  "idleFeePolicy": "₹2/min after 10 min post-charge"
+                }
+              }
+            ]
+          },
+          {
+            "beckn:id": "ev-charger-type2-002",
+            "beckn:itemAttributes": {
+              "connectorType": "TYPE_2",
+              "maxPowerKW": "fast",
+              "powerType": "AC",
+              "status": "OutOfOrder"
+            },
+            "beckn:rating": {"value": 5.0, "count": 28}
+          }
+        ]
+      }
+    ]
+  }
+}`
+
+func loadFixture(t *testing.T) *models.DiscoveryResponse {
+	t.Helper()
+	var resp models.DiscoveryResponse
+	require.NoError(t, json.Unmarshal([]byte(discoveryFixture), &resp))
+	return &resp
+}
+
+func TestDiscoveryToCatalogs_MapsCatalog(t *testing.T) {
+	catalogs, _ := translator.DiscoveryToCatalogs(loadFixture(t))
+	require.Len(t, catalogs, 1)
+
+	c := catalogs[0]
+	assert.Equal(t, "catalog-ev-charging-001", c.ID)
+	assert.Equal(t, "ecopower-charging", c.Provider.ID)
+	assert.Equal(t, "EcoPower Charging Pvt Ltd", c.Provider.Descriptor.Name)
+	assert.Equal(t, "MG JVLR Jogeshwari Caves Road", c.Address.Name)
+	assert.Equal(t, []float64{12.9716, 77.5946}, c.Address.GeoCoordinates)
+	assert.Equal(t, []string{"DC", "AC"}, c.AvailablePowerType)
+	require.Len(t, c.AvailabilityWindow, 1)
+	assert.Equal(t, "06:00:00", c.AvailabilityWindow[0].StartTime)
+
+	// Ratings are weighted by count across items.
+	require.NotNil(t, c.Rating)
+	assert.Equal(t, 128, c.Rating.Count)
+	assert.InDelta(t, 4.2, c.Rating.Value, 0.001)
+}
+
+func TestDiscoveryToCatalogs_MapsConnectorAttributes(t *testing.T) {
+	catalogs, _ := translator.DiscoveryToCatalogs(loadFixture(t))
+	require.Len(t, catalogs[0].Connectors, 2)
+
+	conn := catalogs[0].Connectors[0]
+	assert.Equal(t, "ev-charger-ccs2-001", conn.ID)
+	assert.True(t, conn.IsActive)
+	attrs := conn.ConnectorAttributes
+	assert.Equal(t, "CCS2", attrs.ConnectorType)
+	assert.Equal(t, float64(60), attrs.MaxPowerKW)
+	assert.Equal(t, float64(5), attrs.MinPowerKW)
+	assert.Equal(t, 2, attrs.SocketCount)
+	assert.True(t, attrs.ReservationSupported)
+	assert.Equal(t, "IN*ECO*BTM*01*CCS2*A", attrs.EvseID)
+	assert.Equal(t, "Available", attrs.Status)
+	assert.Equal(t, []string{"Restroom", "Wi-Fi"}, attrs.AmenityFeature)
+
+	assert.False(t, catalogs[0].Connectors[1].IsActive, "OutOfOrder connectors are inactive")
+}
+
+func TestDiscoveryToCatalogs_MapsOffers(t *testing.T) {
+	catalogs, _ := translator.DiscoveryToCatalogs(loadFixture(t))
+	require.Len(t, catalogs[0].Offers, 1)
+
+	offer := catalogs[0].Offers[0]
+	assert.Equal(t, "offer-ccs2-60kw-kwh", offer.ID)
+	assert.Equal(t, []string{"ev-charger-ccs2-001"}, offer.Items)
+	assert.Equal(t, "ecopower-charging", offer.Provider)
+	assert.Equal(t, float64(18), offer.Price.Value)
+	require.NotNil(t, offer.Price.ApplicableQuantity)
+	assert.Equal(t, "KWH", offer.Price.ApplicableQuantity.UnitCode)
+	require.NotNil(t, offer.Validity)
+	assert.Equal(t, "2026-03-31T23:59:59Z", offer.Validity.EndDate)
+	require.NotNil(t, offer.OfferAttributes)
+	require.NotNil(t, offer.OfferAttributes.BuyerFinderFee)
+	assert.Equal(t, 2.5, offer.OfferAttributes.BuyerFinderFee.FeeValue)
+	assert.Equal(t, "₹2/min after 10 min post-charge", offer.OfferAttributes.IdleFeePolicy)
+	assert.Equal(t, &model.IdleFee{Rate: 2, Currency: "INR", Unit: "MIN", GracePeriodMinutes: 10},
+		offer.OfferAttributes.IdleFee)
+}
+
+func TestDiscoveryToCatalogs_ReportsIssues(t *testing.T) {
+	_, report := translator.DiscoveryToCatalogs(loadFixture(t))
+	require.True(t, report.HasIssues())
+
+	assert.Contains(t, report.Issues, translator.Issue{
+		Path:   "catalogs[0].beckn:items[0].beckn:itemAttributes.vendorSpecificFlag",
+		Reason: translator.ReasonUnmapped,
+	})
+	assert.Contains(t, report.Issues, translator.Issue{
+		Path:   "catalogs[0].beckn:items[1].beckn:itemAttributes.maxPowerKW",
+		Reason: translator.ReasonInvalid,
+		Detail: "expected number, got string",
+	})
+	assert.Equal(t, 1, report.Count(translator.ReasonUnmapped), "JSON-LD keys are not reported")
+	assert.Equal(t, 1, report.Count(translator.ReasonInvalid))
+	assert.Equal(t, 0, report.Count(translator.ReasonMissing))
+}
+
+func TestDiscoveryToCatalogs_ReportsMissingFields(t *testing.T) {
+	resp := &models.DiscoveryResponse{
+		Message: models.Message{
+			Catalogs: []models.Catalog{
+				{
+					Provider: models.Provider{ID: "cpo-1"},
+					Items: []models.Item{
+						{ID: "item-1", ItemAttributes: map[string]interface{}{"connectorType": "TYPE_2"}},
+					},
+				},
+			},
+		},
+	}
+
+	catalogs, report := translator.DiscoveryToCatalogs(resp)
+	require.Len(t, catalogs, 1)
+	assert.Equal(t, "cpo-1", catalogs[0].ID, "catalog id defaults to provider id")
+
+	missing := map[string]bool{}
+	for _, i := range report.Issues {
+		if i.Reason == translator.ReasonMissing {
+			missing[i.Path] = true
+		}
+	}
+	assert.True(t, missing["catalogs[0].beckn:id"])
+	assert.True(t, missing["catalogs[0].beckn:items[0].beckn:itemAttributes.maxPowerKW"])
+	assert.True(t, missing["catalogs[0].beckn:items[0].beckn:itemAttributes.powerType"])
+	assert.True(t, missing["catalogs[0].beckn:items[0].beckn:itemAttributes.status"])
+	assert.True(t, missing["catalogs[0].beckn:items[*].beckn:availableAt.geo"])
+	assert.False(t, missing["catalogs[0].beckn:items[0].beckn:itemAttributes.connectorType"])
+}
+
+func TestDiscoveryToCatalogs_Nil(t *testing.T) {
+	catalogs, report := translator.DiscoveryToCatalogs(nil)
+	assert.Empty(t, catalogs)
+	assert.False(t, report.HasIssues())
+}
+
+func TestDiscoveryToCatalogs_RejectsNonFiniteNumbers(t *testing.T) {
+	resp := &models.DiscoveryResponse{
+		Message: models.Message{
+			Catalogs: []models.Catalog{
+				{
+					ID:       "cat-1",
+					Provider: models.Provider{ID: "cpo-1"},
+					Items: []models.Item{
+						{ID: "item-1", ItemAttributes: map[string]interface{}{"maxPowerKW": "NaN", "minPowerKW": "-Inf"}},
+					},
+				},
+			},
+		},
+	}
+
+	catalogs, report := translator.DiscoveryToCatalogs(resp)
+	require.Len(t, catalogs, 1)
+	require.Len(t, catalogs[0].Connectors, 1)
+	assert.Zero(t, catalogs[0].Connectors[0].ConnectorAttributes.MaxPowerKW)
+	assert.Zero(t, catalogs[0].Connectors[0].ConnectorAttributes.MinPowerKW)
+
+	invalid := map[string]bool{}
+	for _, i := range report.Issues {
+		if i.Reason == translator.ReasonInvalid {
+			invalid[i.Path] = true
+		}
+	}
+	assert.True(t, invalid["catalogs[0].beckn:items[0].beckn:itemAttributes.maxPowerKW"])
+	assert.True(t, invalid["catalogs[0].beckn:items[0].beckn:itemAttributes.minPowerKW"])
+}
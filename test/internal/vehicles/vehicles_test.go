@@ -0,0 +1,148 @@
+package vehicles_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/model"
+	"bff-go-mvp/internal/vehicles"
+)
+
+// flat is a vehicle that charges at up to 40 kW DC until 80%, then tapers
+// linearly to 10 kW at 100%.
+var flat = model.VehicleSpec{
+	ID:               "test-car",
+	Type:             "4-wheeler",
+	UsableBatteryKWh: 50,
+	Connectors:       []string{"CCS2"},
+	MaxACPowerKW:     7,
+	MaxDCPowerKW:     40,
+	ChargingCurve: []model.ChargingCurvePoint{
+		{SoC: 0, PowerKW: 100},
+		{SoC: 80, PowerKW: 100},
+		{SoC: 100, PowerKW: 10},
+	},
+}
+
+func TestDefault_LooksUpKnownVehiclesAndTypeDefaults(t *testing.T) {
+	r := vehicles.Default()
+
+	spec, err := r.Lookup(model.Vehicle{Make: "tata", Model: "nexon  ev", Type: "4-wheeler"})
+	require.NoError(t, err)
+	assert.Equal(t, "tata-nexon-ev", spec.ID)
+
+	for vehicleType, id := range map[string]string{
+		"2-wheeler": "default-2-wheeler",
+		"3-Wheeler": "default-3-wheeler",
+		"4-wheeler": "default-4-wheeler",
+		"":          "default-4-wheeler",
+	} {
+		spec, err := r.Lookup(model.Vehicle{Make: "Unknown", Model: "Prototype", Type: vehicleType})
+		require.NoError(t, err, vehicleType)
+		assert.Equal(t, id, spec.ID, vehicleType)
+	}
+
+	_, err = r.Lookup(model.Vehicle{Type: "bus"})
+	require.Error(t, err)
+	assert.True(t, apperror.IsKind(err, apperror.KindValidation))
+	assert.Equal(t, "vehicle.type", apperror.From(err).Details["field"])
+}
+
+func TestRegistry_List(t *testing.T) {
+	r := vehicles.Default()
+
+	all := r.List("", "")
+	require.NotEmpty(t, all)
+	// Ordered by make, then model, ignoring case.
+	for i := 1; i < len(all); i++ {
+		prev, cur := all[i-1], all[i]
+		assert.LessOrEqual(t, strings.ToLower(prev.Make+" "+prev.Model), strings.ToLower(cur.Make+" "+cur.Model))
+	}
+
+	for _, v := range r.List("TATA", "") {
+		assert.Equal(t, "Tata", v.Make)
+	}
+	for _, v := range r.List("", "2-wheeler") {
+		assert.Equal(t, "2-wheeler", v.Type)
+	}
+	assert.Empty(t, r.List("Nobody", ""))
+}
+
+func TestParse_RejectsInvalidRegistries(t *testing.T) {
+	tests := map[string]string{
+		"malformed":       `{`,
+		"missing default": `{"defaults": [], "vehicles": []}`,
+		"no battery": `{"defaults": [
+			{"id": "d2", "type": "2-wheeler", "connectors": ["BHARAT_AC_001"], "maxACPowerKW": 3, "chargingCurve": [{"soc": 0, "powerKW": 3}]}
+		]}`,
+		"unordered curve": `{"defaults": [
+			{"id": "d2", "type": "2-wheeler", "usableBatteryKWh": 3, "connectors": ["BHARAT_AC_001"], "maxACPowerKW": 3,
+			 "chargingCurve": [{"soc": 50, "powerKW": 3}, {"soc": 10, "powerKW": 3}]}
+		]}`,
+	}
+
+	for name, data := range tests {
+		_, err := vehicles.Parse([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestCharge_FollowsTheChargingCurve(t *testing.T) {
+	c, err := vehicles.NewCharge(flat, 60, true, 20)
+	require.NoError(t, err)
+
+	// 20% to 80% is 30 kWh at the 40 kW DC limit: 45 minutes.
+	assert.InDelta(t, 45, c.Minutes(30), 0.01)
+	assert.InDelta(t, 30, c.Energy(45), 0.001)
+	assert.Equal(t, 40.0, c.MaxEnergy())
+	assert.InDelta(t, 60, c.SoCGain(30), 1e-9)
+
+	// Above 80% the curve falls below the DC limit, so the last 10 kWh take
+	// longer than 15 minutes, and minutes beyond a full battery add nothing.
+	full := c.Minutes(40)
+	assert.Greater(t, full, 60.0)
+	assert.Equal(t, 40.0, c.Energy(full+30))
+	assert.Equal(t, full, c.Minutes(45))
+}
+
+func TestCharge_UsesTheLowestPowerLimit(t *testing.T) {
+	ac, err := vehicles.NewCharge(flat, 22, false, 0)
+	require.NoError(t, err)
+	// 7 kW AC: 7 kWh in an hour.
+	assert.InDelta(t, 60, ac.Minutes(7), 0.01)
+
+	slow, err := vehicles.NewCharge(flat, 20, true, 0)
+	require.NoError(t, err)
+	// The 20 kW connector is below the 40 kW vehicle limit.
+	assert.InDelta(t, 30, slow.Minutes(10), 0.01)
+}
+
+func TestNewCharge_RejectsImpossibleSessions(t *testing.T) {
+	noDC := flat
+	noDC.MaxDCPowerKW = 0
+
+	tests := map[string]struct {
+		spec      model.VehicleSpec
+		connector float64
+		dc        bool
+		soc       float64
+		field     string
+	}{
+		"full battery":    {flat, 60, true, 100, "state_of_charge"},
+		"negative charge": {flat, 60, true, -5, "state_of_charge"},
+		"no DC inlet":     {noDC, 60, true, 20, "connector_id"},
+		"unrated power":   {flat, 0, true, 20, "connector_id"},
+	}
+
+	for name, tt := range tests {
+		_, err := vehicles.NewCharge(tt.spec, tt.connector, tt.dc, tt.soc)
+
+		require.Error(t, err, name)
+		assert.True(t, apperror.IsKind(err, apperror.KindValidation), name)
+		assert.Equal(t, tt.field, apperror.From(err).Details["field"], name)
+	}
+}
@@ -0,0 +1,78 @@
+package webhook_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bff-go-mvp/internal/apperror"
+	"bff-go-mvp/internal/webhook"
+)
+
+func TestVerifier(t *testing.T) {
+	v := webhook.NewVerifier(map[string]string{"fakepsp": "s3cret"}, 5*time.Minute)
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Now()
+
+	assert.NoError(t, v.Verify("fakepsp", webhook.Sign("s3cret", now, body), body))
+	assert.NoError(t, v.Verify("fakepsp", webhook.Sign("s3cret", now.Add(-4*time.Minute), body), body))
+
+	for name, tc := range map[string]struct {
+		provider, header string
+		body             []byte
+		kind             apperror.Kind
+		code             string
+	}{
+		"unknown provider": {"acme", webhook.Sign("s3cret", now, body), body, apperror.KindNotFound, "NOT_FOUND"},
+		"missing header":   {"fakepsp", "", body, apperror.KindUnauthorized, webhook.CodeInvalidSignature},
+		"wrong secret":     {"fakepsp", webhook.Sign("other", now, body), body, apperror.KindUnauthorized, webhook.CodeInvalidSignature},
+		"tampered body":    {"fakepsp", webhook.Sign("s3cret", now, body), []byte(`{"id":"evt_2"}`), apperror.KindUnauthorized, webhook.CodeInvalidSignature},
+		"stale":            {"fakepsp", webhook.Sign("s3cret", now.Add(-6*time.Minute), body), body, apperror.KindUnauthorized, webhook.CodeStaleEvent},
+		"from the future":  {"fakepsp", webhook.Sign("s3cret", now.Add(6*time.Minute), body), body, apperror.KindUnauthorized, webhook.CodeStaleEvent},
+	} {
+		err := v.Verify(tc.provider, tc.header, tc.body)
+		if assert.Error(t, err, name) {
+			assert.Equal(t, tc.kind, apperror.From(err).Kind, name)
+			assert.Equal(t, tc.code, apperror.From(err).Code, name)
+		}
+	}
+}
+
+func TestDeduper(t *testing.T) {
+	d := webhook.NewDeduper(time.Hour)
+
+	assert.Equal(t, webhook.New, d.Begin("fakepsp:evt_1"))
+	assert.Equal(t, webhook.InProgress, d.Begin("fakepsp:evt_1"))
+
+	// A failed event may be delivered again; a processed one is a duplicate.
+	d.Done("fakepsp:evt_1", false)
+	assert.Equal(t, webhook.New, d.Begin("fakepsp:evt_1"))
+	d.Done("fakepsp:evt_1", true)
+	assert.Equal(t, webhook.Duplicate, d.Begin("fakepsp:evt_1"))
+	assert.Equal(t, webhook.New, d.Begin("acme:evt_1"))
+}
+
+func TestDeduper_ForgetsEventsAfterRetention(t *testing.T) {
+	d := webhook.NewDeduper(50 * time.Millisecond)
+
+	require.Equal(t, webhook.New, d.Begin("fakepsp:evt_1"))
+	d.Done("fakepsp:evt_1", true)
+	time.Sleep(60 * time.Millisecond)
+	require.Equal(t, webhook.New, d.Begin("fakepsp:evt_2"))
+	d.Done("fakepsp:evt_2", true)
+
+	// evt_1 expired, evt_2 is still remembered.
+	assert.Equal(t, webhook.New, d.Begin("fakepsp:evt_1"))
+	assert.Equal(t, webhook.Duplicate, d.Begin("fakepsp:evt_2"))
+
+	// Processed again, evt_1 is remembered for a new retention period.
+	d.Done("fakepsp:evt_1", true)
+	assert.Equal(t, webhook.Duplicate, d.Begin("fakepsp:evt_1"))
+}
+
+func TestRetention_OutlastsSignatures(t *testing.T) {
+	assert.Equal(t, webhook.MinRetention, webhook.Retention(5*time.Minute))
+	assert.Equal(t, 96*time.Hour, webhook.Retention(48*time.Hour))
+}